@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Text scale and spacing apply on top of the chosen theme to every window:
+// entries, labels, menus and dialogs, and the presentation windows, where
+// the audience text can be made larger still for projectors.
+
+// Preference keys of the accessibility settings
+const (
+	prefFontScale         = "fontScale"
+	prefLineSpacing       = "lineSpacing"
+	prefPresentationScale = "presentationScale"
+)
+
+// Bounds of the text scale and spacing sliders
+const (
+	minFontScale   = 0.8
+	maxFontScale   = 2.5
+	minLineSpacing = 1.0
+	maxLineSpacing = 2.5
+)
+
+// accessibleTheme is a theme with its text scaled and its lines and padding
+// spaced out
+type accessibleTheme struct {
+	base    fyne.Theme
+	scale   float32
+	spacing float32
+}
+
+func (t *accessibleTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	return t.base.Color(name, variant)
+}
+
+func (t *accessibleTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return t.base.Font(style)
+}
+
+func (t *accessibleTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return t.base.Icon(name)
+}
+
+func (t *accessibleTheme) Size(name fyne.ThemeSizeName) float32 {
+	size := t.base.Size(name)
+	switch name {
+	case theme.SizeNameText, theme.SizeNameHeadingText, theme.SizeNameSubHeadingText,
+		theme.SizeNameCaptionText, theme.SizeNameInlineIcon:
+		return size * t.scale
+	case theme.SizeNameLineSpacing, theme.SizeNamePadding, theme.SizeNameInnerPadding:
+		return size * t.spacing
+	}
+	return size
+}
+
+// baseTheme is a theme without the accessibility settings applied
+func baseTheme(current fyne.Theme) fyne.Theme {
+	if accessible, ok := current.(*accessibleTheme); ok {
+		return accessible.base
+	}
+	return current
+}
+
+// setTheme applies a theme with the text scale and spacing of the settings
+func (c *Canvas) setTheme(base fyne.Theme) {
+	fyne.CurrentApp().Settings().SetTheme(&accessibleTheme{
+		base:    baseTheme(base),
+		scale:   float32(min(max(c.prefs.FloatWithFallback(prefFontScale, 1), minFontScale), maxFontScale)),
+		spacing: float32(min(max(c.prefs.FloatWithFallback(prefLineSpacing, 1), minLineSpacing), maxLineSpacing)),
+	})
+}
+
+// applyAccessibility applies the accessibility settings to the current
+// theme
+func (c *Canvas) applyAccessibility() {
+	c.setTheme(fyne.CurrentApp().Settings().Theme())
+}
+
+// presentationText scales the text of the audience window further than the
+// rest of the app
+func (c *Canvas) presentationText(content fyne.CanvasObject) fyne.CanvasObject {
+	scale := float32(c.prefs.FloatWithFallback(prefPresentationScale, 1))
+	if scale == 1 {
+		return content
+	}
+	return container.NewThemeOverride(content, &accessibleTheme{
+		base:    fyne.CurrentApp().Settings().Theme(),
+		scale:   scale,
+		spacing: 1,
+	})
+}
+
+// accessibilitySetting edits the text scale, the spacing and the text scale
+// of presentations in the settings dialog
+func (c *Canvas) accessibilitySetting() fyne.CanvasObject {
+	slider := func(key string, min, max float64, format string, apply func()) fyne.CanvasObject {
+		value := widget.NewLabel("")
+		s := widget.NewSlider(min, max)
+		s.Step = 0.1
+		s.SetValue(c.prefs.FloatWithFallback(key, 1))
+		value.SetText(fmt.Sprintf(format, s.Value*100))
+		s.OnChanged = func(v float64) {
+			value.SetText(fmt.Sprintf(format, v*100))
+		}
+		s.OnChangeEnded = func(v float64) {
+			c.prefs.SetFloat(key, v)
+			if apply != nil {
+				apply()
+			}
+		}
+		return container.NewBorder(nil, nil, nil, value, s)
+	}
+	return container.NewVBox(
+		widget.NewLabel("Text size"),
+		slider(prefFontScale, minFontScale, maxFontScale, "%.0f%%", c.applyAccessibility),
+		widget.NewLabel("Line spacing"),
+		slider(prefLineSpacing, minLineSpacing, maxLineSpacing, "%.0f%%", c.applyAccessibility),
+		widget.NewLabel("Presentation text size"),
+		slider(prefPresentationScale, 1, maxFontScale*2, "%.0f%%", nil),
+	)
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// prefAgenda stores the workshop agenda and the log of its last run
+const prefAgenda = "agenda"
+
+// AgendaItem is a timed workshop activity on a canvas file, or on a blank
+// canvas of a template when no file is set
+type AgendaItem struct {
+	Title      string `json:"title"`
+	CanvasURI  string `json:"canvasURI,omitempty"`
+	CanvasType string `json:"canvasType"`
+	Minutes    int    `json:"minutes"`
+}
+
+// AgendaOutcome records how an agenda item went when the agenda was run
+type AgendaOutcome struct {
+	Item     string        `json:"item"`
+	Planned  int           `json:"planned"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+	Notes    string        `json:"notes"`
+}
+
+// Agenda sequences activities across canvases and templates, with the
+// outcomes and attendance of its last run
+type Agenda struct {
+	Items      []AgendaItem    `json:"items"`
+	Outcomes   []AgendaOutcome `json:"outcomes,omitempty"`
+	Attendance []Attendance    `json:"attendance,omitempty"`
+}
+
+func (c *Canvas) loadAgenda() Agenda {
+	var agenda Agenda
+	if stored := c.prefs.String(prefAgenda); stored != "" {
+		json.Unmarshal([]byte(stored), &agenda)
+	}
+	return agenda
+}
+
+func (c *Canvas) saveAgenda(agenda Agenda) {
+	stored, err := json.Marshal(agenda)
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	c.prefs.SetString(prefAgenda, string(stored))
+}
+
+// describe summarizes an agenda item for the builder list
+func (item AgendaItem) describe() string {
+	source := findCanvasType(item.CanvasType).Name
+	if uri, err := storage.ParseURI(item.CanvasURI); err == nil && item.CanvasURI != "" {
+		source = uri.Name()
+	}
+	return fmt.Sprintf("%s — %s, %d min", item.Title, source, item.Minutes)
+}
+
+// showAgendaBuilder lets a facilitator sequence timed activities across
+// canvases and templates, and run the agenda
+func (c *Canvas) showAgendaBuilder() {
+	agenda := c.loadAgenda()
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(agenda.Items) },
+		func() fyne.CanvasObject {
+			up := widget.NewButtonWithIcon("", theme.MoveUpIcon(), nil)
+			down := widget.NewButtonWithIcon("", theme.MoveDownIcon(), nil)
+			remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
+			return container.NewBorder(nil, nil, nil, container.NewHBox(up, down, remove), widget.NewLabel("Template"))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%d. %s", id+1, agenda.Items[id].describe()))
+			buttons := row.Objects[1].(*fyne.Container).Objects
+			buttons[0].(*widget.Button).OnTapped = func() {
+				if id > 0 {
+					agenda.Items[id-1], agenda.Items[id] = agenda.Items[id], agenda.Items[id-1]
+					c.saveAgenda(agenda)
+					list.Refresh()
+				}
+			}
+			buttons[1].(*widget.Button).OnTapped = func() {
+				if id < len(agenda.Items)-1 {
+					agenda.Items[id], agenda.Items[id+1] = agenda.Items[id+1], agenda.Items[id]
+					c.saveAgenda(agenda)
+					list.Refresh()
+				}
+			}
+			buttons[2].(*widget.Button).OnTapped = func() {
+				agenda.Items = append(agenda.Items[:id], agenda.Items[id+1:]...)
+				c.saveAgenda(agenda)
+				list.Refresh()
+			}
+		},
+	)
+
+	// New activity form
+	titleEntry := widget.NewEntry()
+	titleEntry.SetPlaceHolder("Activity, e.g. Map the value proposition")
+	minutesEntry := widget.NewEntry()
+	minutesEntry.SetText("30")
+
+	var typeNames []string
+	for _, kind := range canvasTypes {
+		typeNames = append(typeNames, kind.Name)
+	}
+	typeSelect := widget.NewSelect(typeNames, nil)
+	typeSelect.SetSelected(findCanvasType(c.canvasTypeID).Name)
+
+	var canvasURI string
+	canvasLabel := widget.NewLabel("Blank canvas")
+	chooseCanvas := widget.NewButton("Choose...", func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			data, err := readCanvasData(reader)
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			canvasURI = reader.URI().String()
+			canvasLabel.SetText(reader.URI().Name())
+			typeSelect.SetSelected(data.canvasType().Name)
+		}, c.window)
+	})
+	clearCanvas := widget.NewButton("Blank", func() {
+		canvasURI = ""
+		canvasLabel.SetText("Blank canvas")
+	})
+
+	add := widget.NewButtonWithIcon("Add Activity", theme.ContentAddIcon(), func() {
+		title := strings.TrimSpace(titleEntry.Text)
+		if title == "" {
+			dialog.ShowError(fmt.Errorf("activity title is required"), c.window)
+			return
+		}
+		minutes, err := strconv.Atoi(strings.TrimSpace(minutesEntry.Text))
+		if err != nil || minutes <= 0 {
+			dialog.ShowError(fmt.Errorf("minutes must be a positive number"), c.window)
+			return
+		}
+
+		item := AgendaItem{Title: title, CanvasURI: canvasURI, Minutes: minutes}
+		for _, kind := range canvasTypes {
+			if kind.Name == typeSelect.Selected {
+				item.CanvasType = kind.ID
+			}
+		}
+		agenda.Items = append(agenda.Items, item)
+		c.saveAgenda(agenda)
+		list.Refresh()
+		titleEntry.SetText("")
+	})
+
+	form := &widget.Form{Items: []*widget.FormItem{
+		widget.NewFormItem("Activity", titleEntry),
+		widget.NewFormItem("Template", typeSelect),
+		widget.NewFormItem("Canvas", container.NewBorder(nil, nil, nil, container.NewHBox(chooseCanvas, clearCanvas), canvasLabel)),
+		widget.NewFormItem("Minutes", minutesEntry),
+	}}
+
+	var builder dialog.Dialog
+	run := widget.NewButtonWithIcon("Run Agenda", theme.MediaPlayIcon(), func() {
+		if len(agenda.Items) == 0 {
+			dialog.ShowInformation("Agenda", "Add activities before running the agenda", c.window)
+			return
+		}
+		builder.Hide()
+		c.runAgenda(agenda)
+	})
+	lastRun := widget.NewButton("Last Run Log", func() {
+		c.showAgendaLog(c.loadAgenda())
+	})
+
+	content := container.NewBorder(nil, container.NewVBox(form, add, widget.NewSeparator(), container.NewHBox(run, lastRun)), nil, nil, list)
+	builder = dialog.NewCustom("Workshop Agenda", "Close", content, c.window)
+	builder.Resize(fyne.NewSize(600, 550))
+	builder.Show()
+}
+
+// openAgendaItem loads the canvas of an agenda item into the editor
+func (c *Canvas) openAgendaItem(item AgendaItem) error {
+	data := CanvasData{CanvasType: item.CanvasType}
+	if item.CanvasURI != "" {
+		uri, err := parseLocation(item.CanvasURI)
+		if err != nil {
+			return err
+		}
+		data, err = readCanvasURI(uri)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.undoStack = append(c.undoStack, c.getCurrentData())
+	c.setCurrentData(data)
+	c.resetSectionEdits(data.SectionEdited, data.SectionEditors)
+	c.resetSectionCRDT(data.CRDT)
+	c.updateProgress()
+	return nil
+}
+
+// closeAgendaItem keeps the work of an agenda item: a version is saved and
+// a canvas file is written back. The version created at session end carries
+// the attendance log.
+func (c *Canvas) closeAgendaItem(item AgendaItem, attendance []Attendance) error {
+	c.saveCurrentVersion()
+	c.versions[len(c.versions)-1].Attendance = attendance
+	if item.CanvasURI == "" {
+		return nil
+	}
+
+	uri, err := parseLocation(item.CanvasURI)
+	if err != nil {
+		return err
+	}
+	jsonData, err := json.MarshalIndent(c.canvasFileData(), "", "    ")
+	if err != nil {
+		return err
+	}
+	return writeURI(uri, jsonData)
+}
+
+// runAgenda steps through the agenda in facilitation mode: each activity
+// opens its canvas with a countdown, and its notes and actual duration are
+// logged when the facilitator moves on. Participants check in and out for
+// the attendance log.
+func (c *Canvas) runAgenda(agenda Agenda) {
+	panel := fyne.CurrentApp().NewWindow("Facilitation")
+
+	step := 0
+	started := time.Now()
+	var outcomes []AgendaOutcome
+	attendance := &attendanceTracker{}
+
+	stepLabel := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	timerLabel := widget.NewLabel("")
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.SetPlaceHolder("Outcomes and decisions of this activity")
+
+	showStep := func() {
+		item := agenda.Items[step]
+		stepLabel.SetText(fmt.Sprintf("Step %d of %d: %s", step+1, len(agenda.Items), item.Title))
+		notesEntry.SetText("")
+		started = time.Now()
+		if err := c.openAgendaItem(item); err != nil {
+			dialog.ShowError(err, c.window)
+		}
+	}
+
+	// Countdown of the current activity, overtime is shown as negative
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				planned := time.Duration(agenda.Items[step].Minutes) * time.Minute
+				remaining := (planned - time.Since(started)).Round(time.Second)
+				timerLabel.SetText("Remaining: " + remaining.String())
+			}
+		}
+	}()
+
+	var next, end *widget.Button
+	finishStep := func(attendance []Attendance) {
+		item := agenda.Items[step]
+		outcomes = append(outcomes, AgendaOutcome{
+			Item:     item.Title,
+			Planned:  item.Minutes,
+			Started:  started,
+			Duration: time.Since(started).Round(time.Second),
+			Notes:    notesEntry.Text,
+		})
+		if err := c.closeAgendaItem(item, attendance); err != nil {
+			dialog.ShowError(err, c.window)
+		}
+	}
+	finish := func() {
+		close(stop)
+		agenda.Attendance = attendance.log()
+		finishStep(agenda.Attendance)
+		agenda.Outcomes = outcomes
+		c.saveAgenda(agenda)
+		panel.Close()
+		c.showAgendaLog(agenda)
+	}
+
+	next = widget.NewButtonWithIcon("Next", theme.MediaSkipNextIcon(), func() {
+		if step == len(agenda.Items)-1 {
+			finish()
+			return
+		}
+		finishStep(nil)
+		step++
+		if step == len(agenda.Items)-1 {
+			next.SetText("Finish")
+		}
+		showStep()
+	})
+	end = widget.NewButtonWithIcon("End Session", theme.MediaStopIcon(), func() {
+		finish()
+	})
+	if len(agenda.Items) == 1 {
+		next.SetText("Finish")
+	}
+
+	panel.SetContent(container.NewBorder(
+		container.NewVBox(stepLabel, timerLabel),
+		container.NewHBox(next, end),
+		nil, nil,
+		container.NewAppTabs(
+			container.NewTabItem("Notes", notesEntry),
+			container.NewTabItem("Attendance", attendance.createPanel()),
+		),
+	))
+	panel.SetCloseIntercept(func() {
+		end.OnTapped()
+	})
+	panel.Resize(fyne.NewSize(450, 400))
+	showStep()
+	panel.Show()
+}
+
+// showAgendaLog lists the outcomes and attendance of the last agenda run
+func (c *Canvas) showAgendaLog(agenda Agenda) {
+	if len(agenda.Outcomes) == 0 {
+		dialog.ShowInformation("Agenda Log", "The agenda has not been run yet", c.window)
+		return
+	}
+
+	var log strings.Builder
+	for _, outcome := range agenda.Outcomes {
+		fmt.Fprintf(&log, "%s  %s (%s of %d min)\n", formatStamp(outcome.Started), outcome.Item, outcome.Duration, outcome.Planned)
+		if outcome.Notes != "" {
+			fmt.Fprintf(&log, "    %s\n", strings.ReplaceAll(outcome.Notes, "\n", "\n    "))
+		}
+	}
+
+	if len(agenda.Attendance) > 0 {
+		fmt.Fprintf(&log, "\nAttendance: %s\n", attendanceSummary(agenda.Attendance))
+	}
+
+	logText := widget.NewLabel(log.String())
+	logText.Wrapping = fyne.TextWrapWord
+	logDialog := dialog.NewCustom("Agenda Log", "Close", container.NewVScroll(logText), c.window)
+	logDialog.Resize(fyne.NewSize(600, 400))
+	logDialog.Show()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+// flashcard is a question about the canvas and the answer from its content
+type flashcard struct {
+	Question string
+	Answer   string
+	Tag      string
+}
+
+// canvasFlashcards turns every filled block into a card asking the block's
+// prompt, answered with the block's content
+func canvasFlashcards(data CanvasData) []flashcard {
+	kind := data.canvasType()
+	prompts := append([]string(nil), kind.Prompts...)
+	for _, custom := range data.CustomSections {
+		prompts = append(prompts, custom.Prompt)
+	}
+
+	var cards []flashcard
+	for i, section := range data.sections() {
+		if strings.TrimSpace(section.Text) == "" {
+			continue
+		}
+		question := section.Title
+		if i < len(prompts) && prompts[i] != "" {
+			question = section.Title + ": " + prompts[i]
+		}
+		cards = append(cards, flashcard{
+			Question: question,
+			Answer:   section.Text,
+			Tag:      strings.Join(strings.Fields(section.Title), "_"),
+		})
+	}
+	return cards
+}
+
+// writeAnki writes flashcards as an Anki text import: tab separated with
+// HTML fields and a header naming the deck
+func writeAnki(w io.Writer, deck string, cards []flashcard) error {
+	field := func(text string) string {
+		return strings.ReplaceAll(html.EscapeString(strings.TrimSpace(text)), "\n", "<br>")
+	}
+	var b strings.Builder
+	b.WriteString("#separator:tab\n#html:true\n#notetype:Basic\n")
+	fmt.Fprintf(&b, "#deck:%s\n#tags column:3\n", deck)
+	for _, card := range cards {
+		fmt.Fprintf(&b, "%s\t%s\t%s\n", field(card.Question), field(card.Answer), card.Tag)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// exportFlashcards saves the canvas as an Anki deck for pitch practice
+func (c *Canvas) exportFlashcards() {
+	data := c.getCurrentData()
+	cards := canvasFlashcards(data)
+	if len(cards) == 0 {
+		dialog.ShowInformation("Export Flashcards", "Fill in some blocks first, every filled block becomes a card", c.window)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		if err := writeAnki(writer, data.canvasType().Name+" Pitch Practice", cards); err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+
+		dialog.ShowInformation("Success", fmt.Sprintf("%d flashcards have been exported, import the file in Anki with File > Import", len(cards)), c.window)
+	}, c.window)
+	saveDialog.SetFileName("pitch-flashcards.txt")
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".txt"}))
+	saveDialog.Show()
+}
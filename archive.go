@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+// exportHistory writes every saved version as a timestamped JSON and PDF
+// file to a chosen folder for audit and archiving
+func (c *Canvas) exportHistory() {
+	if len(c.versions) == 0 {
+		dialog.ShowInformation("Export History", "No previous versions found", c.window)
+		return
+	}
+
+	opts, err := c.pdfOptions()
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	versions := c.versions
+
+	dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if dir == nil {
+			return
+		}
+
+		for _, version := range versions {
+			versionOpts := opts
+			if opts.Comments != nil {
+				versionOpts.Comments = version.Comments
+			}
+			if err := writeVersionFiles(dir, versionOpts, version); err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+		}
+
+		dialog.ShowInformation("Success", fmt.Sprintf("%d versions have been exported successfully", len(versions)), c.window)
+	}, c.window)
+}
+
+// versionFileName names the archive files of a version by timestamp, with
+// the start of the ID keeping versions saved in the same second apart
+func versionFileName(version Version) string {
+	id := version.ID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return "canvas-" + version.Timestamp.UTC().Format("2006-01-02-150405") + "-" + id
+}
+
+// writeVersionFiles writes the JSON and PDF files of a version to a folder
+func writeVersionFiles(dir fyne.URI, opts pdfOptions, version Version) error {
+	name := versionFileName(version)
+
+	versionJSON, err := json.MarshalIndent(version, "", "    ")
+	if err != nil {
+		return err
+	}
+	err = writeArchiveFile(dir, name+".json", func(w io.Writer) error {
+		_, err := w.Write(versionJSON)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeArchiveFile(dir, name+".pdf", func(w io.Writer) error {
+		return writeCanvasPDF(w, opts, version.Data)
+	})
+}
+
+func writeArchiveFile(dir fyne.URI, name string, write func(io.Writer) error) error {
+	uri, err := storage.Child(dir, name)
+	if err != nil {
+		return err
+	}
+	var content bytes.Buffer
+	if err := write(&content); err != nil {
+		return err
+	}
+	return writeURI(uri, content.Bytes())
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Attendance records a participant of a facilitation session
+type Attendance struct {
+	Name     string        `json:"name"`
+	Joined   time.Time     `json:"joined"`
+	Left     time.Time     `json:"left"`
+	Duration time.Duration `json:"duration"`
+}
+
+// attendanceTracker records participants checking in and out of a session
+type attendanceTracker struct {
+	entries []Attendance
+}
+
+func (t *attendanceTracker) checkIn(name string) {
+	t.entries = append(t.entries, Attendance{Name: name, Joined: nowUTC()})
+}
+
+func (t *attendanceTracker) checkOut(index int) {
+	entry := &t.entries[index]
+	if !entry.Left.IsZero() {
+		return
+	}
+	entry.Left = nowUTC()
+	entry.Duration = entry.Left.Sub(entry.Joined).Round(time.Second)
+}
+
+// log checks out everyone still present and returns the attendance log
+func (t *attendanceTracker) log() []Attendance {
+	for i := range t.entries {
+		t.checkOut(i)
+	}
+	return t.entries
+}
+
+// createPanel shows the check-in form and the participants of the session
+func (t *attendanceTracker) createPanel() fyne.CanvasObject {
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(t.entries) },
+		func() fyne.CanvasObject {
+			checkOut := widget.NewButtonWithIcon("", theme.LogoutIcon(), nil)
+			return container.NewBorder(nil, nil, nil, checkOut, widget.NewLabel("Template"))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			entry := t.entries[id]
+			status := "joined " + formatClock(entry.Joined)
+			if !entry.Left.IsZero() {
+				status = fmt.Sprintf("%s–%s, %s", formatClock(entry.Joined), formatClock(entry.Left), entry.Duration)
+			}
+			row.Objects[0].(*widget.Label).SetText(entry.Name + " (" + status + ")")
+
+			checkOut := row.Objects[1].(*widget.Button)
+			checkOut.OnTapped = func() {
+				t.checkOut(id)
+				list.Refresh()
+			}
+			if entry.Left.IsZero() {
+				checkOut.Enable()
+			} else {
+				checkOut.Disable()
+			}
+		},
+	)
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Participant name")
+	checkIn := func() {
+		name := strings.TrimSpace(nameEntry.Text)
+		if name == "" {
+			return
+		}
+		t.checkIn(name)
+		nameEntry.SetText("")
+		list.Refresh()
+	}
+	nameEntry.OnSubmitted = func(string) { checkIn() }
+
+	return container.NewBorder(
+		container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("Check In", theme.LoginIcon(), checkIn), nameEntry),
+		nil, nil, nil,
+		list,
+	)
+}
+
+// attendanceSummary describes an attendance log in one line
+func attendanceSummary(attendance []Attendance) string {
+	var names []string
+	for _, entry := range attendance {
+		names = append(names, fmt.Sprintf("%s (%s)", entry.Name, entry.Duration))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// attributeEdit records who last edited a section, from the user profile
+// for local edits and from the session for edits of collaborators
+func (c *Canvas) attributeEdit(section, editor string) {
+	if editor == "" {
+		return
+	}
+	c.collabMu.Lock()
+	c.sectionEditors[section] = editor
+	c.collabMu.Unlock()
+	c.refreshLastEdit()
+}
+
+// attribution describes the last edit of a section, e.g. "Last edited by
+// Ana at 2024-05-02 14:30", empty when nothing is known about it
+func (c *Canvas) attribution(section string) string {
+	c.collabMu.Lock()
+	edited := c.sectionEdited[section]
+	editor := c.sectionEditors[section]
+	c.collabMu.Unlock()
+	switch {
+	case editor != "" && !edited.IsZero():
+		return fmt.Sprintf("Last edited by %s at %s", editor, formatStamp(edited))
+	case !edited.IsZero():
+		return "Last edited at " + formatStamp(edited)
+	case editor != "":
+		return "Last edited by " + editor
+	}
+	return ""
+}
+
+// refreshLastEdit shows the most recent edit of the canvas in the status bar
+func (c *Canvas) refreshLastEdit() {
+	if c.lastEditLabel == nil {
+		return
+	}
+	var latest, editor string
+	var at time.Time
+	c.collabMu.Lock()
+	for section, edited := range c.sectionEdited {
+		if edited.After(at) {
+			latest, at = section, edited
+		}
+	}
+	editor = c.sectionEditors[latest]
+	c.collabMu.Unlock()
+	if latest == "" {
+		c.lastEditLabel.SetText("")
+		return
+	}
+	text := latest + " edited " + formatClock(at)
+	if editor != "" {
+		text = fmt.Sprintf("%s edited by %s at %s", latest, editor, formatClock(at))
+	}
+	c.lastEditLabel.SetText(text)
+}
+
+// sectionEditorsData copies the editors for saving
+func (c *Canvas) sectionEditorsData() map[string]string {
+	c.collabMu.Lock()
+	defer c.collabMu.Unlock()
+	if len(c.sectionEditors) == 0 {
+		return nil
+	}
+	editors := make(map[string]string, len(c.sectionEditors))
+	for section, editor := range c.sectionEditors {
+		editors[section] = editor
+	}
+	return editors
+}
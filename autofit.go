@@ -0,0 +1,141 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// prefAutoFit scales the text of each section so its content fits without
+// scrolling, like looking at a physical canvas
+const prefAutoFit = "autoFit"
+
+// minAutoFitTextSize is the smallest text size auto-fit shrinks to
+const minAutoFitTextSize = 8
+
+// textSizeTheme is the app theme with a different text size
+type textSizeTheme struct {
+	size float32
+}
+
+func (t *textSizeTheme) current() fyne.Theme {
+	return fyne.CurrentApp().Settings().Theme()
+}
+
+func (t *textSizeTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	return t.current().Color(name, variant)
+}
+
+func (t *textSizeTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return t.current().Font(style)
+}
+
+func (t *textSizeTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return t.current().Icon(name)
+}
+
+func (t *textSizeTheme) Size(name fyne.ThemeSizeName) float32 {
+	if name == theme.SizeNameText && t.size > 0 {
+		return t.size
+	}
+	return t.current().Size(name)
+}
+
+// autoFitEntry lays out a section entry and shrinks its text to fit the
+// space it is given whenever that space or the text changes
+type autoFitEntry struct {
+	canvas   *Canvas
+	entry    *widget.Entry
+	theme    *textSizeTheme
+	override *container.ThemeOverride
+	size     fyne.Size
+}
+
+func (a *autoFitEntry) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	for _, object := range objects {
+		object.Move(fyne.NewPos(0, 0))
+		object.Resize(size)
+	}
+	if size != a.size {
+		a.size = size
+		a.fit()
+	}
+}
+
+func (a *autoFitEntry) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	return objects[0].MinSize()
+}
+
+// fit applies the text size at which the entry content fits, or the theme
+// text size when auto-fit is off
+func (a *autoFitEntry) fit() {
+	size := theme.TextSize()
+	if a.canvas.prefs.Bool(prefAutoFit) && a.size.Width > 0 && a.size.Height > 0 {
+		size = fittingTextSize(a.entry.Text, a.entry.Wrapping == fyne.TextWrapWord, a.size, size)
+	}
+	if size != a.theme.size {
+		a.theme.size = size
+		a.override.Refresh()
+	}
+}
+
+// fittingTextSize returns the largest text size up to largest at which the
+// text fits in the box, wrapped at words or with every line on one row
+func fittingTextSize(text string, wrap bool, box fyne.Size, largest float32) float32 {
+	padding := 2 * theme.InnerPadding()
+	width, height := box.Width-padding, box.Height-padding
+	lines := strings.Split(text, "\n")
+	for size := largest; size > minAutoFitTextSize; size -= 0.5 {
+		rows := 0
+		fits := true
+		for _, line := range lines {
+			lineWidth := fyne.MeasureText(line, size, fyne.TextStyle{}).Width
+			switch {
+			case wrap:
+				rows += max(int(math.Ceil(float64(lineWidth/width))), 1)
+			case lineWidth > width:
+				fits = false
+			default:
+				rows++
+			}
+		}
+		lineHeight := fyne.MeasureText("M", size, fyne.TextStyle{}).Height + theme.LineSpacing()
+		if fits && float32(rows)*lineHeight <= height {
+			return size
+		}
+	}
+	return minAutoFitTextSize
+}
+
+// autoFit wraps a section entry so its text size can follow its content
+func (c *Canvas) autoFit(entry *widget.Entry) fyne.CanvasObject {
+	fit := &autoFitEntry{canvas: c, entry: entry, theme: &textSizeTheme{}}
+	fit.override = container.NewThemeOverride(container.New(fit, entry), fit.theme)
+	c.autoFits[entry] = fit
+	return fit.override
+}
+
+// refitSection updates the text size of a section after its text changed
+func (c *Canvas) refitSection(entry *widget.Entry) {
+	if fit := c.autoFits[entry]; fit != nil {
+		fit.fit()
+	}
+}
+
+// autoFitSetting turns auto-fit on and off in the settings dialog
+func (c *Canvas) autoFitSetting() fyne.CanvasObject {
+	check := widget.NewCheck("Shrink text so each section fits without scrolling", func(checked bool) {
+		c.prefs.SetBool(prefAutoFit, checked)
+		for _, fit := range c.autoFits {
+			fit.fit()
+		}
+		c.setupMainMenu()
+	})
+	check.SetChecked(c.prefs.Bool(prefAutoFit))
+	return check
+}
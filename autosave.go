@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Preference keys of the significance threshold for auto-saved versions:
+// a version is only added once this many characters or list items changed
+// since the previous one
+const (
+	prefAutoSaveChars = "autoSaveChars"
+	prefAutoSaveItems = "autoSaveItems"
+
+	defaultAutoSaveChars = 40
+	defaultAutoSaveItems = 1
+)
+
+// canvasChange measures how much two canvases differ, as characters
+// changed and list items added or removed across all sections
+func canvasChange(from, to CanvasData) (chars, items int) {
+	fromText := make(map[string]string)
+	for _, section := range from.sections() {
+		fromText[section.Title] = section.Text
+	}
+	for _, section := range to.sections() {
+		old := fromText[section.Title]
+		chars += changedChars(old, section.Text)
+
+		count := make(map[string]int)
+		for _, line := range sectionLines(old) {
+			count[line]++
+		}
+		for _, line := range sectionLines(section.Text) {
+			count[line]--
+		}
+		for _, n := range count {
+			items += max(n, -n)
+		}
+	}
+	return chars, items
+}
+
+// autoSaveVersion saves a version on the auto-save tick when the canvas
+// changed significantly since the latest version of the branch. Smaller
+// changes are merged into that version when it was auto-saved as well, so
+// history stays readable, unless it is hashed: chained versions never
+// change.
+func (c *Canvas) autoSaveVersion() {
+	latest, ok := c.latestVersion(c.currentBranch())
+	if !ok {
+		c.saveCurrentVersion()
+		return
+	}
+
+	current := c.getCurrentData()
+	chars, items := canvasChange(latest.Data, current)
+	if chars == 0 && items == 0 {
+		return
+	}
+	minChars := c.prefs.IntWithFallback(prefAutoSaveChars, defaultAutoSaveChars)
+	minItems := c.prefs.IntWithFallback(prefAutoSaveItems, defaultAutoSaveItems)
+	last := &c.versions[len(c.versions)-1]
+	trivial := chars < minChars && (minItems == 0 || items < minItems)
+	if !trivial || last.ID != latest.ID || !autoSavedVersion(*last) || last.Author != c.userName() || last.Hash != "" {
+		c.saveCurrentVersion()
+		return
+	}
+
+	last.Data = current
+	last.Timestamp = nowUTC()
+	c.lastSaved = last.Timestamp
+	c.resetSnapshotBase()
+	c.updateProgress()
+}
+
+// autoSavedVersion reports whether a version was saved without the user
+// naming, tagging or describing it
+func autoSavedVersion(v Version) bool {
+	return v.Name == "" && len(v.Tags) == 0 && v.Note == "" && len(v.Attendance) == 0
+}
+
+// autoSaveSetting edits the significance threshold in the settings dialog
+func (c *Canvas) autoSaveSetting() fyne.CanvasObject {
+	charsEntry := c.intSettingEntry(prefAutoSaveChars, defaultAutoSaveChars)
+	itemsEntry := c.intSettingEntry(prefAutoSaveItems, defaultAutoSaveItems)
+	return container.NewVBox(
+		container.NewBorder(nil, nil, nil, widget.NewLabel("characters changed"), charsEntry),
+		container.NewBorder(nil, nil, widget.NewLabel("or"), widget.NewLabel("items added or removed"), itemsEntry),
+	)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// benchmarkBlock is the typical content of a block in an industry: how many
+// items it usually lists and elements it usually mentions
+type benchmarkBlock struct {
+	Items   int
+	Typical []string
+}
+
+// industryBenchmark is a reference Business Model Canvas for an industry,
+// blocks in reading order
+type industryBenchmark struct {
+	Industry string
+	Blocks   [9]benchmarkBlock
+}
+
+var industryBenchmarks = []industryBenchmark{
+	{
+		Industry: "Software as a Service",
+		Blocks: [9]benchmarkBlock{
+			{3, []string{"cloud", "integration", "reseller"}},
+			{4, []string{"development", "support", "marketing"}},
+			{3, []string{"platform", "engineers", "data"}},
+			{3, []string{"save", "automate", "integrat"}},
+			{3, []string{"self-service", "onboarding", "success"}},
+			{3, []string{"website", "app store", "sales"}},
+			{2, []string{"small", "enterprise"}},
+			{4, []string{"hosting", "salaries", "acquisition"}},
+			{2, []string{"subscription", "tier"}},
+		},
+	},
+	{
+		Industry: "Retail and E-commerce",
+		Blocks: [9]benchmarkBlock{
+			{3, []string{"supplier", "logistics", "payment"}},
+			{4, []string{"sourcing", "merchandising", "fulfil"}},
+			{3, []string{"inventory", "store", "brand"}},
+			{3, []string{"price", "selection", "convenien"}},
+			{3, []string{"loyalty", "service", "returns"}},
+			{3, []string{"online", "store", "social"}},
+			{2, []string{"shoppers", "age"}},
+			{4, []string{"inventory", "rent", "shipping", "staff"}},
+			{2, []string{"sales", "margin"}},
+		},
+	},
+	{
+		Industry: "Marketplace",
+		Blocks: [9]benchmarkBlock{
+			{3, []string{"payment", "seller", "insurance"}},
+			{4, []string{"matching", "trust", "platform"}},
+			{3, []string{"network", "platform", "reviews"}},
+			{4, []string{"buyers", "sellers", "trust"}},
+			{3, []string{"reviews", "support", "community"}},
+			{3, []string{"app", "search", "referral"}},
+			{2, []string{"buyers", "sellers"}},
+			{4, []string{"marketing", "development", "support"}},
+			{2, []string{"commission", "fee"}},
+		},
+	},
+	{
+		Industry: "Manufacturing",
+		Blocks: [9]benchmarkBlock{
+			{3, []string{"supplier", "distributor", "logistics"}},
+			{4, []string{"production", "quality", "procurement"}},
+			{3, []string{"plant", "machinery", "patent"}},
+			{3, []string{"quality", "reliab", "cost"}},
+			{2, []string{"account", "service"}},
+			{3, []string{"distributor", "direct", "dealer"}},
+			{2, []string{"b2b", "industr"}},
+			{4, []string{"materials", "labor", "equipment", "energy"}},
+			{2, []string{"product sales", "service", "spare"}},
+		},
+	},
+	{
+		Industry: "Professional Services",
+		Blocks: [9]benchmarkBlock{
+			{2, []string{"referral", "specialist"}},
+			{3, []string{"delivery", "business development", "training"}},
+			{3, []string{"expert", "reputation", "method"}},
+			{2, []string{"expertise", "result"}},
+			{2, []string{"personal", "account", "retainer"}},
+			{2, []string{"referral", "network", "event"}},
+			{2, []string{"companies", "executives"}},
+			{3, []string{"salaries", "travel", "office"}},
+			{2, []string{"fee", "retainer", "project"}},
+		},
+	},
+}
+
+// BenchmarkFinding flags a block that is unusually thin or missing typical
+// elements compared to an industry benchmark
+type BenchmarkFinding struct {
+	Section string
+	Items   int
+	Typical int
+	Missing []string
+}
+
+// compareToBenchmark checks the standard blocks of a Business Model Canvas
+// against a benchmark, returning only blocks that need attention
+func compareToBenchmark(data CanvasData, benchmark industryBenchmark) []BenchmarkFinding {
+	var findings []BenchmarkFinding
+	for i, section := range data.sections()[:len(benchmark.Blocks)] {
+		block := benchmark.Blocks[i]
+		text := strings.ToLower(section.Text)
+		finding := BenchmarkFinding{
+			Section: section.Title,
+			Items:   len(sectionLines(section.Text)),
+			Typical: block.Items,
+		}
+		for _, element := range block.Typical {
+			if !strings.Contains(text, element) {
+				finding.Missing = append(finding.Missing, element)
+			}
+		}
+
+		// Thin means fewer than half the typical number of items
+		if finding.Items*2 < block.Items || len(finding.Missing) == len(block.Typical) {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func benchmarkReport(benchmark industryBenchmark, findings []BenchmarkFinding) string {
+	if len(findings) == 0 {
+		return fmt.Sprintf("Your canvas is in line with the %s benchmark.", benchmark.Industry)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Compared to the %s benchmark:\n", benchmark.Industry)
+	for _, finding := range findings {
+		fmt.Fprintf(&b, "\n• %s: %d items (typically %d)", finding.Section, finding.Items, finding.Typical)
+		if len(finding.Missing) > 0 {
+			fmt.Fprintf(&b, "\n    Typical elements not mentioned: %s", strings.Join(finding.Missing, ", "))
+		}
+	}
+	return b.String()
+}
+
+// showBenchmarkComparison compares the canvas against an industry benchmark
+// chosen by the user
+func (c *Canvas) showBenchmarkComparison() {
+	if c.canvasTypeID != canvasTypeBusiness {
+		dialog.ShowInformation("Benchmark", "Industry benchmarks are available for the Business Model Canvas", c.window)
+		return
+	}
+
+	report := widget.NewLabel("Choose an industry to compare against")
+	report.Wrapping = fyne.TextWrapWord
+
+	var industries []string
+	for _, benchmark := range industryBenchmarks {
+		industries = append(industries, benchmark.Industry)
+	}
+	industrySelect := widget.NewSelect(industries, func(selected string) {
+		for _, benchmark := range industryBenchmarks {
+			if benchmark.Industry == selected {
+				report.SetText(benchmarkReport(benchmark, compareToBenchmark(c.getCurrentData(), benchmark)))
+			}
+		}
+	})
+
+	content := container.NewBorder(industrySelect, nil, nil, nil, container.NewVScroll(report))
+	benchmarkDialog := dialog.NewCustom("Industry Benchmark", "Close", content, c.window)
+	benchmarkDialog.Resize(fyne.NewSize(550, 450))
+	benchmarkDialog.Show()
+}
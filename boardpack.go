@@ -0,0 +1,329 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// boardPackEntry is a single canvas included in a board pack, with when
+// and by whom it was last updated
+type boardPackEntry struct {
+	Name      string
+	Data      CanvasData
+	Versions  []Version
+	Updated   time.Time
+	UpdatedBy string
+}
+
+// exportBoardPack combines the current canvas and canvases from a chosen
+// folder into one PDF with a cover page and table of contents
+func (c *Canvas) exportBoardPack() {
+	dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+
+		entries := []boardPackEntry{{
+			Name:      "Current canvas",
+			Data:      c.getCurrentData(),
+			Versions:  c.versions,
+			Updated:   c.lastReviewed(),
+			UpdatedBy: c.userName(),
+		}}
+		if dir != nil {
+			found, err := readCanvasFolder(dir)
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			entries = append(entries, found...)
+		}
+
+		c.chooseBoardPackEntries(entries)
+	}, c.window)
+}
+
+// readCanvasFolder loads every canvas JSON file in a folder
+func readCanvasFolder(dir fyne.ListableURI) ([]boardPackEntry, error) {
+	uris, err := dir.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []boardPackEntry
+	for _, uri := range uris {
+		if strings.ToLower(uri.Extension()) != ".json" {
+			continue
+		}
+		data, err := readCanvasURI(uri)
+		if err != nil {
+			// Skip files that are not canvases
+			continue
+		}
+		entries = append(entries, boardPackEntry{
+			Name:    strings.TrimSuffix(uri.Name(), uri.Extension()),
+			Data:    data,
+			Updated: lastSectionEdit(data),
+		})
+	}
+	return entries, nil
+}
+
+func (c *Canvas) chooseBoardPackEntries(entries []boardPackEntry) {
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+
+	titleEntry := widget.NewEntry()
+	titleEntry.SetText("Board Pack")
+	selection := widget.NewCheckGroup(names, nil)
+	selection.SetSelected(names)
+
+	content := container.NewBorder(
+		widget.NewForm(widget.NewFormItem("Title", titleEntry)), nil, nil, nil,
+		container.NewVScroll(selection),
+	)
+
+	dialog.ShowCustomConfirm("Board Pack", "Export", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		var selected []boardPackEntry
+		for _, entry := range entries {
+			for _, name := range selection.Selected {
+				if entry.Name == name {
+					selected = append(selected, entry)
+					break
+				}
+			}
+		}
+		if len(selected) == 0 {
+			dialog.ShowInformation("Board Pack", "Select at least one canvas", c.window)
+			return
+		}
+
+		opts, err := c.pdfOptions()
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		text := titleEntry.Text
+		for _, entry := range selected {
+			text += entry.Name + pdfText(entry.Data, nil)
+		}
+		opts.Font = opts.Font.coverText(text)
+		c.saveBoardPack(opts, titleEntry.Text, selected)
+	}, c.window)
+}
+
+func (c *Canvas) saveBoardPack(opts pdfOptions, title string, entries []boardPackEntry) {
+	dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		err = writeBoardPack(writer, opts, title, entries)
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+
+		dialog.ShowInformation("Success", "Board pack has been exported successfully", c.window)
+	}, c.window)
+}
+
+// lastSectionEdit is when a section of canvas data last changed
+func lastSectionEdit(data CanvasData) time.Time {
+	var last time.Time
+	for _, edited := range data.SectionEdited {
+		if edited.After(last) {
+			last = edited
+		}
+	}
+	return last
+}
+
+// writeBoardPack renders the cover, table of contents, one page per canvas,
+// a KPI dashboard per canvas and a changelog page for every canvas that has
+// version history
+func writeBoardPack(w io.Writer, opts pdfOptions, title string, entries []boardPackEntry) error {
+	pdf := newPDF(opts)
+	pdf.SetTitle(title, true)
+
+	// Cover page
+	pdf.AddPage()
+	pdf.SetFont(pdfFontFamily, "B", 36)
+	pdf.SetXY(10, 110)
+	pdf.CellFormat(400, 20, title, "", 1, "C", false, 0, "")
+	pdf.SetFont(pdfFontFamily, "", 16)
+	pdf.CellFormat(400, 10, time.Now().Format("January 2, 2006"), "", 1, "C", false, 0, "")
+	pdf.CellFormat(400, 10, fmt.Sprintf("%d canvases", len(entries)), "", 1, "C", false, 0, "")
+
+	// Table of contents, page numbers are known up front since every
+	// canvas takes one page per layer plus one for its changelog
+	pdf.AddPage()
+	_, top, _, _ := pdf.GetMargins()
+	pdf.SetFont(pdfFontFamily, "B", 24)
+	pdf.Text(10, top+15, "Contents")
+	pdf.SetFont(pdfFontFamily, "", 14)
+	page := 3
+	y := top + 30
+	for _, entry := range entries {
+		pdf.Text(15, y, entry.Name)
+		pdf.Text(390, y, fmt.Sprintf("%d", page))
+		y += 10
+		page++
+		for _, layer := range canvasLayers {
+			if len(entry.Data.layerData(layer.Name)) > 0 {
+				page++
+			}
+		}
+		pdf.Text(25, y, "KPI dashboard")
+		pdf.Text(390, y, fmt.Sprintf("%d", page))
+		y += 10
+		page++
+		if len(entry.Versions) > 0 {
+			pdf.Text(25, y, "Changelog")
+			pdf.Text(390, y, fmt.Sprintf("%d", page))
+			y += 10
+			page++
+		}
+	}
+
+	for _, entry := range entries {
+		pdf.AddPage()
+		pdf.SetFont(pdfFontFamily, "B", 10)
+		pdf.Text(10, top-3, entry.Name)
+		drawCanvasPage(pdf, opts, entry.Data)
+		drawLayerPages(pdf, opts, entry.Data)
+
+		pdf.AddPage()
+		drawKPIDashboard(pdf, entry)
+
+		if len(entry.Versions) > 0 {
+			pdf.AddPage()
+			drawChangelog(pdf, entry.Name, entry.Versions)
+		}
+	}
+
+	return pdf.Output(w)
+}
+
+// changelogLineHeight is the height of one changelog entry
+const changelogLineHeight = 7.0
+
+func drawChangelog(pdf *gofpdf.Fpdf, name string, versions []Version) {
+	_, pageHeight := pdf.GetPageSize()
+	_, top, _, _ := pdf.GetMargins()
+	_, bottom := pdf.GetAutoPageBreak()
+
+	pdf.SetFont(pdfFontFamily, "B", 20)
+	pdf.Text(10, top+10, name+" - Changelog")
+	pdf.SetFont(pdfFontFamily, "", 12)
+	pdf.SetXY(10, top+20)
+
+	// Keep the changelog on a single page so the contents stay accurate
+	maxLines := int((pageHeight-bottom-top-20)/changelogLineHeight) - 1
+	if len(versions) > maxLines {
+		skipped := len(versions) - maxLines
+		pdf.CellFormat(0, changelogLineHeight, fmt.Sprintf("... %d earlier versions", skipped), "", 1, "L", false, 0, "")
+		versions = versions[skipped:]
+	}
+	for _, version := range versions {
+		pdf.CellFormat(0, changelogLineHeight, formatStampSeconds(version.Timestamp), "", 1, "L", false, 0, "")
+	}
+}
+
+// kpiBarWidth is the width of a full bar on the KPI dashboard
+const kpiBarWidth = 160.0
+
+// drawKPIDashboard renders the health score of a canvas with its factors
+// and the key figures of its content, assumptions and history
+func drawKPIDashboard(pdf *gofpdf.Fpdf, entry boardPackEntry) {
+	_, top, _, _ := pdf.GetMargins()
+	data := entry.Data
+	report := canvasHealth(data, func(string) int { return defaultStaleDays }, entry.Updated, time.Now())
+
+	pdf.SetFont(pdfFontFamily, "B", 20)
+	pdf.Text(10, top+10, entry.Name+" - KPI Dashboard")
+	pdf.SetFont(pdfFontFamily, "B", 48)
+	pdf.Text(10, top+40, fmt.Sprintf("%d", report.Score()))
+	pdf.SetFont(pdfFontFamily, "", 14)
+	pdf.Text(50, top+40, "/ 100 health")
+
+	y := top + 55
+	for _, factor := range report.Factors {
+		pdf.SetFont(pdfFontFamily, "B", 12)
+		pdf.Text(10, y+5, factor.Name)
+		pdf.SetFillColor(230, 230, 230)
+		pdf.Rect(60, y, kpiBarWidth, 7, "F")
+		pdf.SetFillColor(68, 170, 136)
+		pdf.Rect(60, y, kpiBarWidth*factor.Score, 7, "F")
+		pdf.SetFont(pdfFontFamily, "", 12)
+		pdf.Text(60+kpiBarWidth+5, y+5, fmt.Sprintf("%.0f%% (weight %.0f)", factor.Score*100, factor.Weight))
+		advice, _, _ := strings.Cut(factor.Advice, "\n")
+		pdf.SetFont(pdfFontFamily, "", 10)
+		pdf.Text(60, y+12, advice)
+		y += 20
+	}
+
+	sections := data.sections()
+	filled, words := 0, 0
+	for _, section := range sections {
+		words += wordCount(section.Text)
+		if strings.TrimSpace(section.Text) != "" {
+			filled++
+		}
+	}
+	statuses := make(map[string]int)
+	files := 0
+	for _, assumption := range data.Assumptions {
+		statuses[assumption.status()]++
+		files += len(assumption.Evidence)
+	}
+	findings := "not recorded"
+	if data.Validation != nil {
+		findings = fmt.Sprintf("%d of %d rules", len(data.Validation.Findings), data.Validation.Rules)
+	}
+	updated := "unknown"
+	if !entry.Updated.IsZero() {
+		updated = formatStamp(entry.Updated)
+		if entry.UpdatedBy != "" {
+			updated += " by " + entry.UpdatedBy
+		}
+	}
+	figures := [][2]string{
+		{"Sections filled", fmt.Sprintf("%d of %d", filled, len(sections))},
+		{"Words", fmt.Sprintf("%d", words)},
+		{"Validation findings", findings},
+		{"Assumptions", fmt.Sprintf("%d: %d validated, %d invalidated, %d untested", len(data.Assumptions),
+			statuses[assumptionValidated], statuses[assumptionInvalidated], statuses[assumptionUntested])},
+		{"Evidence files", fmt.Sprintf("%d", files)},
+		{"Versions", fmt.Sprintf("%d", len(entry.Versions))},
+		{"Last updated", updated},
+	}
+	y += 5
+	for _, figure := range figures {
+		pdf.SetFont(pdfFontFamily, "B", 12)
+		pdf.Text(10, y, figure[0])
+		pdf.SetFont(pdfFontFamily, "", 12)
+		pdf.Text(70, y, figure[1])
+		y += 8
+	}
+}
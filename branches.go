@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// mainBranch is the branch versions are saved to until another is created
+const mainBranch = "main"
+
+// branchName returns the branch a version was saved to
+func (v Version) branchName() string {
+	if v.Branch == "" {
+		return mainBranch
+	}
+	return v.Branch
+}
+
+// currentBranch returns the branch new versions are saved to
+func (c *Canvas) currentBranch() string {
+	if c.branch == "" {
+		return mainBranch
+	}
+	return c.branch
+}
+
+// branches lists the branches with saved versions, main first and the
+// rest in the order they were created
+func (c *Canvas) branches() []string {
+	branches := []string{mainBranch}
+	seen := map[string]bool{mainBranch: true}
+	for _, version := range c.versions {
+		if name := version.branchName(); !seen[name] {
+			seen[name] = true
+			branches = append(branches, name)
+		}
+	}
+	if name := c.currentBranch(); !seen[name] {
+		branches = append(branches, name)
+	}
+	return branches
+}
+
+// latestVersionIndex returns the index of the most recent version saved to
+// a branch, -1 when there is none
+func (c *Canvas) latestVersionIndex(branch string) int {
+	for i := len(c.versions) - 1; i >= 0; i-- {
+		if c.versions[i].branchName() == branch {
+			return i
+		}
+	}
+	return -1
+}
+
+// latestVersion returns the most recent version saved to a branch
+func (c *Canvas) latestVersion(branch string) (Version, bool) {
+	if i := c.latestVersionIndex(branch); i >= 0 {
+		return c.versions[i], true
+	}
+	return Version{}, false
+}
+
+// switchBranch saves the current state to the current branch and loads
+// the latest version of another
+func (c *Canvas) switchBranch(branch string) {
+	if branch == c.currentBranch() {
+		return
+	}
+	latest, ok := c.latestVersion(branch)
+	if !ok {
+		return
+	}
+
+	c.saveCurrentVersion()
+	c.undoStack = append(c.undoStack, c.getCurrentData())
+	c.branch = branch
+	c.setCurrentData(latest.Data)
+	c.updateProgress()
+}
+
+// restoreAsBranch restores a version into a new branch, leaving the
+// current branch as it is, and calls done once the branch is created
+func (c *Canvas) restoreAsBranch(version Version, done func()) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("e.g. Freemium model")
+	nameEntry.Validator = func(name string) error {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return errors.New("enter a branch name")
+		}
+		for _, branch := range c.branches() {
+			if strings.EqualFold(branch, name) {
+				return fmt.Errorf("branch %q already exists", branch)
+			}
+		}
+		return nil
+	}
+
+	items := []*widget.FormItem{widget.NewFormItem("Branch name", nameEntry)}
+	form := dialog.NewForm("Restore as Branch", "Create", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		c.saveCurrentVersion()
+		c.undoStack = append(c.undoStack, c.getCurrentData())
+		c.branch = strings.TrimSpace(nameEntry.Text)
+		c.setCurrentData(version.Data)
+		c.saveCurrentVersion()
+		c.versions[len(c.versions)-1].Note = "Branched from " + version.label()
+
+		if done != nil {
+			done()
+		}
+		dialog.ShowInformation("Success", "Branch "+c.branch+" created from the selected version", c.window)
+	}, c.window)
+	form.Resize(fyne.NewSize(400, 0))
+	form.Show()
+}
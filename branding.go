@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Preference keys for PDF branding
+const (
+	prefBrandingLogo   = "brandingLogo"
+	prefBrandingTitle  = "brandingTitle"
+	prefBrandingAuthor = "brandingAuthor"
+	prefBrandingDate   = "brandingDate"
+	prefBrandingFooter = "brandingFooter"
+)
+
+// PDFBranding decorates exported PDFs with a logo, title block and footer
+type PDFBranding struct {
+	LogoURI  string
+	Title    string
+	Author   string
+	ShowDate bool
+	Footer   bool
+
+	logo     []byte
+	logoType string
+}
+
+// hasHeader reports whether the branding needs a header band on each page
+func (b PDFBranding) hasHeader() bool {
+	return len(b.logo) > 0 || b.Title != "" || b.Author != "" || b.ShowDate
+}
+
+func (c *Canvas) loadBranding() PDFBranding {
+	return PDFBranding{
+		LogoURI:  c.prefs.String(prefBrandingLogo),
+		Title:    c.prefs.String(prefBrandingTitle),
+		Author:   c.prefs.String(prefBrandingAuthor),
+		ShowDate: c.prefs.Bool(prefBrandingDate),
+		Footer:   c.prefs.Bool(prefBrandingFooter),
+	}
+}
+
+func (c *Canvas) saveBranding(branding PDFBranding) {
+	c.prefs.SetString(prefBrandingLogo, branding.LogoURI)
+	c.prefs.SetString(prefBrandingTitle, branding.Title)
+	c.prefs.SetString(prefBrandingAuthor, branding.Author)
+	c.prefs.SetBool(prefBrandingDate, branding.ShowDate)
+	c.prefs.SetBool(prefBrandingFooter, branding.Footer)
+}
+
+// loadLogo reads the logo image referenced by the branding
+func (b *PDFBranding) loadLogo() error {
+	if b.LogoURI == "" {
+		return nil
+	}
+	uri, err := parseLocation(b.LogoURI)
+	if err != nil {
+		return err
+	}
+	b.logo, err = readURI(uri)
+	if err != nil {
+		return err
+	}
+	b.logoType = strings.TrimPrefix(strings.ToLower(uri.Extension()), ".")
+	return nil
+}
+
+// brandingHeaderHeight is the space reserved above the canvas for the header
+const brandingHeaderHeight = 20.0
+
+// applyBranding sets document metadata, margins and the header and footer.
+// A non-empty note, such as the canvas statistics, is printed in the footer
+// callbacks that draw the branding on every page
+func applyBranding(pdf *gofpdf.Fpdf, branding PDFBranding, note string) {
+	if branding.Title != "" {
+		pdf.SetTitle(branding.Title, true)
+	}
+	if branding.Author != "" {
+		pdf.SetAuthor(branding.Author, true)
+	}
+
+	if branding.hasHeader() {
+		pdf.SetTopMargin(10 + brandingHeaderHeight)
+		if len(branding.logo) > 0 {
+			pdf.RegisterImageOptionsReader("logo", gofpdf.ImageOptions{ImageType: branding.logoType}, bytes.NewReader(branding.logo))
+		}
+		date := time.Now().Format("January 2, 2006")
+
+		pdf.SetHeaderFunc(func() {
+			pageWidth, _ := pdf.GetPageSize()
+			x := 10.0
+			if len(branding.logo) > 0 {
+				pdf.ImageOptions("logo", x, 8, 0, 16, false, gofpdf.ImageOptions{}, 0, "")
+				if info := pdf.GetImageInfo("logo"); info != nil && info.Height() > 0 {
+					x += 16*info.Width()/info.Height() + 5
+				}
+			}
+			if branding.Title != "" {
+				pdf.SetFont(pdfFontFamily, "B", 18)
+				pdf.Text(x, 19, branding.Title)
+			}
+
+			var details []string
+			if branding.Author != "" {
+				details = append(details, branding.Author)
+			}
+			if branding.ShowDate {
+				details = append(details, date)
+			}
+			if len(details) > 0 {
+				pdf.SetFont(pdfFontFamily, "", 11)
+				text := strings.Join(details, " | ")
+				pdf.Text(pageWidth-10-pdf.GetStringWidth(text), 19, text)
+			}
+		})
+	}
+
+	if branding.Footer || note != "" {
+		pdf.SetAutoPageBreak(true, 18)
+		pdf.AliasNbPages("")
+		pdf.SetFooterFunc(func() {
+			pdf.SetY(-12)
+			if note != "" {
+				pdf.SetFont(pdfFontFamily, "", 8)
+				pdf.CellFormat(0, 6, note, "", 0, "L", false, 0, "")
+				pdf.SetY(-12) // back to the left margin
+			}
+			if branding.Footer {
+				pdf.SetFont(pdfFontFamily, "", 9)
+				pdf.CellFormat(0, 6, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+			}
+		})
+	}
+}
+
+// showBrandingSettings edits the persisted PDF branding
+func (c *Canvas) showBrandingSettings() {
+	branding := c.loadBranding()
+
+	logoLabel := widget.NewLabel("None")
+	if uri, err := storage.ParseURI(branding.LogoURI); err == nil && branding.LogoURI != "" {
+		logoLabel.SetText(uri.Name())
+	}
+	chooseLogo := widget.NewButton("Choose...", func() {
+		logoDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			reader.Close()
+			branding.LogoURI = reader.URI().String()
+			logoLabel.SetText(reader.URI().Name())
+		}, c.window)
+		logoDialog.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg", ".gif"}))
+		logoDialog.Show()
+	})
+	clearLogo := widget.NewButton("Clear", func() {
+		branding.LogoURI = ""
+		logoLabel.SetText("None")
+	})
+
+	titleEntry := widget.NewEntry()
+	titleEntry.SetText(branding.Title)
+	authorEntry := widget.NewEntry()
+	authorEntry.SetText(branding.Author)
+	dateCheck := widget.NewCheck("Show export date", nil)
+	dateCheck.SetChecked(branding.ShowDate)
+	footerCheck := widget.NewCheck("Page numbers in footer", nil)
+	footerCheck.SetChecked(branding.Footer)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Logo", container.NewHBox(logoLabel, chooseLogo, clearLogo)),
+		widget.NewFormItem("Title", titleEntry),
+		widget.NewFormItem("Author", authorEntry),
+		widget.NewFormItem("Date", dateCheck),
+		widget.NewFormItem("Footer", footerCheck),
+	}
+
+	dialog.ShowForm("PDF Branding", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		branding.Title = titleEntry.Text
+		branding.Author = authorEntry.Text
+		branding.ShowDate = dateCheck.Checked
+		branding.Footer = footerCheck.Checked
+		c.saveBranding(branding)
+	}, c.window)
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// defaultAutoSaveInterval is how long auto-save waits after the last save
+// unless the canvas sets its own interval
+const defaultAutoSaveInterval = 5 * time.Minute
+
+// Export settings a canvas can carry as its export profile
+var (
+	exportProfileStrings = []string{
+		prefPDFFont,
+		prefPDFPageSize,
+		prefBrandingLogo,
+		prefBrandingTitle,
+		prefBrandingAuthor,
+	}
+	exportProfileBools = []string{
+		prefPDFColored,
+		prefPDFComments,
+		prefPDFWordCloud,
+		prefPDFPresenterNotes,
+		prefExportStats,
+		prefBrandingDate,
+		prefBrandingFooter,
+	}
+)
+
+// CanvasSettings are stored in a canvas file and override the app settings
+// while it is open, so a client's canvas brings the conventions of its
+// engagement along
+type CanvasSettings struct {
+	AutoSaveMinutes int `json:"autoSaveMinutes,omitempty"`
+	// RulePacks names the rule packs the canvas is validated with in place
+	// of the packs enabled in the app
+	RulePacks []string `json:"rulePacks"`
+	// Language is the language the canvas is written in, as a BCP 47 tag
+	Language string `json:"language,omitempty"`
+	// Strings and Bools are the export profile, keyed by preference
+	Strings map[string]string `json:"strings,omitempty"`
+	Bools   map[string]bool   `json:"bools,omitempty"`
+}
+
+// empty reports whether the settings override nothing
+func (s *CanvasSettings) empty() bool {
+	return s == nil || s.AutoSaveMinutes == 0 && s.RulePacks == nil && s.Language == "" && len(s.Strings) == 0 && len(s.Bools) == 0
+}
+
+// language is the language of a canvas, empty when it was not set
+func (d CanvasData) language() string {
+	if d.Settings == nil {
+		return ""
+	}
+	return d.Settings.Language
+}
+
+// canvasPreferences reads the app preferences through the export profile of
+// the open canvas. Changes are always written to the app preferences.
+type canvasPreferences struct {
+	fyne.Preferences
+	canvas *Canvas
+}
+
+func (p canvasPreferences) Bool(key string) bool {
+	return p.BoolWithFallback(key, false)
+}
+
+func (p canvasPreferences) BoolWithFallback(key string, fallback bool) bool {
+	if s := p.canvas.canvasSettings; s != nil {
+		if value, ok := s.Bools[key]; ok {
+			return value
+		}
+	}
+	return p.Preferences.BoolWithFallback(key, fallback)
+}
+
+func (p canvasPreferences) String(key string) string {
+	return p.StringWithFallback(key, "")
+}
+
+func (p canvasPreferences) StringWithFallback(key, fallback string) string {
+	if s := p.canvas.canvasSettings; s != nil {
+		if value, ok := s.Strings[key]; ok {
+			return value
+		}
+	}
+	return p.Preferences.StringWithFallback(key, fallback)
+}
+
+// autoSaveInterval is how long auto-save waits after the last save
+func (c *Canvas) autoSaveInterval() time.Duration {
+	if s := c.canvasSettings; s != nil && s.AutoSaveMinutes > 0 {
+		return time.Duration(s.AutoSaveMinutes) * time.Minute
+	}
+	return defaultAutoSaveInterval
+}
+
+// canvasRulePacks applies the rule packs chosen for the canvas to the
+// installed packs
+func (c *Canvas) canvasRulePacks(packs []RulePack) []RulePack {
+	s := c.canvasSettings
+	if s == nil || s.RulePacks == nil {
+		return packs
+	}
+	chosen := make([]RulePack, len(packs))
+	for i, pack := range packs {
+		pack.Enabled = slices.Contains(s.RulePacks, pack.Name)
+		chosen[i] = pack
+	}
+	return chosen
+}
+
+// applyCanvasSettings switches to the settings of a canvas, nil for the app
+// settings
+func (c *Canvas) applyCanvasSettings(settings *CanvasSettings) {
+	if settings.empty() {
+		settings = nil
+	}
+	c.canvasSettings = settings
+	c.loadValidator()
+	c.refreshHealth()
+}
+
+// showCanvasSettings edits the settings stored in the canvas file
+func (c *Canvas) showCanvasSettings() {
+	current := CanvasSettings{}
+	if c.canvasSettings != nil {
+		current = *c.canvasSettings
+	}
+
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetPlaceHolder(fmt.Sprintf("%d (app default)", int(defaultAutoSaveInterval.Minutes())))
+	if current.AutoSaveMinutes > 0 {
+		intervalEntry.SetText(strconv.Itoa(current.AutoSaveMinutes))
+	}
+	intervalEntry.Validator = func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		if minutes, err := strconv.Atoi(strings.TrimSpace(text)); err != nil || minutes <= 0 {
+			return fmt.Errorf("enter a number of minutes")
+		}
+		return nil
+	}
+
+	ownPacks := widget.NewCheck("Use its own rule packs", nil)
+	packChecks := container.NewVBox()
+	var packs []*widget.Check
+	var packNames []string
+	for _, pack := range c.loadRulePacks() {
+		check := widget.NewCheck(pack.label(), nil)
+		check.SetChecked(slices.Contains(current.RulePacks, pack.Name))
+		packs = append(packs, check)
+		packNames = append(packNames, pack.Name)
+		packChecks.Add(check)
+	}
+	if len(packs) == 0 {
+		packChecks.Add(widget.NewLabel("No rule packs installed"))
+	}
+	ownPacks.OnChanged = func(on bool) {
+		for _, check := range packs {
+			if on {
+				check.Enable()
+			} else {
+				check.Disable()
+			}
+		}
+	}
+	ownPacks.SetChecked(current.RulePacks != nil)
+	ownPacks.OnChanged(ownPacks.Checked)
+
+	languageEntry := widget.NewEntry()
+	languageEntry.SetPlaceHolder("e.g. en, de-CH")
+	languageEntry.SetText(current.Language)
+
+	exportLabel := widget.NewLabel("")
+	exportStrings, exportBools := current.Strings, current.Bools
+	showExport := func() {
+		if len(exportStrings) == 0 && len(exportBools) == 0 {
+			exportLabel.SetText("App export settings")
+		} else {
+			exportLabel.SetText("Export settings stored in the canvas")
+		}
+	}
+	showExport()
+	captureExport := widget.NewButton("Store Current", func() {
+		exportStrings = make(map[string]string)
+		exportBools = make(map[string]bool)
+		for _, key := range exportProfileStrings {
+			exportStrings[key] = c.prefs.String(key)
+		}
+		for _, key := range exportProfileBools {
+			exportBools[key] = c.prefs.Bool(key)
+		}
+		showExport()
+	})
+	clearExport := widget.NewButton("Clear", func() {
+		exportStrings, exportBools = nil, nil
+		showExport()
+	})
+
+	form := dialog.NewForm("Canvas Settings", "Save", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Auto-save after (minutes)", intervalEntry),
+		widget.NewFormItem("Validation", container.NewVBox(ownPacks, packChecks)),
+		widget.NewFormItem("Language", languageEntry),
+		widget.NewFormItem("Export profile", container.NewBorder(nil, nil, nil, container.NewHBox(captureExport, clearExport), exportLabel)),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		settings := &CanvasSettings{
+			Language: strings.TrimSpace(languageEntry.Text),
+			Strings:  exportStrings,
+			Bools:    exportBools,
+		}
+		settings.AutoSaveMinutes, _ = strconv.Atoi(strings.TrimSpace(intervalEntry.Text))
+		if ownPacks.Checked {
+			settings.RulePacks = []string{}
+			for i, check := range packs {
+				if check.Checked {
+					settings.RulePacks = append(settings.RulePacks, packNames[i])
+				}
+			}
+		}
+		c.undoStack = append(c.undoStack, c.getCurrentData())
+		c.applyCanvasSettings(settings)
+		dialog.ShowInformation("Success", "Canvas settings are saved with the canvas file", c.window)
+	}, c.window)
+	form.Resize(fyne.NewSize(500, 0))
+	form.Show()
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"gopkg.in/yaml.v3"
+)
+
+// Canvas templates are JSON or YAML files in the templates folder of the
+// app, each adding a canvas type with its own sections, grid and
+// validation. A template has up to nine sections, stored like the nine
+// blocks of the Business Model Canvas:
+//
+//	name: Lean Canvas
+//	columns: 10
+//	rows: 10
+//	sections:
+//	  - title: Problem
+//	    placeholder: What are the top three problems?
+//	    col: 0
+//	    row: 0
+//	    colSpan: 2
+//	    rowSpan: 6
+//	    minWords: 8
+//	    message: List the top problems of your customers
+
+// templateTypePrefix keeps the IDs of template canvas types apart from the
+// built-in ones
+const templateTypePrefix = "template:"
+
+// maxTemplateSections is the number of sections a canvas file can store
+const maxTemplateSections = 9
+
+// CanvasTemplate describes a canvas type in a template file
+type CanvasTemplate struct {
+	ID       string            `json:"id" yaml:"id"`
+	Name     string            `json:"name" yaml:"name"`
+	Columns  int               `json:"columns" yaml:"columns"`
+	Rows     int               `json:"rows" yaml:"rows"`
+	Sections []TemplateSection `json:"sections" yaml:"sections"`
+}
+
+// TemplateSection is a section of a canvas template, placed on the grid of
+// the template and valid once its text reaches MinWords words. MinLength
+// counts characters instead, as templates did before word counts.
+type TemplateSection struct {
+	Title       string `json:"title" yaml:"title"`
+	Placeholder string `json:"placeholder" yaml:"placeholder"`
+	Col         int    `json:"col" yaml:"col"`
+	Row         int    `json:"row" yaml:"row"`
+	ColSpan     int    `json:"colSpan" yaml:"colSpan"`
+	RowSpan     int    `json:"rowSpan" yaml:"rowSpan"`
+	MinWords    int    `json:"minWords,omitempty" yaml:"minWords"`
+	MinLength   int    `json:"minLength,omitempty" yaml:"minLength"`
+	Message     string `json:"message,omitempty" yaml:"message"`
+}
+
+// templateError is a template file that could not be loaded
+type templateError struct {
+	File string
+	Err  error
+}
+
+// templatesFolder is where template files are loaded from
+func templatesFolder() string {
+	return filepath.Join(fyne.CurrentApp().Storage().RootURI().Path(), "templates")
+}
+
+// parseCanvasTemplate reads a template file, YAML unless it ends in .json
+func parseCanvasTemplate(name string, data []byte) (CanvasTemplate, error) {
+	var template CanvasTemplate
+	var err error
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		err = json.Unmarshal(data, &template)
+	} else {
+		err = yaml.Unmarshal(data, &template)
+	}
+	if err != nil {
+		return template, err
+	}
+	if template.ID == "" {
+		template.ID = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	}
+	return template, template.check()
+}
+
+// check rejects templates that cannot be shown or stored
+func (t CanvasTemplate) check() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("template has no name")
+	}
+	if len(t.Sections) == 0 || len(t.Sections) > maxTemplateSections {
+		return fmt.Errorf("template needs 1 to %d sections, it has %d", maxTemplateSections, len(t.Sections))
+	}
+	if t.Columns <= 0 || t.Rows <= 0 {
+		return errors.New("template needs a grid of at least one column and row")
+	}
+	seen := make(map[string]bool)
+	for _, section := range t.Sections {
+		if strings.TrimSpace(section.Title) == "" {
+			return errors.New("every section needs a title")
+		}
+		if seen[section.Title] {
+			return fmt.Errorf("section %q is defined twice", section.Title)
+		}
+		seen[section.Title] = true
+		if section.ColSpan <= 0 || section.RowSpan <= 0 || section.Col < 0 || section.Row < 0 ||
+			section.Col+section.ColSpan > t.Columns || section.Row+section.RowSpan > t.Rows {
+			return fmt.Errorf("section %q does not fit on the %dx%d grid", section.Title, t.Columns, t.Rows)
+		}
+	}
+	return nil
+}
+
+// canvasType turns the template into a canvas type
+func (t CanvasTemplate) canvasType() *canvasType {
+	kind := &canvasType{
+		ID:     templateTypePrefix + t.ID,
+		Name:   t.Name,
+		Layout: canvasLayout{Columns: t.Columns, Rows: t.Rows},
+	}
+	for _, section := range t.Sections {
+		kind.Titles = append(kind.Titles, section.Title)
+		kind.Prompts = append(kind.Prompts, section.Placeholder)
+		kind.Layout.Blocks = append(kind.Layout.Blocks, blockPlacement{
+			Col: section.Col, Row: section.Row, ColSpan: section.ColSpan, RowSpan: section.RowSpan,
+		})
+	}
+	sections := t.Sections
+	kind.NewValidator = func() *BusinessValidator {
+		validator := &BusinessValidator{}
+		for i, section := range sections {
+			if section.MinWords > 0 {
+				message := section.Message
+				if message == "" {
+					message = fmt.Sprintf("Write at least %d words", section.MinWords)
+				}
+				i := i
+				validator.rules = append(validator.rules, wordRule(section.Title, section.MinWords, message, func(c *Canvas) string {
+					return c.standardEntries()[i].Text
+				}))
+				continue
+			}
+			if section.MinLength <= 0 {
+				continue
+			}
+			i, minLength := i, section.MinLength
+			message := section.Message
+			if message == "" {
+				message = fmt.Sprintf("Write at least %d characters", minLength)
+			}
+			validator.rules = append(validator.rules, ValidationRule{
+				Section: section.Title,
+				Message: message,
+				Check: func(c *Canvas) bool {
+					return len(c.standardEntries()[i].Text) >= minLength
+				},
+			})
+		}
+		return validator
+	}
+	return kind
+}
+
+// loadCanvasTemplates adds the canvas types of the template files in dir,
+// returning the files that could not be loaded
+func loadCanvasTemplates(dir string) []templateError {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return []templateError{{dir, err}}
+	}
+	var failed []templateError
+	for _, file := range files {
+		switch strings.ToLower(filepath.Ext(file.Name())) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err == nil {
+			var template CanvasTemplate
+			if template, err = parseCanvasTemplate(file.Name(), data); err == nil {
+				kind := template.canvasType()
+				if findCanvasType(kind.ID).ID == kind.ID {
+					err = fmt.Errorf("a template with ID %q is already loaded", template.ID)
+				} else {
+					canvasTypes = append(canvasTypes, kind)
+				}
+			}
+		}
+		if err != nil {
+			failed = append(failed, templateError{file.Name(), err})
+		}
+	}
+	return failed
+}
+
+// showCanvasTemplates lists the templates loaded at startup and where to put
+// template files
+func (c *Canvas) showCanvasTemplates() {
+	list := container.NewVBox()
+	for _, kind := range canvasTypes {
+		if strings.HasPrefix(kind.ID, templateTypePrefix) {
+			list.Add(widget.NewLabel(fmt.Sprintf("• %s (%d sections)", kind.Name, len(kind.Titles))))
+		}
+	}
+	if len(list.Objects) == 0 {
+		list.Add(widget.NewLabel("No templates loaded"))
+	}
+	for _, failed := range c.templateErrors {
+		errorLabel := widget.NewLabel(fmt.Sprintf("%s: %v", failed.File, failed.Err))
+		errorLabel.Importance = widget.DangerImportance
+		errorLabel.Wrapping = fyne.TextWrapWord
+		list.Add(errorLabel)
+	}
+
+	folder := templatesFolder()
+	help := widget.NewLabel("Put JSON or YAML template files in " + folder + " and restart the app. New > Template lists them.")
+	help.Wrapping = fyne.TextWrapWord
+	open := widget.NewButton("Open Templates Folder", func() {
+		if err := os.MkdirAll(folder, 0o755); err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if folderURL, err := url.Parse(storage.NewFileURI(folder).String()); err == nil {
+			fyne.CurrentApp().OpenURL(folderURL)
+		}
+	})
+
+	templates := dialog.NewCustom("Canvas Templates", "Close", container.NewBorder(help, open, nil, nil, container.NewVScroll(list)), c.window)
+	templates.Resize(fyne.NewSize(500, 400))
+	templates.Show()
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fyne.io/fyne/v2/widget"
+)
+
+// Canvas type identifiers stored in the file format
+const (
+	canvasTypeBusiness = "business"
+	canvasTypeMission  = "mission"
+	canvasTypeTeam     = "team"
+	canvasTypeCulture  = "culture"
+	canvasTypeValue    = "value"
+	canvasTypeEnv      = "environment"
+)
+
+// canvasType is a canvas variant: the titles, prompts, layout and
+// validation rules of its blocks. A type uses up to nine blocks, stored in
+// the standard fields in reading order.
+type canvasType struct {
+	ID           string
+	Name         string
+	Titles       []string
+	Prompts      []string
+	Layout       canvasLayout
+	NewValidator func() *BusinessValidator
+}
+
+var canvasTypes = []*canvasType{
+	{
+		ID:   canvasTypeBusiness,
+		Name: "Business Model Canvas",
+		Titles: []string{
+			"Key Partners",
+			"Key Activities",
+			"Key Resources",
+			"Value Proposition",
+			"Customer Relationships",
+			"Channels",
+			"Customer Segments",
+			"Cost Structure",
+			"Revenue Streams",
+		},
+		Prompts: []string{
+			"Who are your key partners and suppliers? What resources are you acquiring from them?",
+			"What key activities does your value proposition require?",
+			"What key resources does your value proposition require?",
+			"What value do you deliver to customers? Which problems are you solving?",
+			"What type of relationship does each customer segment expect?",
+			"Through which channels do your customers want to be reached?",
+			"For whom are you creating value? Who are your most important customers?",
+			"What are the most important costs inherent in your business model?",
+			"For what value are your customers willing to pay? How would they prefer to pay?",
+		},
+		Layout:       businessLayout,
+		NewValidator: NewBusinessValidator,
+	},
+	{
+		ID:   canvasTypeMission,
+		Name: "Mission Model Canvas",
+		Titles: []string{
+			"Key Partners",
+			"Key Activities",
+			"Key Resources",
+			"Value Proposition",
+			"Buy-In & Support",
+			"Deployment",
+			"Beneficiaries",
+			"Mission Budget / Cost",
+			"Mission Achievement",
+		},
+		Prompts: []string{
+			"Which partners, agencies or volunteers do you need to deliver the mission?",
+			"What activities does delivering value to beneficiaries require?",
+			"What people, funding and assets does the mission depend on?",
+			"What value do you deliver to beneficiaries? Which needs are you meeting?",
+			"Whose buy-in and support is needed for the mission to succeed?",
+			"How will the solution be deployed and delivered to beneficiaries?",
+			"Who benefits from the mission? Which groups matter most?",
+			"What does it cost to deliver the mission, and how is it funded?",
+			"What are the impact factors that define mission achievement?",
+		},
+		Layout:       businessLayout,
+		NewValidator: NewMissionValidator,
+	},
+	{
+		ID:   canvasTypeTeam,
+		Name: "Team Canvas",
+		Titles: []string{
+			"People & Roles",
+			"Common Goals",
+			"Personal Goals",
+			"Purpose",
+			"Values",
+			"Needs & Expectations",
+			"Rules & Activities",
+			"Strengths & Assets",
+			"Weaknesses & Risks",
+		},
+		Prompts: []string{
+			"Who is on the team, and what role does each person play?",
+			"What do we want to achieve together as a team?",
+			"What does each of us want to achieve or learn personally?",
+			"Why are we doing this? What is the team's purpose?",
+			"What do we stand for? Which principles guide our behavior?",
+			"What does each person need from the team to succeed?",
+			"Which rules, rituals and activities do we agree on?",
+			"What skills and assets help us reach our goals?",
+			"Which weaknesses and risks could keep us from our goals?",
+		},
+		Layout:       teamLayout,
+		NewValidator: NewTeamValidator,
+	},
+	{
+		ID:   canvasTypeCulture,
+		Name: "Culture Map",
+		Titles: []string{
+			"Outcomes",
+			"Behaviors",
+			"Enablers",
+			"Blockers",
+		},
+		Prompts: []string{
+			"Which outcomes does the culture produce, good and bad?",
+			"Which behaviors, positive and negative, produce those outcomes?",
+			"Which policies, actions and rituals enable the desired behaviors?",
+			"Which policies, actions and rituals block the desired behaviors?",
+		},
+		Layout:       cultureLayout,
+		NewValidator: NewCultureValidator,
+	},
+	{
+		ID:   canvasTypeValue,
+		Name: "Value Proposition Canvas",
+		Titles: []string{
+			"Customer Jobs",
+			"Pains",
+			"Gains",
+			"Products & Services",
+			"Pain Relievers",
+			"Gain Creators",
+		},
+		Prompts: []string{
+			"What functional, social and emotional jobs is the customer trying to get done?",
+			"What annoys the customer before, during and after getting a job done? What risks do they fear?",
+			"What outcomes and benefits does the customer want? What would delight them?",
+			"Which products and services does your value proposition build on?",
+			"How do your products and services relieve the customer's pains?",
+			"How do your products and services create the gains the customer wants?",
+		},
+		Layout:       valueLayout,
+		NewValidator: NewValueValidator,
+	},
+	{
+		ID:   canvasTypeEnv,
+		Name: "Business Model Environment",
+		Titles: []string{
+			"Market Forces",
+			"Industry Forces",
+			"Key Trends",
+			"Macroeconomic Forces",
+		},
+		Prompts: []string{
+			"Which market segments, needs, switching costs and revenue attractiveness shape the market?",
+			"Who are the competitors, new entrants, substitutes, suppliers and other stakeholders?",
+			"Which technology, regulatory, societal, cultural and socioeconomic trends matter?",
+			"How do global market conditions, capital markets, commodities and economic infrastructure affect you?",
+		},
+		Layout:       environmentLayout,
+		NewValidator: NewEnvironmentValidator,
+	},
+}
+
+// findCanvasType returns a canvas type by ID, falling back to the Business
+// Model Canvas for files written before canvas types existed
+func findCanvasType(id string) *canvasType {
+	for _, kind := range canvasTypes {
+		if kind.ID == id {
+			return kind
+		}
+	}
+	return canvasTypes[0]
+}
+
+func (d CanvasData) canvasType() *canvasType {
+	return findCanvasType(d.CanvasType)
+}
+
+// standardEntries returns the nine standard editors in reading order
+func (c *Canvas) standardEntries() []*widget.Entry {
+	return []*widget.Entry{
+		c.keyPartners,
+		c.keyActivities,
+		c.keyResources,
+		c.valueProposition,
+		c.customerRel,
+		c.channels,
+		c.customerSegments,
+		c.costStructure,
+		c.revenueStreams,
+	}
+}
+
+// standardFields returns the nine standard text fields in reading order
+func (d *CanvasData) standardFields() []*string {
+	return []*string{
+		&d.KeyPartners,
+		&d.KeyActivities,
+		&d.KeyResources,
+		&d.ValueProposition,
+		&d.CustomerRel,
+		&d.Channels,
+		&d.CustomerSegments,
+		&d.CostStructure,
+		&d.RevenueStreams,
+	}
+}
+
+// applyCanvasType switches the titles, prompts, layout and validation rules
+// of the standard blocks, keeping their content
+func (c *Canvas) applyCanvasType(id string) {
+	kind := findCanvasType(id)
+	c.canvasTypeID = kind.ID
+	c.loadValidator()
+
+	for i, entry := range c.standardEntries()[:len(kind.Titles)] {
+		entry.SetPlaceHolder(kind.Prompts[i])
+		c.setupDynamicValidation(entry, kind.Titles[i])
+	}
+
+	c.refreshLayout()
+	c.refreshHealth()
+}
+
+// createCanvasTypeSelect picks the canvas variant in the settings dialog
+func (c *Canvas) createCanvasTypeSelect() *widget.Select {
+	var names []string
+	for _, kind := range canvasTypes {
+		names = append(names, kind.Name)
+	}
+
+	typeSelect := widget.NewSelect(names, func(selected string) {
+		for _, kind := range canvasTypes {
+			if kind.Name == selected && kind.ID != c.canvasTypeID {
+				c.applyCanvasType(kind.ID)
+				break
+			}
+		}
+	})
+	typeSelect.SetSelected(findCanvasType(c.canvasTypeID).Name)
+	return typeSelect
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// stringList collects a flag that can be repeated
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runCommand runs a command line subcommand instead of the app, reporting
+// whether the arguments named one and the exit code
+func runCommand(args []string) (bool, int) {
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		printUsage(os.Stdout)
+		return true, 0
+	case "watch":
+		if err := runWatch(args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "watch:", err)
+			return true, 1
+		}
+		return true, 0
+	case "relay":
+		flags := flag.NewFlagSet("relay", flag.ContinueOnError)
+		addr := flags.String("addr", defaultCollabAddr, "address to relay collaboration sessions on")
+		key := flags.String("key", os.Getenv("BUSINESS_CANVAS_RELAY_KEY"), "session key peers must send, required unless the relay is on a loopback address")
+		if err := flags.Parse(args[1:]); err != nil {
+			return true, 2
+		}
+		if err := runRelay(*addr, *key); err != nil {
+			fmt.Fprintln(os.Stderr, "relay:", err)
+			return true, 1
+		}
+		return true, 0
+	case "verify":
+		if err := runVerify(args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "verify:", err)
+			return true, 1
+		}
+		return true, 0
+	case "diff":
+		if err := runDiff(args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "diff:", err)
+			return true, 1
+		}
+		return true, 0
+	case "patch":
+		if err := runPatch(args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "patch:", err)
+			return true, 1
+		}
+		return true, 0
+	case "backup", "restore":
+		run := runBackup
+		if args[0] == "restore" {
+			run = runRestore
+		}
+		if err := run(args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, args[0]+":", err)
+			return true, 1
+		}
+		return true, 0
+	case "serve":
+		flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+		addr := flags.String("addr", defaultServerAddr, "address to serve workspaces and collaboration sessions on")
+		dir := flags.String("data", defaultServerData, "folder to keep workspaces in")
+		adminToken := flags.String("admin-token", os.Getenv("BUSINESS_CANVAS_ADMIN_TOKEN"), "token required to create organizations")
+		publicURL := flags.String("public-url", "", "address browsers reach the server at, for single sign-on (default http://addr)")
+		if err := flags.Parse(args[1:]); err != nil {
+			return true, 2
+		}
+		if err := runServer(*addr, *dir, *adminToken, *publicURL); err != nil {
+			fmt.Fprintln(os.Stderr, "serve:", err)
+			return true, 1
+		}
+		return true, 0
+	}
+	return false, 0
+}
+
+// watchAction is an action run when a watched canvas file changes
+type watchAction func(path string, data CanvasData) error
+
+// watchExports are the exports that can be run on change, writing next to
+// the canvas file or into the output directory
+var watchExports = map[string]struct {
+	Extension string
+	Write     func(w io.Writer, data CanvasData) error
+}{
+	"pdf": {".pdf", func(w io.Writer, data CanvasData) error {
+		env, err := loadEnvironmentMap(data.EnvironmentMap)
+		if err != nil {
+			return err
+		}
+		return writeCanvasPDF(w, pdfOptions{Font: builtinPDFFont().coverText(pdfText(data, nil)), Environment: env}, data)
+	}},
+	"xlsx": {".xlsx", func(w io.Writer, data CanvasData) error {
+		return writeXLSX(w, canvasWorkbook(data, nil))
+	}},
+	"md": {".md", func(w io.Writer, data CanvasData) error {
+		_, err := io.WriteString(w, canvasMarkdown(data))
+		return err
+	}},
+	"summary": {".txt", func(w io.Writer, data CanvasData) error {
+		_, err := io.WriteString(w, executiveSummary(data, time.Now()))
+		return err
+	}},
+}
+
+// parseWatchAction parses an --on-change action: "export <format>" or
+// "webhook <url>", which posts the canvas file to the URL
+func parseWatchAction(action, outDir string) (watchAction, error) {
+	fields := strings.Fields(action)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("unknown action %q, use \"export <format>\" or \"webhook <url>\"", action)
+	}
+
+	switch fields[0] {
+	case "export":
+		export, ok := watchExports[fields[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown export format %q, use pdf, xlsx, md or summary", fields[1])
+		}
+		return func(path string, data CanvasData) error {
+			dir := outDir
+			if dir == "" {
+				dir = filepath.Dir(path)
+			}
+			name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + export.Extension
+			file, err := os.Create(filepath.Join(dir, name))
+			if err != nil {
+				return err
+			}
+			if err := export.Write(file, data); err != nil {
+				file.Close()
+				return err
+			}
+			return file.Close()
+		}, nil
+	case "webhook":
+		url := fields[1]
+		return func(path string, data CanvasData) error {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			resp, err := http.Post(url, "application/json", bytes.NewReader(content))
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				return fmt.Errorf("webhook returned %s", resp.Status)
+			}
+			return nil
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown action %q, use \"export <format>\" or \"webhook <url>\"", action)
+}
+
+// runWatch watches canvas files and re-runs the configured actions every
+// time one changes:
+//
+//	business-canvas watch canvas.json --on-change "export pdf" --on-change "export md"
+func runWatch(args []string) error {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	var onChange stringList
+	flags.Var(&onChange, "on-change", `action to run on change, "export pdf|xlsx|md|summary" or "webhook <url>" (repeatable)`)
+	outDir := flags.String("out", "", "directory for exports, next to the canvas file by default")
+	interval := flags.Duration("interval", 2*time.Second, "how often to check the files for changes")
+	once := flags.Bool("once", false, "run the actions once and exit instead of watching")
+
+	// Files and flags may be given in any order
+	var files []string
+	for len(args) > 0 {
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		files = append(files, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	if len(files) == 0 || len(onChange) == 0 {
+		flags.Usage()
+		return errors.New("give at least one canvas file and --on-change action")
+	}
+
+	var actions []watchAction
+	for _, action := range onChange {
+		run, err := parseWatchAction(action, *outDir)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, run)
+	}
+
+	runActions := func(path string) {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		data, err := readCanvasData(file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			return
+		}
+		// Exports show timestamps as where the canvas was saved
+		applyDateDisplay(data.DateDisplay)
+		for i, run := range actions {
+			if err := run(path, data); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %s: %v\n", path, onChange[i], err)
+				continue
+			}
+			fmt.Printf("%s: %s\n", path, onChange[i])
+		}
+	}
+
+	// Bring the artifacts up to date before waiting for changes
+	for _, path := range files {
+		runActions(path)
+	}
+	if *once {
+		return nil
+	}
+
+	stop := make(chan struct{})
+	for _, path := range files {
+		go watchFile(path, *interval, stop, func() { runActions(path) })
+	}
+	fmt.Printf("Watching %s\n", strings.Join(files, ", "))
+	select {}
+}
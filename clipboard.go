@@ -0,0 +1,125 @@
+package main
+
+import (
+	"html"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+)
+
+// Formats a single section can be copied in
+const (
+	copyPlainText = "Plain Text"
+	copyMarkdown  = "Markdown"
+	copyHTML      = "HTML"
+)
+
+// sectionCopyFormats lists the formats offered by "Copy as"
+var sectionCopyFormats = []string{copyPlainText, copyMarkdown, copyHTML}
+
+// copyAsText puts the whole canvas on the clipboard as a Markdown outline
+// for pasting into emails and chat
+func (c *Canvas) copyAsText() {
+	c.window.Clipboard().SetContent(canvasMarkdown(c.getCurrentData()))
+	dialog.ShowInformation("Copied", "Canvas has been copied to the clipboard", c.window)
+}
+
+// copyAsTable puts the whole canvas on the clipboard as a Markdown table,
+// one row per section
+func (c *Canvas) copyAsTable() {
+	c.window.Clipboard().SetContent(canvasMarkdownTable(c.getCurrentData()))
+	dialog.ShowInformation("Copied", "Canvas has been copied to the clipboard as a table", c.window)
+}
+
+// copySection puts a section on the clipboard in one of the copy formats
+func (c *Canvas) copySection(title, text, format string) {
+	c.window.Clipboard().SetContent(sectionAs(title, text, format))
+}
+
+// sectionAs renders a section in one of the copy formats
+func sectionAs(title, text, format string) string {
+	switch format {
+	case copyMarkdown:
+		var b strings.Builder
+		writeMarkdownSections(&b, "##", []sectionContent{{Title: title, Text: text}})
+		return strings.TrimPrefix(b.String(), "\n")
+	case copyHTML:
+		var b strings.Builder
+		b.WriteString("<h2>" + html.EscapeString(title) + "</h2>\n")
+		if lines := sectionLines(text); len(lines) > 0 {
+			b.WriteString("<ul>\n")
+			for _, line := range lines {
+				b.WriteString("<li>" + html.EscapeString(line) + "</li>\n")
+			}
+			b.WriteString("</ul>\n")
+		}
+		return b.String()
+	default:
+		return title + "\n" + strings.TrimSpace(text) + "\n"
+	}
+}
+
+// canvasMarkdownTable serializes a canvas as a two column Markdown table
+// of sections and their items
+func canvasMarkdownTable(data CanvasData) string {
+	cell := func(text string) string {
+		return strings.ReplaceAll(text, "|", "\\|")
+	}
+	var b strings.Builder
+	b.WriteString("| Section | Content |\n| --- | --- |\n")
+	for _, section := range data.sections() {
+		var items []string
+		for _, line := range sectionLines(section.Text) {
+			items = append(items, cell(line))
+		}
+		b.WriteString("| " + cell(section.Title) + " | " + strings.Join(items, "<br>") + " |\n")
+	}
+	return b.String()
+}
+
+// canvasMarkdown serializes a canvas as a Markdown outline, one heading per
+// section with its lines as bullets
+func canvasMarkdown(data CanvasData) string {
+	var b strings.Builder
+	b.WriteString("# " + data.canvasType().Name + "\n")
+	writeMarkdownSections(&b, "##", data.sections())
+
+	if data.canvasType().ID == canvasTypeBusiness {
+		for _, layer := range canvasLayers {
+			if len(data.layerData(layer.Name)) == 0 {
+				continue
+			}
+			b.WriteString("\n## " + layer.Name + " Layer\n")
+			writeMarkdownSections(&b, "###", data.layerSections(layer))
+		}
+	}
+	return b.String()
+}
+
+func writeMarkdownSections(b *strings.Builder, heading string, sections []sectionContent) {
+	for _, section := range sections {
+		b.WriteString("\n" + heading + " " + section.Title + "\n")
+		lines := sectionLines(section.Text)
+		if len(lines) == 0 {
+			b.WriteString("_(empty)_\n")
+			continue
+		}
+		for _, line := range lines {
+			b.WriteString("- " + line + "\n")
+		}
+	}
+}
+
+// sectionLines splits section text into its non-empty lines, dropping any
+// bullet markers typed by the user
+func sectionLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimLeft(line, "-*•"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
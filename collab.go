@@ -0,0 +1,509 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/net/websocket"
+)
+
+// collabPath is where collaboration sessions are served
+const collabPath = "/collab"
+
+// defaultCollabAddr is the address a session or relay is hosted on by
+// default, reachable from this machine only. Sessions open to others need a
+// session key.
+const defaultCollabAddr = "127.0.0.1:8765"
+
+// collabWriteTimeout is how long a peer may take to accept a message before
+// it is disconnected, so a stalled peer does not hold up the session
+const collabWriteTimeout = 10 * time.Second
+
+// Types of collaboration messages
+const (
+	collabEdit     = "edit"     // a section changed
+	collabHello    = "hello"    // a peer joined and asks for the canvas
+	collabSync     = "sync"     // the canvas for a peer that joined
+	collabPresence = "presence" // presence of a user
+)
+
+// collabMessage is a message of a collaboration session. Edits carry the
+// edit history of a section, merged with the local one so concurrent edits
+// of the same section keep the content of both.
+type collabMessage struct {
+	Type     string                  `json:"type"`
+	Replica  string                  `json:"replica"`
+	To       string                  `json:"to,omitempty"`
+	Section  string                  `json:"section,omitempty"`
+	State    *SectionCRDT            `json:"state,omitempty"`
+	User     string                  `json:"user,omitempty"`
+	States   map[string]*SectionCRDT `json:"states,omitempty"`
+	Presence *PresenceUpdate         `json:"presence,omitempty"`
+}
+
+// collabHub connects peers of a collaboration session, passing every
+// message it gets from one peer on to the others. The app applies the
+// messages it gets, a relay only passes them on.
+type collabHub struct {
+	mu     sync.Mutex
+	conns  map[*websocket.Conn]bool
+	key    string
+	server *http.Server
+	apply  func(collabMessage)
+	closed func(error)
+}
+
+func newCollabHub(apply func(collabMessage), closed func(error)) *collabHub {
+	return &collabHub{conns: make(map[*websocket.Conn]bool), apply: apply, closed: closed}
+}
+
+// checkCollabKey accepts the handshake of peers that send key as bearer
+// token, or of every peer when key is empty
+func checkCollabKey(key string) func(*websocket.Config, *http.Request) error {
+	return func(_ *websocket.Config, r *http.Request) error {
+		if key != "" && subtle.ConstantTimeCompare([]byte(requestToken(r)), []byte(key)) != 1 {
+			return errors.New("a valid session key is required")
+		}
+		return nil
+	}
+}
+
+// listen serves the session on an address for peers with the key of the
+// hub to join
+func (h *collabHub) listen(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(collabPath, websocket.Server{Handshake: checkCollabKey(h.key), Handler: h.serve})
+	h.server = &http.Server{Handler: mux}
+	go func() {
+		if err := h.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) && h.closed != nil {
+			h.closed(err)
+		}
+	}()
+	return nil
+}
+
+// dial joins a session hosted by another app, a relay or a workspace
+// server, sending header with the handshake
+func (h *collabHub) dial(url string, header http.Header) error {
+	config, err := websocket.NewConfig(url, "http://localhost/")
+	if err != nil {
+		return err
+	}
+	config.Header = header
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return err
+	}
+	go func() {
+		h.serve(conn)
+		if h.closed != nil {
+			h.closed(fmt.Errorf("disconnected from %s", url))
+		}
+	}()
+	return nil
+}
+
+// serve reads the messages of a peer until it disconnects
+func (h *collabHub) serve(conn *websocket.Conn) {
+	h.mu.Lock()
+	h.conns[conn] = true
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.conns, conn)
+		h.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		var msg collabMessage
+		if err := websocket.JSON.Receive(conn, &msg); err != nil {
+			return
+		}
+		h.send(msg, conn)
+		if h.apply != nil {
+			h.apply(msg)
+		}
+	}
+}
+
+// send passes a message to every peer except the one it came from. Peers
+// are written to without the lock, so joins and leaves go on while a slow
+// peer takes its message, and one that stalls is disconnected.
+func (h *collabHub) send(msg collabMessage, from *websocket.Conn) {
+	h.mu.Lock()
+	conns := make([]*websocket.Conn, 0, len(h.conns))
+	for conn := range h.conns {
+		if conn != from {
+			conns = append(conns, conn)
+		}
+	}
+	h.mu.Unlock()
+	for _, conn := range conns {
+		conn.SetWriteDeadline(time.Now().Add(collabWriteTimeout))
+		if err := websocket.JSON.Send(conn, msg); err != nil {
+			conn.Close()
+		}
+	}
+}
+
+// peers returns the number of connected peers
+func (h *collabHub) peers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.conns)
+}
+
+// close disconnects every peer and stops serving
+func (h *collabHub) close() {
+	h.mu.Lock()
+	for conn := range h.conns {
+		conn.Close()
+	}
+	h.mu.Unlock()
+	if h.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		h.server.Shutdown(ctx)
+	}
+}
+
+// collabRooms relays separate sessions, a hub for each room that has peers
+type collabRooms struct {
+	mu    sync.Mutex
+	hubs  map[string]*collabHub
+	users map[string]int
+}
+
+func newCollabRooms() *collabRooms {
+	return &collabRooms{hubs: make(map[string]*collabHub), users: make(map[string]int)}
+}
+
+// serve relays the messages of a peer to the others in its room until it
+// disconnects, dropping the room when it was the last one
+func (r *collabRooms) serve(room string, conn *websocket.Conn) {
+	r.mu.Lock()
+	hub := r.hubs[room]
+	if hub == nil {
+		hub = newCollabHub(nil, nil)
+		r.hubs[room] = hub
+	}
+	r.users[room]++
+	r.mu.Unlock()
+
+	hub.serve(conn)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.users[room]--; r.users[room] == 0 {
+		delete(r.hubs, room)
+		delete(r.users, room)
+	}
+}
+
+// peers returns the number of peers connected to any room
+func (r *collabRooms) peers() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	peers := 0
+	for _, users := range r.users {
+		peers += users
+	}
+	return peers
+}
+
+// loopbackAddr reports whether an address only listens on this machine
+func loopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// runRelay runs a collaboration relay for apps to join without one of them
+// hosting the session. Every path below collabPath is a room of its own.
+// Peers must send key, which relays reachable from other machines need.
+func runRelay(addr, key string) error {
+	if key == "" && !loopbackAddr(addr) {
+		return fmt.Errorf("relaying on %s lets anyone reach the rooms, set a key with --key", addr)
+	}
+	rooms := newCollabRooms()
+	mux := http.NewServeMux()
+	mux.Handle(collabPath, websocket.Server{Handshake: checkCollabKey(key), Handler: func(conn *websocket.Conn) {
+		rooms.serve("", conn)
+	}})
+	mux.Handle(collabPath+"/{room}", websocket.Server{Handshake: checkCollabKey(key), Handler: func(conn *websocket.Conn) {
+		rooms.serve(conn.Request().PathValue("room"), conn)
+	}})
+	metrics := newServerMetrics(collabGauge(rooms.peers))
+	fmt.Printf("relaying collaboration sessions on ws://%s%s/<room>\n", addr, collabPath)
+	return http.ListenAndServe(addr, metrics.instrument(mux, func() error { return nil }))
+}
+
+// sectionEntry returns the editor of a section by title, including custom
+// sections and those of the additional layers
+func (c *Canvas) sectionEntry(title string) *widget.Entry {
+	for i, t := range findCanvasType(c.canvasTypeID).Titles {
+		if t == title {
+			return c.standardEntries()[i]
+		}
+	}
+	for _, block := range c.customBlocks {
+		if block.Title == title {
+			return block.entry
+		}
+	}
+	for _, layer := range canvasLayers {
+		for i, t := range layer.Titles {
+			if t == title {
+				return c.layerEntries[layer.Name][i]
+			}
+		}
+	}
+	return nil
+}
+
+// applyCollab applies a message from another peer of the session
+func (c *Canvas) applyCollab(msg collabMessage) {
+	if msg.Replica == c.replicaID() {
+		return
+	}
+	switch msg.Type {
+	case collabEdit:
+		if msg.State != nil {
+			c.queueRemoteEdit(msg.Section, msg.State)
+			if msg.User != "" {
+				c.attributeEdit(msg.Section, msg.User)
+			}
+		}
+	case collabHello:
+		c.collab.send(collabMessage{Type: collabSync, Replica: c.replicaID(), To: msg.Replica, States: c.sectionCRDTData()}, nil)
+	case collabSync:
+		// A peer that joins takes on the canvas of the session
+		if msg.To == c.replicaID() {
+			for section, state := range msg.States {
+				c.collabMu.Lock()
+				c.sectionCRDT[section] = state.clone()
+				c.collabMu.Unlock()
+				c.applyRemoteText(section, state.Text())
+			}
+		}
+	case collabPresence:
+		if msg.Presence != nil {
+			c.applyPresence(*msg.Presence)
+		}
+	}
+}
+
+// applyRemoteText puts a section edited by another peer into its editor,
+// noting the text so the change handler does not send it back
+func (c *Canvas) applyRemoteText(section, text string) {
+	entry := c.sectionEntry(section)
+	if entry == nil || entry.Text == text {
+		return
+	}
+	c.collabMu.Lock()
+	c.remoteTexts[section] = text
+	c.collabMu.Unlock()
+	entry.SetText(text)
+}
+
+// takeRemoteText reports whether a change of a section is the text a peer
+// sent, forgetting it
+func (c *Canvas) takeRemoteText(section, text string) bool {
+	c.collabMu.Lock()
+	defer c.collabMu.Unlock()
+	remote, ok := c.remoteTexts[section]
+	delete(c.remoteTexts, section)
+	return ok && remote == text
+}
+
+// publishEdit sends the edit history of a section to the session after a
+// local edit
+func (c *Canvas) publishEdit(section string) {
+	if c.collab == nil {
+		return
+	}
+	c.collabMu.Lock()
+	state := c.sectionCRDT[section]
+	if state != nil {
+		state = state.clone()
+	}
+	c.collabMu.Unlock()
+	if state == nil {
+		return
+	}
+	c.collab.send(collabMessage{Type: collabEdit, Replica: c.replicaID(), Section: section, State: state, User: c.userName()}, nil)
+}
+
+// startCollab joins the session of hub, sharing presence through it
+func (c *Canvas) startCollab(hub *collabHub, status string) {
+	c.collab = hub
+	c.collabStatus = status
+	c.presenceSink = func(update PresenceUpdate) {
+		identity := c.identity()
+		update.Initials, update.Color = identity.Initials, identity.Color
+		hub.send(collabMessage{Type: collabPresence, Replica: c.replicaID(), Presence: &update}, nil)
+	}
+	c.presenceSink(PresenceUpdate{User: c.userName(), At: time.Now()})
+}
+
+// stopCollab leaves the session, telling the others
+func (c *Canvas) stopCollab() {
+	if c.collab == nil {
+		return
+	}
+	c.presenceSink(PresenceUpdate{User: c.userName(), At: time.Now(), Left: true})
+	c.collab.close()
+	c.collab = nil
+	c.collabStatus = ""
+	c.presenceSink = nil
+	c.collabMu.Lock()
+	c.presence = make(map[string]PresenceUpdate)
+	c.collabMu.Unlock()
+	c.refreshPresence()
+	c.flushQueuedEdits(true)
+}
+
+// collabClosed reports a session that ended without the user leaving it
+func (c *Canvas) collabClosed(hub *collabHub) func(error) {
+	return func(err error) {
+		if c.collab != hub {
+			return
+		}
+		c.stopCollab()
+		dialog.ShowError(err, c.window)
+	}
+}
+
+// joinCollab joins the session at target, reporting whether it could
+func (c *Canvas) joinCollab(target string, header http.Header) bool {
+	hub := newCollabHub(c.applyCollab, nil)
+	hub.closed = c.collabClosed(hub)
+	if err := hub.dial(target, header); err != nil {
+		dialog.ShowError(err, c.window)
+		return false
+	}
+	c.startCollab(hub, "Connected to "+target)
+	hub.send(collabMessage{Type: collabHello, Replica: c.replicaID()}, nil)
+	return true
+}
+
+// showCollaboration hosts or joins a collaboration session, or leaves the
+// current one
+func (c *Canvas) showCollaboration() {
+	var collab *dialog.CustomDialog
+
+	if c.collab != nil {
+		status := widget.NewLabel(fmt.Sprintf("%s, %d peer(s) connected", c.collabStatus, c.collab.peers()))
+		leave := widget.NewButton("Leave Session", func() {
+			c.stopCollab()
+			collab.Hide()
+		})
+		collab = dialog.NewCustom("Collaboration", "Close", container.NewVBox(status, leave), c.window)
+		collab.Show()
+		return
+	}
+
+	addr := widget.NewEntry()
+	addr.SetText(defaultCollabAddr)
+	host := widget.NewButton("Host Session", func() {
+		key, err := newShareToken()
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		hub := newCollabHub(c.applyCollab, nil)
+		hub.key = key
+		hub.closed = c.collabClosed(hub)
+		if err := hub.listen(addr.Text); err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		c.startCollab(hub, "Hosting on "+addr.Text)
+		collab.Hide()
+		reach := "Only this machine can join, host on :8765 to let others in."
+		if !loopbackAddr(addr.Text) {
+			reach = fmt.Sprintf("Others can join at ws://<this machine>%s%s", addr.Text, collabPath)
+		}
+		keyEntry := widget.NewEntry()
+		keyEntry.SetText(key)
+		dialog.ShowCustom("Collaboration", "OK", container.NewVBox(
+			widget.NewLabel(reach),
+			widget.NewLabel("Session key to give those who join:"),
+			keyEntry,
+		), c.window)
+	})
+
+	url := widget.NewEntry()
+	url.SetPlaceHolder("ws://host:8765" + collabPath)
+	sessionKey := widget.NewPasswordEntry()
+	sessionKey.SetPlaceHolder("Session key")
+	join := widget.NewButton("Join Session", func() {
+		target := strings.TrimSpace(url.Text)
+		if !strings.HasPrefix(target, "ws://") && !strings.HasPrefix(target, "wss://") {
+			dialog.ShowError(errors.New("enter a ws:// or wss:// address"), c.window)
+			return
+		}
+		var header http.Header
+		if key := strings.TrimSpace(sessionKey.Text); key != "" {
+			header = http.Header{"Authorization": {"Bearer " + key}}
+		}
+		if c.joinCollab(target, header) {
+			collab.Hide()
+		}
+	})
+
+	canvasName := widget.NewEntry()
+	canvasName.SetText("canvas.json")
+	if c.openFile != nil && c.openFile.Scheme() == remoteScheme {
+		canvasName.SetText(c.openFile.Name())
+	}
+	joinWorkspace := widget.NewButton("Join Canvas", func() {
+		store := c.workspace()
+		if store == nil {
+			return
+		}
+		target, err := store.collabURL(strings.TrimSpace(canvasName.Text))
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if c.joinCollab(target, http.Header{"Authorization": {"Bearer " + store.Token}}) {
+			collab.Hide()
+		}
+	})
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("Host a session on this machine", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, host, addr),
+		widget.NewLabelWithStyle("Join a session or relay", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, join, url),
+		sessionKey,
+		widget.NewLabelWithStyle("Join the session of a workspace canvas", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, joinWorkspace, canvasName),
+	)
+	collab = dialog.NewCustom("Collaboration", "Close", content, c.window)
+	collab.Resize(fyne.NewSize(500, 0))
+	collab.Show()
+}
@@ -0,0 +1,83 @@
+package main
+
+import "time"
+
+// While someone else types in a section of a collaboration session, the
+// section is locked here so two people never type over each other. Edits
+// that arrive for a section this user is typing in wait until they pause,
+// so the text never changes under their cursor. Both sides typing at once
+// keep typing and their edits merge once they pause.
+
+// noteLocalTyping records a keystroke of this user in a section
+func (c *Canvas) noteLocalTyping(section string) {
+	c.collabMu.Lock()
+	c.typedAt[section] = time.Now()
+	c.collabMu.Unlock()
+}
+
+// typingLocally reports whether this user typed in a section recently,
+// with c.collabMu held
+func (c *Canvas) typingLocally(section string, now time.Time) bool {
+	return now.Sub(c.typedAt[section]) < typingTimeout
+}
+
+// queueRemoteEdit applies the edit history of a section from another peer,
+// holding it back while this user is typing in the section
+func (c *Canvas) queueRemoteEdit(section string, state *SectionCRDT) {
+	c.collabMu.Lock()
+	typing := c.typingLocally(section, time.Now())
+	if typing {
+		c.queuedEdits[section] = append(c.queuedEdits[section], state)
+	}
+	c.collabMu.Unlock()
+	if !typing {
+		c.applyRemoteText(section, c.mergeSectionCRDT(section, state))
+	}
+}
+
+// flushQueuedEdits applies the edits held back for sections this user has
+// stopped typing in, or for every section when all is set
+func (c *Canvas) flushQueuedEdits(all bool) {
+	now := time.Now()
+	due := make(map[string][]*SectionCRDT)
+	c.collabMu.Lock()
+	for section, states := range c.queuedEdits {
+		if !all && c.typingLocally(section, now) {
+			continue
+		}
+		delete(c.queuedEdits, section)
+		due[section] = states
+	}
+	c.collabMu.Unlock()
+
+	for section, states := range due {
+		var text string
+		for _, state := range states {
+			text = c.mergeSectionCRDT(section, state)
+		}
+		c.applyRemoteText(section, text)
+	}
+}
+
+// refreshRemoteLocks locks the sections others are typing in, unless this
+// user is typing there too, and unlocks them once they stop. Sections
+// locked from the section menu stay locked. It runs with c.collabMu held.
+func (c *Canvas) refreshRemoteLocks(typing map[string][]string) {
+	now := time.Now()
+	for section := range c.remoteLocks {
+		if len(typing[section]) == 0 {
+			delete(c.remoteLocks, section)
+			if entry := c.sectionEntry(section); entry != nil && !c.lockedSections[section] {
+				entry.Enable()
+			}
+		}
+	}
+	for section := range typing {
+		entry := c.sectionEntry(section)
+		if entry == nil || c.remoteLocks[section] || c.typingLocally(section, now) {
+			continue
+		}
+		c.remoteLocks[section] = true
+		entry.Disable()
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// reportComment is a comment in the comments report, with the status of
+// its thread and who it replies to
+type reportComment struct {
+	Comment
+	Open    bool
+	ReplyTo string
+}
+
+// reportAuthor is the comments of one author on a section
+type reportAuthor struct {
+	Name     string
+	Comments []reportComment
+}
+
+// reportSection is the comments on a section grouped by author
+type reportSection struct {
+	Title   string
+	Authors []reportAuthor
+}
+
+// commentsReport groups comments by section and then by author, in the
+// order they were first made
+func commentsReport(data CanvasData, comments []Comment) (sections []reportSection, open, resolved int) {
+	byID := make(map[string]Comment)
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+	status := make(map[string]bool)
+	for _, thread := range threadComments(comments) {
+		status[thread.Root.ID] = !thread.Root.Resolved
+		for _, reply := range thread.Replies {
+			status[reply.ID] = !thread.Root.Resolved
+		}
+		if thread.Root.Resolved {
+			resolved++
+		} else {
+			open++
+		}
+	}
+
+	order, grouped := groupCommentsBySection(data, comments)
+	for _, title := range order {
+		section := reportSection{Title: title}
+		author := make(map[string]int)
+		for _, comment := range grouped[title] {
+			name := comment.Author
+			if name == "" {
+				name = "Anonymous"
+			}
+			i, ok := author[name]
+			if !ok {
+				i = len(section.Authors)
+				author[name] = i
+				section.Authors = append(section.Authors, reportAuthor{Name: name})
+			}
+			entry := reportComment{Comment: comment, Open: status[comment.ID]}
+			if parent, ok := byID[comment.ParentID]; ok {
+				entry.ReplyTo = parent.Author
+				if entry.ReplyTo == "" {
+					entry.ReplyTo = "Anonymous"
+				}
+			}
+			section.Authors[i].Comments = append(section.Authors[i].Comments, entry)
+		}
+		sections = append(sections, section)
+	}
+	return sections, open, resolved
+}
+
+// line describes a comment on one line of the report
+func (r reportComment) line() string {
+	status := "Resolved"
+	if r.Open {
+		status = "Open"
+	}
+	line := fmt.Sprintf("[%s] %s", status, formatStamp(r.Timestamp))
+	if r.ReplyTo != "" {
+		line += ", reply to " + r.ReplyTo
+	}
+	return line
+}
+
+func writeCommentsMarkdown(w io.Writer, data CanvasData, comments []Comment) error {
+	sections, open, resolved := commentsReport(data, comments)
+	var b strings.Builder
+	b.WriteString("# Comments Report\n")
+	fmt.Fprintf(&b, "\n%d comments, %d open and %d resolved threads\n", len(comments), open, resolved)
+	for _, section := range sections {
+		b.WriteString("\n## " + section.Title + "\n")
+		for _, author := range section.Authors {
+			b.WriteString("\n### " + author.Name + "\n\n")
+			for _, comment := range author.Comments {
+				text := strings.ReplaceAll(strings.TrimSpace(comment.Text), "\n", "\n  ")
+				fmt.Fprintf(&b, "- %s: %s\n", comment.line(), text)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCommentsPDF writes the comments report as an A4 portrait document
+func writeCommentsPDF(w io.Writer, opts pdfOptions, data CanvasData, comments []Comment) error {
+	sections, open, resolved := commentsReport(data, comments)
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "", opts.Font.Regular)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "B", opts.Font.Bold)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddPage()
+
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	width := pageWidth - left - right
+
+	pdf.SetFont(pdfFontFamily, "B", 20)
+	pdf.CellFormat(width, 12, "Comments Report", "", 1, "L", false, 0, "")
+	pdf.SetFont(pdfFontFamily, "", 10)
+	pdf.CellFormat(width, 6, fmt.Sprintf("%d comments, %d open and %d resolved threads", len(comments), open, resolved), "", 1, "L", false, 0, "")
+	for _, section := range sections {
+		pdf.Ln(4)
+		pdf.SetFont(pdfFontFamily, "B", 14)
+		pdf.CellFormat(width, 8, section.Title, "B", 1, "L", false, 0, "")
+		for _, author := range section.Authors {
+			pdf.Ln(2)
+			pdf.SetFont(pdfFontFamily, "B", 11)
+			pdf.CellFormat(width, 6, author.Name, "", 1, "L", false, 0, "")
+			for _, comment := range author.Comments {
+				pdf.SetFont(pdfFontFamily, "B", 9)
+				if comment.Open {
+					pdf.SetTextColor(0xc6, 0x28, 0x28)
+				} else {
+					pdf.SetTextColor(0x2e, 0x7d, 0x32)
+				}
+				pdf.CellFormat(width, 5, comment.line(), "", 1, "L", false, 0, "")
+				pdf.SetTextColor(0, 0, 0)
+				pdf.SetFont(pdfFontFamily, "", 10)
+				pdf.MultiCell(width, 5, comment.Text, "", "", false)
+				pdf.Ln(1)
+			}
+		}
+	}
+	return pdf.Output(w)
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// prefPDFComments enables the comments annex in PDF exports
+const prefPDFComments = "pdfComments"
+
+// allComments gathers the comments recorded on every version, oldest
+// version first, skipping comments carried over between versions
+func (c *Canvas) allComments() []Comment {
+	var comments []Comment
+	seen := make(map[string]bool)
+	for _, version := range c.versions {
+		for _, comment := range version.Comments {
+			if seen[comment.ID] {
+				continue
+			}
+			seen[comment.ID] = true
+			comments = append(comments, comment)
+		}
+	}
+	return comments
+}
+
+// groupCommentsBySection orders comments by canvas section, keeping
+// comments on unknown sections at the end
+func groupCommentsBySection(data CanvasData, comments []Comment) ([]string, map[string][]Comment) {
+	grouped := make(map[string][]Comment)
+	for _, comment := range comments {
+		grouped[comment.Section] = append(grouped[comment.Section], comment)
+	}
+
+	var order []string
+	known := make(map[string]bool)
+	for _, section := range data.sections() {
+		known[section.Title] = true
+		if len(grouped[section.Title]) > 0 {
+			order = append(order, section.Title)
+		}
+	}
+	for _, comment := range comments {
+		if !known[comment.Section] {
+			known[comment.Section] = true
+			order = append(order, comment.Section)
+		}
+	}
+	return order, grouped
+}
+
+// drawCommentsAnnex lists the comments grouped by section on a new page
+func drawCommentsAnnex(pdf *gofpdf.Fpdf, data CanvasData, comments []Comment) {
+	if len(comments) == 0 {
+		return
+	}
+
+	pdf.AddPage()
+	left, top, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	width := pageWidth - left - right
+
+	pdf.SetFont(pdfFontFamily, "B", 20)
+	pdf.SetXY(left, top)
+	pdf.CellFormat(width, 12, "Comments", "", 1, "L", false, 0, "")
+
+	order, grouped := groupCommentsBySection(data, comments)
+	for _, section := range order {
+		pdf.Ln(4)
+		pdf.SetFont(pdfFontFamily, "B", 14)
+		pdf.CellFormat(width, 8, section, "B", 1, "L", false, 0, "")
+		for _, comment := range grouped[section] {
+			author := comment.Author
+			if author == "" {
+				author = "Anonymous"
+			}
+			pdf.SetFont(pdfFontFamily, "B", 10)
+			pdf.CellFormat(width, 6, fmt.Sprintf("%s — %s", author, formatStamp(comment.Timestamp)), "", 1, "L", false, 0, "")
+			pdf.SetFont(pdfFontFamily, "", 10)
+			pdf.MultiCell(width, 5, comment.Text, "", "", false)
+			pdf.Ln(2)
+		}
+	}
+}
+
+// sectionComments returns the comments on a section, oldest first
+func (c *Canvas) sectionComments(section string) []Comment {
+	var comments []Comment
+	for _, comment := range c.allComments() {
+		if comment.Section == section {
+			comments = append(comments, comment)
+		}
+	}
+	return comments
+}
+
+// commentThread is a top-level comment with its replies, oldest first
+type commentThread struct {
+	Root    Comment
+	Replies []Comment
+}
+
+// threadComments groups comments into threads, attaching replies to the
+// top-level comment of their conversation
+func threadComments(comments []Comment) []commentThread {
+	var threads []commentThread
+	thread := make(map[string]int)
+	for _, comment := range comments {
+		if i, ok := thread[comment.ParentID]; ok && comment.ParentID != "" {
+			threads[i].Replies = append(threads[i].Replies, comment)
+			thread[comment.ID] = i
+			continue
+		}
+		thread[comment.ID] = len(threads)
+		threads = append(threads, commentThread{Root: comment})
+	}
+	return threads
+}
+
+// setCommentResolved marks a comment resolved or open on every version
+// that carries it
+func (c *Canvas) setCommentResolved(id string, resolved bool) {
+	for i := range c.versions {
+		for j := range c.versions[i].Comments {
+			if c.versions[i].Comments[j].ID == id {
+				c.versions[i].Comments[j].Resolved = resolved
+			}
+		}
+	}
+}
+
+// addComment stores a comment, or a reply when parentID is set, on the
+// latest version of the current branch, saving a version first when there
+// is none yet
+func (c *Canvas) addComment(section, parentID, text string) {
+	i := c.latestVersionIndex(c.currentBranch())
+	if i < 0 {
+		c.saveCurrentVersion()
+		i = len(c.versions) - 1
+	}
+	identity := c.identity()
+	c.versions[i].Comments = append(c.versions[i].Comments, Comment{
+		ID:             uuid.New().String(),
+		Section:        section,
+		Text:           text,
+		Author:         identity.Name,
+		Timestamp:      nowUTC(),
+		ParentID:       parentID,
+		AuthorInitials: identity.Initials,
+		AuthorColor:    identity.Color,
+	})
+	c.refreshHeatmap()
+}
+
+// unresolvedComments counts the comments in the open threads of a section
+func (c *Canvas) unresolvedComments(section string) int {
+	count := 0
+	for _, thread := range threadComments(c.sectionComments(section)) {
+		if !thread.Root.Resolved {
+			count += 1 + len(thread.Replies)
+		}
+	}
+	return count
+}
+
+// commentButton opens the comments of a section from its header, with a
+// badge counting the unresolved ones
+func (c *Canvas) commentButton(section string) *hintButton {
+	button := newHintButton(c.tooltips, "Comments", "", theme.MailComposeIcon(), func() {
+		c.showComments(section)
+	})
+	c.commentBadges[section] = button
+	c.refreshCommentBadge(section)
+	return button
+}
+
+// refreshCommentBadge updates the unresolved comment count of a section
+func (c *Canvas) refreshCommentBadge(section string) {
+	button := c.commentBadges[section]
+	if button == nil {
+		return
+	}
+	button.Importance = widget.LowImportance
+	button.Text = ""
+	if count := c.unresolvedComments(section); count > 0 {
+		button.Importance = widget.HighImportance
+		button.Text = fmt.Sprintf("%d", count)
+	}
+	button.Refresh()
+}
+
+// commentLabel shows the author, time and text of a comment, highlighting
+// mentions of people
+func commentLabel(comment Comment, people []string) fyne.CanvasObject {
+	author := comment.Author
+	if author == "" {
+		author = "Anonymous"
+	}
+	header := widget.NewLabelWithStyle(fmt.Sprintf("%s — %s", author, formatStamp(comment.Timestamp)), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	text := mentionRichText(comment.Text, people)
+	avatar := identityAvatar(comment.Author, comment.AuthorInitials, comment.AuthorColor, 24)
+	return container.NewVBox(container.NewBorder(nil, nil, avatar, nil, header), text)
+}
+
+// showComments lists the comment threads on a section with a composer to
+// start one, hiding resolved threads unless asked
+func (c *Canvas) showComments(section string) {
+	list := container.NewVBox()
+	showResolved := widget.NewCheck("Show resolved", nil)
+	var refresh func()
+	refresh = func() {
+		list.RemoveAll()
+		threads := threadComments(c.sectionComments(section))
+		hidden := 0
+		for _, thread := range threads {
+			if thread.Root.Resolved && !showResolved.Checked {
+				hidden++
+				continue
+			}
+			list.Add(c.commentThreadCard(section, thread, refresh))
+		}
+		switch {
+		case len(threads) == 0:
+			list.Add(widget.NewLabel("No comments yet"))
+		case hidden > 0:
+			list.Add(widget.NewLabel(fmt.Sprintf("%d resolved thread(s) hidden", hidden)))
+		}
+		c.refreshCommentBadge(section)
+		c.refreshMentions()
+	}
+	showResolved.OnChanged = func(bool) { refresh() }
+	refresh()
+
+	composer := widget.NewMultiLineEntry()
+	composer.SetPlaceHolder("Add a comment, @name to mention someone")
+	composer.Wrapping = fyne.TextWrapWord
+	composer.SetMinRowsVisible(3)
+	addButton := widget.NewButtonWithIcon("Add Comment", theme.MailSendIcon(), func() {
+		text := strings.TrimSpace(composer.Text)
+		if text == "" {
+			return
+		}
+		c.addComment(section, "", text)
+		composer.SetText("")
+		refresh()
+	})
+
+	content := container.NewBorder(showResolved, container.NewBorder(nil, nil, nil, addButton, composer), nil, nil, container.NewVScroll(list))
+	comments := dialog.NewCustom("Comments on "+section, "Close", content, c.window)
+	comments.Resize(fyne.NewSize(500, 500))
+	comments.Show()
+}
+
+// commentThreadCard shows a thread with its replies and the actions to
+// reply to it and resolve or reopen it
+func (c *Canvas) commentThreadCard(section string, thread commentThread, changed func()) fyne.CanvasObject {
+	people := c.people()
+	replies := container.NewVBox()
+	for _, reply := range thread.Replies {
+		replies.Add(commentLabel(reply, people))
+	}
+
+	reply := widget.NewEntry()
+	reply.SetPlaceHolder("Reply")
+	reply.OnSubmitted = func(text string) {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return
+		}
+		c.addComment(section, thread.Root.ID, text)
+		changed()
+	}
+	replyButton := widget.NewButtonWithIcon("", theme.MailReplyIcon(), func() {
+		reply.OnSubmitted(reply.Text)
+	})
+
+	resolveLabel, resolveIcon := "Resolve", theme.ConfirmIcon()
+	if thread.Root.Resolved {
+		resolveLabel, resolveIcon = "Reopen", theme.ContentUndoIcon()
+	}
+	resolveButton := widget.NewButtonWithIcon(resolveLabel, resolveIcon, func() {
+		c.setCommentResolved(thread.Root.ID, !thread.Root.Resolved)
+		changed()
+	})
+
+	title := ""
+	if thread.Root.Resolved {
+		title = "Resolved"
+	}
+	body := container.NewVBox(
+		commentLabel(thread.Root, people),
+		container.NewPadded(replies),
+		container.NewBorder(nil, nil, nil, container.NewHBox(replyButton, resolveButton), reply),
+	)
+	return widget.NewCard("", title, body)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// conflictInterval is how often the folder of the open file is checked for
+// conflict copies left by sync clients
+const conflictInterval = 30 * time.Second
+
+// conflictCopyPattern matches the copies sync clients create next to a file
+// when two machines changed it at once:
+//
+//	canvas (conflicted copy).json                   Dropbox
+//	canvas (Ana's conflicted copy 2024-05-02).json  Dropbox, Nextcloud
+//	canvas 2.json                                   iCloud Drive
+//	canvas-LAPTOP.json                              OneDrive
+//	canvas (1).json                                 Google Drive
+//	canvas.sync-conflict-20240502-143000-ABC.json   Syncthing
+func conflictCopyPattern(name string) *regexp.Regexp {
+	ext := filepath.Ext(name)
+	base := regexp.QuoteMeta(strings.TrimSuffix(name, ext))
+	return regexp.MustCompile(`^` + base + `(` +
+		` \([^)]*(?i:conflict)[^)]*\)` +
+		`| \d+` +
+		`|-[A-Z0-9][A-Z0-9-]+` +
+		`| \(\d+\)` +
+		`|\.sync-conflict-[0-9-]+-[A-Z0-9]+` +
+		`)` + regexp.QuoteMeta(ext) + `$`)
+}
+
+// conflictCopies lists the conflict copies of a local file
+func conflictCopies(path string) []string {
+	files, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil
+	}
+	pattern := conflictCopyPattern(filepath.Base(path))
+	var copies []string
+	for _, file := range files {
+		if !file.IsDir() && pattern.MatchString(file.Name()) {
+			copies = append(copies, filepath.Join(filepath.Dir(path), file.Name()))
+		}
+	}
+	return copies
+}
+
+// watchConflicts checks the folder of the open file for conflict copies
+// until stop is closed
+func (c *Canvas) watchConflicts(file fyne.URI, stop <-chan struct{}) {
+	c.checkConflicts(file)
+	ticker := time.NewTicker(conflictInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.checkConflicts(file)
+		}
+	}
+}
+
+// checkConflicts alerts about conflict copies of the open file that were
+// not reported before
+func (c *Canvas) checkConflicts(file fyne.URI) {
+	var found []string
+	for _, path := range conflictCopies(file.Path()) {
+		if !c.knownConflicts[path] {
+			c.knownConflicts[path] = true
+			found = append(found, path)
+		}
+	}
+	if len(found) == 0 || c.openFile != file {
+		return
+	}
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Sync conflict", file.Name()+" has conflicting copies from your sync client"))
+	c.showConflicts(file, found)
+}
+
+// showConflicts offers to merge each conflict copy into the canvas
+func (c *Canvas) showConflicts(file fyne.URI, copies []string) {
+	message := widget.NewLabel("Your sync client kept these copies of " + file.Name() + " because it was changed on two machines at once. Merge each into your canvas section by section, then delete it.")
+	message.Wrapping = fyne.TextWrapWord
+	list := container.NewVBox(message)
+
+	var conflicts dialog.Dialog
+	for _, path := range copies {
+		path := path
+		merge := widget.NewButton("Merge...", func() {
+			theirs, err := readCanvasURI(storage.NewFileURI(path))
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			conflicts.Hide()
+			c.showMergeChooser(c.canvasFileData(), theirs, func() {
+				c.offerConflictDelete(path)
+			})
+		})
+		remove := widget.NewButton("Delete", func() {
+			conflicts.Hide()
+			c.offerConflictDelete(path)
+		})
+		list.Add(container.NewBorder(nil, nil, nil, container.NewHBox(merge, remove), widget.NewLabel(filepath.Base(path))))
+	}
+
+	conflicts = dialog.NewCustom("Sync Conflict", "Close", list, c.window)
+	conflicts.Resize(fyne.NewSize(550, 0))
+	conflicts.Show()
+}
+
+// offerConflictDelete removes a conflict copy that is no longer needed
+func (c *Canvas) offerConflictDelete(path string) {
+	dialog.ShowConfirm("Delete Conflict Copy?", "Delete "+filepath.Base(path)+"? Save your canvas to keep what you merged from it.", func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := os.Remove(path); err != nil {
+			dialog.ShowError(err, c.window)
+		}
+	}, c.window)
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// prefReplicaID identifies this installation in the edit history of
+// sections so edits from several machines can be merged
+const prefReplicaID = "replicaID"
+
+// crdtID orders elements of a section by Lamport clock, ties broken by
+// replica
+type crdtID struct {
+	Counter int    `json:"c"`
+	Replica string `json:"r,omitempty"`
+}
+
+// after reports whether id sorts after other
+func (id crdtID) after(other crdtID) bool {
+	if id.Counter != other.Counter {
+		return id.Counter > other.Counter
+	}
+	return id.Replica > other.Replica
+}
+
+// crdtElement is a character of a section with the element it was inserted
+// after. Deleted characters stay as tombstones so concurrent edits can
+// still be placed relative to them.
+type crdtElement struct {
+	ID      crdtID `json:"id"`
+	Origin  crdtID `json:"o"`
+	Value   string `json:"v"`
+	Deleted bool   `json:"d,omitempty"`
+}
+
+// SectionCRDT is the text of a section as a replicated growable array
+// (RGA), so concurrent edits to the same section merge character by
+// character instead of one overwriting the other
+type SectionCRDT struct {
+	Elements []crdtElement `json:"elements"`
+}
+
+// Text returns the visible text of the section
+func (s *SectionCRDT) Text() string {
+	var b strings.Builder
+	for _, e := range s.Elements {
+		if !e.Deleted {
+			b.WriteString(e.Value)
+		}
+	}
+	return b.String()
+}
+
+func (s *SectionCRDT) clone() *SectionCRDT {
+	return &SectionCRDT{Elements: slices.Clone(s.Elements)}
+}
+
+// clock returns the highest counter seen
+func (s *SectionCRDT) clock() int {
+	clock := 0
+	for _, e := range s.Elements {
+		clock = max(clock, e.ID.Counter)
+	}
+	return clock
+}
+
+// index returns the position of an element, -1 for the start of the text
+// or an unknown element
+func (s *SectionCRDT) index(id crdtID) int {
+	for i, e := range s.Elements {
+		if e.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// integrate places an element after its origin, skipping elements that sort
+// after it so every replica ends up with the same order
+func (s *SectionCRDT) integrate(e crdtElement) {
+	if i := s.index(e.ID); i >= 0 {
+		s.Elements[i].Deleted = s.Elements[i].Deleted || e.Deleted
+		return
+	}
+	i := s.index(e.Origin) + 1
+	for i < len(s.Elements) && s.Elements[i].ID.after(e.ID) {
+		i++
+	}
+	s.Elements = append(s.Elements, crdtElement{})
+	copy(s.Elements[i+1:], s.Elements[i:])
+	s.Elements[i] = e
+}
+
+// Update records the edit turning the section into text as inserts and
+// deletes by a replica
+func (s *SectionCRDT) Update(replica, text string) {
+	var visible []int
+	for i, e := range s.Elements {
+		if !e.Deleted {
+			visible = append(visible, i)
+		}
+	}
+	old := make([]string, len(visible))
+	for i, index := range visible {
+		old[i] = s.Elements[index].Value
+	}
+	runes := []rune(text)
+
+	// Only the part between the common prefix and suffix changed
+	prefix := 0
+	for prefix < len(old) && prefix < len(runes) && old[prefix] == string(runes[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(runes)-prefix &&
+		old[len(old)-1-suffix] == string(runes[len(runes)-1-suffix]) {
+		suffix++
+	}
+
+	for _, index := range visible[prefix : len(old)-suffix] {
+		s.Elements[index].Deleted = true
+	}
+	var origin crdtID
+	if prefix > 0 {
+		origin = s.Elements[visible[prefix-1]].ID
+	}
+	clock := s.clock()
+	for _, r := range runes[prefix : len(runes)-suffix] {
+		clock++
+		id := crdtID{Counter: clock, Replica: replica}
+		s.integrate(crdtElement{ID: id, Origin: origin, Value: string(r)})
+		origin = id
+	}
+}
+
+// Merge adds the edits of another copy of the section. Elements are
+// integrated in clock order so their origins are always already present.
+func (s *SectionCRDT) Merge(other *SectionCRDT) {
+	elements := slices.Clone(other.Elements)
+	slices.SortFunc(elements, func(a, b crdtElement) int {
+		switch {
+		case a.ID.after(b.ID):
+			return 1
+		case b.ID.after(a.ID):
+			return -1
+		}
+		return 0
+	})
+	for _, e := range elements {
+		s.integrate(e)
+	}
+}
+
+// mergedText returns the text of two copies of a section with the edits
+// of both, when both have an edit history
+func mergedText(mine, theirs *SectionCRDT) (string, bool) {
+	if mine == nil || theirs == nil {
+		return "", false
+	}
+	merged := mine.clone()
+	merged.Merge(theirs)
+	return merged.Text(), true
+}
+
+// mergeSectionCRDT adds the edits of another copy of a section to its
+// state, returning the merged text
+func (c *Canvas) mergeSectionCRDT(section string, other *SectionCRDT) string {
+	c.collabMu.Lock()
+	defer c.collabMu.Unlock()
+	state := c.sectionCRDT[section]
+	if state == nil {
+		state = &SectionCRDT{}
+		c.sectionCRDT[section] = state
+	}
+	state.Merge(other)
+	return state.Text()
+}
+
+// replicaID returns the identifier of this installation, created on first
+// use
+func (c *Canvas) replicaID() string {
+	id := c.prefs.String(prefReplicaID)
+	if id == "" {
+		id = uuid.New().String()[:8]
+		c.prefs.SetString(prefReplicaID, id)
+	}
+	return id
+}
+
+// recordSectionCRDT applies an edit of a section to its CRDT state
+func (c *Canvas) recordSectionCRDT(section, text string) {
+	replica := c.replicaID()
+	c.collabMu.Lock()
+	defer c.collabMu.Unlock()
+	state := c.sectionCRDT[section]
+	if state == nil && text == "" {
+		return
+	}
+	if state == nil {
+		state = &SectionCRDT{}
+		c.sectionCRDT[section] = state
+	}
+	if state.Text() != text {
+		state.Update(replica, text)
+	}
+}
+
+// sectionCRDTData copies the CRDT state of every section for saving
+func (c *Canvas) sectionCRDTData() map[string]*SectionCRDT {
+	c.collabMu.Lock()
+	defer c.collabMu.Unlock()
+	if len(c.sectionCRDT) == 0 {
+		return nil
+	}
+	states := make(map[string]*SectionCRDT, len(c.sectionCRDT))
+	for section, state := range c.sectionCRDT {
+		states[section] = state.clone()
+	}
+	return states
+}
+
+// resetSectionCRDT replaces the CRDT state, e.g. after loading a file, and
+// brings it up to date with the section texts in case the file was edited
+// without it
+func (c *Canvas) resetSectionCRDT(states map[string]*SectionCRDT) {
+	c.collabMu.Lock()
+	c.sectionCRDT = make(map[string]*SectionCRDT, len(states))
+	for section, state := range states {
+		c.sectionCRDT[section] = state.clone()
+	}
+	c.collabMu.Unlock()
+	for _, section := range c.getCurrentData().sections() {
+		c.recordSectionCRDT(section.Title, section.Text)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// edited returns a copy of a section with an edit by a replica
+func edited(s *SectionCRDT, replica, text string) *SectionCRDT {
+	next := s.clone()
+	next.Update(replica, text)
+	return next
+}
+
+func TestSectionCRDTMergesConcurrentEdits(t *testing.T) {
+	base := &SectionCRDT{}
+	base.Update("a", "abc")
+
+	deleted := edited(base, "a", "ac")
+	inserted := edited(base, "b", "aXbc")
+
+	for name, merged := range map[string]*SectionCRDT{"mine first": deleted.clone(), "theirs first": inserted.clone()} {
+		if name == "mine first" {
+			merged.Merge(inserted)
+		} else {
+			merged.Merge(deleted)
+		}
+		if got := merged.Text(); got != "aXc" {
+			t.Errorf("%s: merged text is %q, want %q", name, got, "aXc")
+		}
+	}
+}
+
+func TestSectionCRDTKeepsDeletesThroughFiles(t *testing.T) {
+	base := &SectionCRDT{}
+	base.Update("a", "abc")
+	old := base.clone()
+
+	saved := edited(base, "a", "ac")
+	content, err := json.Marshal(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded SectionCRDT
+	if err := json.Unmarshal(content, &loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	// A copy made before the delete must not bring the text back
+	old.Update("b", "abcX")
+	text, ok := mergedText(&loaded, old)
+	if !ok || text != "acX" {
+		t.Errorf("merged text is %q, want %q", text, "acX")
+	}
+}
+
+func TestSectionCRDTMergeIsIdempotent(t *testing.T) {
+	base := &SectionCRDT{}
+	base.Update("a", "hello")
+	mine := edited(base, "a", "hello world")
+	theirs := edited(base, "b", "hey hello")
+
+	once := mine.clone()
+	once.Merge(theirs)
+	twice := once.clone()
+	twice.Merge(theirs)
+	twice.Merge(mine)
+	if once.Text() != twice.Text() || len(once.Elements) != len(twice.Elements) {
+		t.Errorf("merging again changed %q to %q", once.Text(), twice.Text())
+	}
+
+	other := theirs.clone()
+	other.Merge(mine)
+	if other.Text() != once.Text() {
+		t.Errorf("replicas diverged: %q and %q", once.Text(), other.Text())
+	}
+}
+
+func TestMergedTextNeedsBothHistories(t *testing.T) {
+	state := &SectionCRDT{}
+	state.Update("a", "text")
+	if _, ok := mergedText(state, nil); ok {
+		t.Error("merged a section without the history of the other copy")
+	}
+}
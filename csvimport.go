@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// fuzzyMatchBlock finds the standard block closest to a section name as
+// typed in a spreadsheet, tolerating typos, abbreviations and extra words.
+// It returns -1 when no block is close enough.
+func fuzzyMatchBlock(kind *canvasType, name string) int {
+	if index := matchStandardBlock(kind, name); index >= 0 {
+		return index
+	}
+	name = normalizeBlockName(name)
+	if name == "" {
+		return -1
+	}
+
+	best, bestDistance := -1, 0
+	for i, title := range kind.Titles {
+		candidates := []string{normalizeBlockName(title)}
+		if i < len(strategyzerBlockTypes) {
+			candidates = append(candidates, normalizeBlockName(strategyzerBlockTypes[i]))
+		}
+		for _, candidate := range candidates {
+			// Names such as "Partners" or "Key partners (suppliers)"
+			if len(name) >= 4 && (strings.Contains(candidate, name) || strings.Contains(name, candidate)) {
+				return i
+			}
+			distance := levenshtein(name, candidate)
+			if distance <= len(candidate)/4 && (best < 0 || distance < bestDistance) {
+				best, bestDistance = i, distance
+			}
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current := make([]int, len(rb)+1)
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(rb)]
+}
+
+// readCanvasCSV reads a two-column CSV of section and content into a canvas
+// of the given type. Rows for the same section are joined line by line,
+// and the names of rows that match no section are returned.
+func readCanvasCSV(reader io.Reader, kind *canvasType) (CanvasData, []string, error) {
+	data := CanvasData{CanvasType: kind.ID}
+
+	records := csv.NewReader(reader)
+	records.FieldsPerRecord = -1
+	records.TrimLeadingSpace = true
+	rows, err := records.ReadAll()
+	if err != nil {
+		return data, nil, err
+	}
+
+	var skipped []string
+	matched := false
+	for i, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		name, content := strings.TrimSpace(row[0]), strings.TrimSpace(row[1])
+		index := fuzzyMatchBlock(kind, name)
+		if index < 0 {
+			// A header row such as "section,content" is not worth reporting
+			if i > 0 && name != "" {
+				skipped = append(skipped, name)
+			}
+			continue
+		}
+		matched = true
+		if content != "" {
+			addSticky(&data, index, content)
+		}
+	}
+	if !matched {
+		return data, skipped, errors.New("no rows match a canvas section")
+	}
+	return data, skipped, nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// CustomSection is a user-defined block appended to the standard nine
+type CustomSection struct {
+	Title  string `json:"title"`
+	Prompt string `json:"prompt"`
+	Text   string `json:"text"`
+}
+
+// customBlock is the on-screen editor for a custom section
+type customBlock struct {
+	Title  string
+	Prompt string
+	entry  *widget.Entry
+}
+
+func (c *Canvas) newCustomBlock(title, prompt string) *customBlock {
+	block := &customBlock{
+		Title:  title,
+		Prompt: prompt,
+		entry:  widget.NewMultiLineEntry(),
+	}
+	block.entry.SetPlaceHolder(prompt)
+	c.setupDynamicValidation(block.entry, title)
+	return block
+}
+
+// customData returns the custom sections with their current text
+func (c *Canvas) customData() []CustomSection {
+	var sections []CustomSection
+	for _, block := range c.customBlocks {
+		sections = append(sections, CustomSection{
+			Title:  block.Title,
+			Prompt: block.Prompt,
+			Text:   block.entry.Text,
+		})
+	}
+	return sections
+}
+
+// setCustomSections updates the custom blocks in place when the titles and
+// prompts match, otherwise rebuilds them and the layout
+func (c *Canvas) setCustomSections(sections []CustomSection) {
+	same := len(sections) == len(c.customBlocks)
+	for i := 0; same && i < len(sections); i++ {
+		same = sections[i].Title == c.customBlocks[i].Title && sections[i].Prompt == c.customBlocks[i].Prompt
+	}
+
+	if !same {
+		c.customBlocks = nil
+		for _, section := range sections {
+			c.customBlocks = append(c.customBlocks, c.newCustomBlock(section.Title, section.Prompt))
+		}
+		c.refreshLayout()
+	}
+	for i, section := range sections {
+		c.customBlocks[i].entry.SetText(section.Text)
+	}
+}
+
+// refreshLayout rebuilds the section grid, e.g. after custom blocks change
+func (c *Canvas) refreshLayout() {
+	if c.mainArea == nil {
+		return
+	}
+	c.mainArea.Objects = []fyne.CanvasObject{c.createMainContent()}
+	c.mainArea.Refresh()
+	c.updateProgress()
+}
+
+// createCustomRow lays out the custom blocks side by side
+func (c *Canvas) createCustomRow() *fyne.Container {
+	row := container.NewGridWithColumns(len(c.customBlocks))
+	for _, block := range c.customBlocks {
+		row.Add(c.withTypingIndicator(block.Title, c.createSection(block.Title, block.entry, block.Prompt, c.validationMarker(block.Title), c.commentButton(block.Title))))
+	}
+	return row
+}
+
+// validateCustomTitle rejects empty titles and titles already in use by a
+// section of any canvas type, a layer or another custom section, as
+// settings such as word limits are kept by section title
+func (c *Canvas) validateCustomTitle(title string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return errors.New("title is required")
+	}
+	taken := func(other string) bool { return strings.EqualFold(other, title) }
+	for _, kind := range canvasTypes {
+		if slices.ContainsFunc(kind.Titles, taken) {
+			return fmt.Errorf("the %s has a section with this title", kind.Name)
+		}
+	}
+	for _, layer := range canvasLayers {
+		if slices.ContainsFunc(layer.Titles[:], taken) {
+			return fmt.Errorf("the %s layer has a section with this title", layer.Name)
+		}
+	}
+	for _, block := range c.customBlocks {
+		if taken(block.Title) {
+			return errors.New("a section with this title already exists")
+		}
+	}
+	return nil
+}
+
+// showCustomSections manages the custom blocks of the canvas
+func (c *Canvas) showCustomSections() {
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(c.customBlocks) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.DeleteIcon(), nil), widget.NewLabel("Template"))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(c.customBlocks[id].Title)
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				title := c.customBlocks[id].Title
+				dialog.ShowConfirm("Remove Section", "Remove \""+title+"\" and its content?", func(remove bool) {
+					if !remove {
+						return
+					}
+					c.customBlocks = append(c.customBlocks[:id], c.customBlocks[id+1:]...)
+					c.refreshLayout()
+					list.Refresh()
+				}, c.window)
+			}
+		},
+	)
+
+	addButton := widget.NewButtonWithIcon("Add Section", theme.ContentAddIcon(), func() {
+		titleEntry := widget.NewEntry()
+		titleEntry.SetPlaceHolder("e.g. Key Metrics")
+		titleEntry.Validator = c.validateCustomTitle
+		promptEntry := widget.NewEntry()
+		promptEntry.SetPlaceHolder("Guiding question shown in the empty section")
+
+		dialog.ShowForm("Add Section", "Add", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Title", titleEntry),
+			widget.NewFormItem("Prompt", promptEntry),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			title := strings.TrimSpace(titleEntry.Text)
+			if err := c.validateCustomTitle(title); err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			c.customBlocks = append(c.customBlocks, c.newCustomBlock(title, promptEntry.Text))
+			c.refreshLayout()
+			list.Refresh()
+		}, c.window)
+	})
+
+	content := container.NewBorder(nil, addButton, nil, nil, list)
+	custom := dialog.NewCustom("Custom Sections", "Close", content, c.window)
+	custom.Resize(fyne.NewSize(400, 300))
+	custom.Show()
+}
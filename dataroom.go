@@ -0,0 +1,180 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// dataRoomIndex is the landing page of a data room bundle
+var dataRoomIndex = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html{{with .Lang}} lang="{{.}}"{{end}}>
+<head>
+<meta charset="utf-8">
+<title>Business Canvas - Data Room</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+pre { white-space: pre-wrap; margin: 0; }
+</style>
+</head>
+<body>
+<h1>Business Canvas</h1>
+<p>Generated {{.Generated.Format "2006-01-02 15:04:05"}}</p>
+<h2>Documents</h2>
+<ul>
+<li><a href="canvas.pdf">Canvas (PDF)</a></li>
+<li><a href="canvas.json">Canvas (JSON)</a></li>
+<li><a href="changelog.json">Version changelog (JSON)</a></li>
+<li><a href="assumptions.csv">Assumptions log (CSV)</a></li>
+</ul>
+<h2>Canvas</h2>
+<table>
+{{range .Sections}}<tr><th>{{.Title}}</th><td><pre>{{.Text}}</pre></td></tr>
+{{end}}</table>
+<h2>Assumptions log</h2>
+{{if .Assumptions}}<table>
+<tr><th>Section</th><th>Assumption</th><th>Status</th><th>Evidence</th></tr>
+{{range .Assumptions}}<tr><td>{{.Section}}</td><td>{{.Statement}}</td><td>{{.Status}}</td><td>{{range .Files}}<a href="{{.Path}}">{{.Name}}</a><br>{{else}}None{{end}}</td></tr>
+{{end}}</table>{{else}}<p>No assumptions recorded.</p>{{end}}
+<h2>Version changelog</h2>
+{{if .Versions}}<table>
+<tr><th>Saved</th><th>Author</th><th>Name</th><th>What changed</th><th>Comments</th></tr>
+{{range .Versions}}<tr><td>{{.Timestamp.Format "2006-01-02 15:04:05"}}</td><td>{{.Author}}</td><td>{{.Name}}</td><td>{{.Note}}</td><td>{{len .Comments}}</td></tr>
+{{end}}</table>{{else}}<p>No versions recorded.</p>{{end}}
+{{with .Stats}}<footer><p><small>{{.}}</small></p></footer>{{end}}
+</body>
+</html>
+`))
+
+func (c *Canvas) exportDataRoom() {
+	data := c.canvasFileData()
+	versions := c.versions
+	opts, err := c.pdfOptions()
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+
+	dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		err = writeDataRoom(writer, opts, data, versions)
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+
+		dialog.ShowInformation("Success", "Data room bundle has been exported successfully", c.window)
+	}, c.window)
+}
+
+// dataRoomFile is a file of the bundle linked from the index
+type dataRoomFile struct {
+	Name string
+	Path string
+}
+
+// dataRoomAssumption is a row of the assumptions log of the index
+type dataRoomAssumption struct {
+	Section   string
+	Statement string
+	Status    string
+	Files     []dataRoomFile
+}
+
+// writeDataRoom writes a zip holding the canvas PDF and JSON, the version
+// changelog, the assumptions log with the evidence attached to it and an
+// index.html linking them together
+func writeDataRoom(w io.Writer, opts pdfOptions, data CanvasData, versions []Version) error {
+	archive := zip.NewWriter(w)
+
+	// The data room is shared outside the team, presenter notes stay out
+	opts.PresenterNotes = false
+	data = withoutPresenterNotes(data)
+	shared := make([]Version, len(versions))
+	for i, version := range versions {
+		version.Data = withoutPresenterNotes(version.Data)
+		shared[i] = version
+	}
+	versions = shared
+
+	var pdf bytes.Buffer
+	if err := writeCanvasPDF(&pdf, opts, data); err != nil {
+		return err
+	}
+	canvasJSON, err := json.MarshalIndent(data, "", "    ")
+	if err != nil {
+		return err
+	}
+	changelogJSON, err := json.MarshalIndent(versions, "", "    ")
+	if err != nil {
+		return err
+	}
+	var assumptionsLog bytes.Buffer
+	if err := writeAssumptionsLog(&assumptionsLog, data.Assumptions); err != nil {
+		return err
+	}
+
+	type bundleFile struct {
+		Name string
+		Body []byte
+	}
+	var evidence []bundleFile
+	var assumptions []dataRoomAssumption
+	for i, assumption := range data.Assumptions {
+		row := dataRoomAssumption{Section: assumption.Section, Statement: assumption.Statement, Status: assumption.status()}
+		for j, file := range assumption.Evidence {
+			name := evidencePath(i, j, file.Name)
+			row.Files = append(row.Files, dataRoomFile{Name: file.Name, Path: name})
+			evidence = append(evidence, bundleFile{name, file.Content})
+		}
+		assumptions = append(assumptions, row)
+	}
+
+	var index bytes.Buffer
+	err = dataRoomIndex.Execute(&index, map[string]interface{}{
+		"Generated":   time.Now(),
+		"Sections":    data.sections(),
+		"Assumptions": assumptions,
+		"Versions":    versions,
+		"Stats":       opts.Stats,
+		"Lang":        data.language(),
+	})
+	if err != nil {
+		return err
+	}
+
+	files := append([]bundleFile{
+		{"index.html", index.Bytes()},
+		{"canvas.pdf", pdf.Bytes()},
+		{"canvas.json", canvasJSON},
+		{"changelog.json", changelogJSON},
+		{"assumptions.csv", assumptionsLog.Bytes()},
+	}, evidence...)
+	for _, file := range files {
+		f, err := archive.Create(file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(file.Body); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
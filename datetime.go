@@ -0,0 +1,183 @@
+package main
+
+import (
+	"strings"
+	"time"
+	_ "time/tzdata" // named time zones on systems without a zone database
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Timestamps are stored in UTC, so canvases edited on machines in different
+// time zones sort and compare the same, and shown in the time zone and date
+// format chosen in Settings.
+
+// Preference keys of the date display
+const (
+	prefDateFormat = "dateFormat"
+	prefTimeZone   = "timeZone"
+)
+
+// DateDisplay is the date format and time zone of the settings, saved with
+// the canvas
+type DateDisplay struct {
+	Format   string `json:"format,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// dateFormat is a way of writing dates and times
+type dateFormat struct {
+	Name    string
+	Date    string
+	Clock   string
+	Seconds string
+}
+
+// dateFormats are the formats to choose from, automatic first
+var dateFormats = []dateFormat{
+	{Name: "Automatic"},
+	{Name: "ISO (2006-01-02 15:04)", Date: "2006-01-02", Clock: "15:04", Seconds: "15:04:05"},
+	{Name: "US (01/02/2006 3:04 PM)", Date: "01/02/2006", Clock: "3:04 PM", Seconds: "3:04:05 PM"},
+	{Name: "European (02/01/2006 15:04)", Date: "02/01/2006", Clock: "15:04", Seconds: "15:04:05"},
+	{Name: "Dotted (02.01.2006 15:04)", Date: "02.01.2006", Clock: "15:04", Seconds: "15:04:05"},
+	{Name: "Long (2 Jan 2006 15:04)", Date: "2 Jan 2006", Clock: "15:04", Seconds: "15:04:05"},
+}
+
+// dotLocales are languages writing dates with dots
+var dotLocales = map[string]bool{"de": true, "cs": true, "da": true, "fi": true, "nb": true, "pl": true, "ru": true, "sk": true, "tr": true, "uk": true}
+
+// displayFormat and displayZone are what timestamps are shown in
+var (
+	displayFormat = dateFormats[1]
+	displayZone   = time.Local
+)
+
+// nowUTC is the time to store, in UTC and without the monotonic clock
+func nowUTC() time.Time {
+	return time.Now().UTC().Round(0)
+}
+
+// localeDateFormat picks the format of a locale such as en-US or de-DE
+func localeDateFormat(locale string) dateFormat {
+	parts := strings.Split(locale, "-")
+	language, region := parts[0], parts[len(parts)-1]
+	switch {
+	case region == "US" || region == "PH":
+		return dateFormats[2]
+	case dotLocales[language]:
+		return dateFormats[4]
+	case language == "en" || language == "fr" || language == "es" || language == "it" || language == "pt" || language == "nl":
+		return dateFormats[3]
+	}
+	return dateFormats[1]
+}
+
+// findDateFormat looks up a format by name, automatic for unknown names
+func findDateFormat(name string) dateFormat {
+	for _, format := range dateFormats[1:] {
+		if format.Name == name {
+			return format
+		}
+	}
+	return localeDateFormat(lang.SystemLocale().String())
+}
+
+// loadTimeZone looks up a time zone by IANA name, local when empty
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// applyDateSettings sets the date format and time zone timestamps are shown
+// in from the preferences
+func applyDateSettings(prefs fyne.Preferences) {
+	applyDateDisplay(dateDisplay(prefs))
+}
+
+// dateDisplay is the date display of the preferences, nil when automatic
+func dateDisplay(prefs fyne.Preferences) *DateDisplay {
+	display := DateDisplay{Format: prefs.String(prefDateFormat), TimeZone: prefs.String(prefTimeZone)}
+	if display == (DateDisplay{}) {
+		return nil
+	}
+	return &display
+}
+
+// applyDateDisplay sets the date format and time zone timestamps are shown
+// in, automatic for nil
+func applyDateDisplay(display *DateDisplay) {
+	if display == nil {
+		display = &DateDisplay{}
+	}
+	displayFormat = findDateFormat(display.Format)
+	displayZone = time.Local
+	if zone, err := loadTimeZone(display.TimeZone); err == nil {
+		displayZone = zone
+	}
+}
+
+// formatStamp shows the date and time of a timestamp
+func formatStamp(t time.Time) string {
+	return t.In(displayZone).Format(displayFormat.Date + " " + displayFormat.Clock)
+}
+
+// formatStampSeconds shows the date and time of a timestamp to the second
+func formatStampSeconds(t time.Time) string {
+	return t.In(displayZone).Format(displayFormat.Date + " " + displayFormat.Seconds)
+}
+
+// formatDate shows the date of a timestamp
+func formatDate(t time.Time) string {
+	return t.In(displayZone).Format(displayFormat.Date)
+}
+
+// formatClock shows the time of day of a timestamp
+func formatClock(t time.Time) string {
+	return t.In(displayZone).Format(displayFormat.Clock)
+}
+
+// dateSetting picks the date format and time zone in the settings dialog
+func (c *Canvas) dateSetting() fyne.CanvasObject {
+	var names []string
+	for _, format := range dateFormats {
+		names = append(names, format.Name)
+	}
+	formatSelect := widget.NewSelect(names, func(name string) {
+		if name == dateFormats[0].Name {
+			name = ""
+		}
+		c.prefs.SetString(prefDateFormat, name)
+		applyDateSettings(c.prefs)
+		c.refreshLastEdit()
+	})
+	if current := c.prefs.String(prefDateFormat); current != "" {
+		formatSelect.SetSelected(current)
+	} else {
+		formatSelect.SetSelected(dateFormats[0].Name)
+	}
+
+	zoneEntry := widget.NewSelectEntry([]string{"Local", "UTC", "America/New_York", "America/Los_Angeles", "Europe/London", "Europe/Berlin", "Asia/Kolkata", "Asia/Tokyo", "Australia/Sydney"})
+	zoneEntry.SetPlaceHolder("Local")
+	zoneEntry.SetText(c.prefs.String(prefTimeZone))
+	zoneEntry.Validator = func(name string) error {
+		_, err := loadTimeZone(strings.TrimSpace(name))
+		return err
+	}
+	zoneEntry.OnChanged = func(name string) {
+		name = strings.TrimSpace(name)
+		if _, err := loadTimeZone(name); err != nil {
+			return
+		}
+		c.prefs.SetString(prefTimeZone, name)
+		applyDateSettings(c.prefs)
+		c.refreshLastEdit()
+	}
+
+	return container.NewVBox(formatSelect,
+		container.NewBorder(nil, nil, widget.NewLabel("Time zone"), nil, zoneEntry))
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Operations of a line diff
+const (
+	diffEqual = iota
+	diffDelete
+	diffInsert
+)
+
+// diffLine is a line of a diff with its operation
+type diffLine struct {
+	Op   int
+	Text string
+}
+
+// sectionDiff is the line diff of a section that changed
+type sectionDiff struct {
+	Title string
+	Lines []diffLine
+}
+
+// diffLines computes a line diff turning a into b from their longest common
+// subsequence
+func diffLines(a, b string) []diffLine {
+	from, to := strings.Split(a, "\n"), strings.Split(b, "\n")
+	if a == "" {
+		from = nil
+	}
+	if b == "" {
+		to = nil
+	}
+
+	// lcs[i][j] is the common subsequence length of from[i:] and to[j:]
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			lines = append(lines, diffLine{diffEqual, from[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{diffDelete, from[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{diffInsert, to[j]})
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		lines = append(lines, diffLine{diffDelete, from[i]})
+	}
+	for ; j < len(to); j++ {
+		lines = append(lines, diffLine{diffInsert, to[j]})
+	}
+	return lines
+}
+
+// diffCanvases returns the diff of every section that differs between two
+// canvases, matching sections by title
+func diffCanvases(from, to CanvasData) []sectionDiff {
+	fromText := make(map[string]string)
+	var titles []string
+	for _, section := range from.sections() {
+		fromText[section.Title] = section.Text
+		titles = append(titles, section.Title)
+	}
+	toText := make(map[string]string)
+	for _, section := range to.sections() {
+		if _, ok := fromText[section.Title]; !ok {
+			titles = append(titles, section.Title)
+		}
+		toText[section.Title] = section.Text
+	}
+
+	var diffs []sectionDiff
+	for _, title := range titles {
+		if fromText[title] != toText[title] {
+			diffs = append(diffs, sectionDiff{title, diffLines(fromText[title], toText[title])})
+		}
+	}
+	return diffs
+}
+
+// diffRichText renders section diffs with removed lines in red and added
+// lines in green
+func diffRichText(diffs []sectionDiff) *widget.RichText {
+	var segments []widget.RichTextSegment
+	for _, diff := range diffs {
+		segments = append(segments, &widget.TextSegment{Style: widget.RichTextStyleSubHeading, Text: diff.Title})
+		for _, line := range diff.Lines {
+			style := widget.RichTextStyleParagraph
+			prefix := "  "
+			switch line.Op {
+			case diffDelete:
+				style.ColorName = theme.ColorNameError
+				prefix = "- "
+			case diffInsert:
+				style.ColorName = theme.ColorNameSuccess
+				prefix = "+ "
+			}
+			segments = append(segments, &widget.TextSegment{Style: style, Text: prefix + line.Text})
+		}
+	}
+	text := widget.NewRichText(segments...)
+	text.Wrapping = fyne.TextWrapWord
+	return text
+}
+
+// showRestoreSection lets the user pick one section that differs from a
+// version and restores only that section
+func (c *Canvas) showRestoreSection(version Version) {
+	diffs := diffCanvases(c.getCurrentData(), version.Data)
+	if len(diffs) == 0 {
+		dialog.ShowInformation("Restore Section", "This version matches the current canvas", c.window)
+		return
+	}
+
+	titles := make([]string, len(diffs))
+	for i, diff := range diffs {
+		titles[i] = diff.Title
+	}
+	preview := container.NewStack()
+	sectionSelect := widget.NewSelect(titles, func(title string) {
+		for _, diff := range diffs {
+			if diff.Title == title {
+				preview.Objects = []fyne.CanvasObject{container.NewVScroll(diffRichText([]sectionDiff{diff}))}
+				preview.Refresh()
+			}
+		}
+	})
+	sectionSelect.SetSelected(titles[0])
+
+	content := container.NewBorder(sectionSelect, nil, nil, nil, preview)
+	restore := dialog.NewCustomConfirm("Restore Section from "+formatStampSeconds(version.Timestamp), "Restore Section", "Cancel", content,
+		func(ok bool) {
+			if ok {
+				c.restoreSection(version, sectionSelect.Selected)
+			}
+		}, c.window)
+	restore.Resize(fyne.NewSize(600, 450))
+	restore.Show()
+}
+
+// showVersionCompare shows what restoring a version would change in the
+// current canvas, offering to restore it
+func (c *Canvas) showVersionCompare(version Version) {
+	diffs := diffCanvases(c.getCurrentData(), version.Data)
+	if len(diffs) == 0 {
+		dialog.ShowInformation("Compare", "This version matches the current canvas", c.window)
+		return
+	}
+
+	legend := widget.NewLabel("Restoring removes the red lines and adds the green lines")
+	header := container.NewVBox(legend)
+	if version.Note != "" {
+		note := widget.NewLabel("What changed: " + version.Note)
+		note.Wrapping = fyne.TextWrapWord
+		header.Objects = append([]fyne.CanvasObject{note}, header.Objects...)
+	}
+	content := container.NewBorder(header, nil, nil, nil, container.NewVScroll(diffRichText(diffs)))
+	compare := dialog.NewCustomConfirm("Compare with "+formatStampSeconds(version.Timestamp), "Restore", "Close", content,
+		func(restore bool) {
+			if restore {
+				c.restoreVersion(version)
+			}
+		}, c.window)
+	compare.Resize(fyne.NewSize(600, 500))
+	compare.Show()
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+)
+
+// diffReport is the rendered diff of two canvas files, for attaching to
+// pull requests that change canvases kept in Git
+type diffReport struct {
+	Old, New         string
+	OldType, NewType string
+	Sections         []sectionDiff
+	Added, Removed   int
+	SectionAdded     map[string]int
+	SectionRemoved   map[string]int
+}
+
+// newDiffReport compares two canvases, named for the report
+func newDiffReport(oldName string, from CanvasData, newName string, to CanvasData) diffReport {
+	report := diffReport{
+		Old: oldName, New: newName,
+		OldType: from.canvasType().Name, NewType: to.canvasType().Name,
+		Sections:       diffCanvases(from, to),
+		SectionAdded:   make(map[string]int),
+		SectionRemoved: make(map[string]int),
+	}
+	for _, section := range report.Sections {
+		for _, line := range section.Lines {
+			switch line.Op {
+			case diffInsert:
+				report.SectionAdded[section.Title]++
+				report.Added++
+			case diffDelete:
+				report.SectionRemoved[section.Title]++
+				report.Removed++
+			}
+		}
+	}
+	return report
+}
+
+// summary is a line stating what changed
+func (r diffReport) summary() string {
+	if len(r.Sections) == 0 && r.OldType == r.NewType {
+		return "No changes"
+	}
+	summary := fmt.Sprintf("Sections changed: %d, lines added: %d, lines removed: %d", len(r.Sections), r.Added, r.Removed)
+	if r.OldType != r.NewType {
+		summary = fmt.Sprintf("Changed from %s to %s. %s", r.OldType, r.NewType, summary)
+	}
+	return summary
+}
+
+// writeDiffMarkdown writes the report as Markdown, with a diff block per
+// section that code hosts color
+func writeDiffMarkdown(w io.Writer, r diffReport) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Canvas changes: %s → %s\n\n", r.Old, r.New)
+	fmt.Fprintf(&b, "%s · %s\n\n", r.NewType, r.summary())
+	if len(r.Sections) > 0 {
+		b.WriteString("| Section | Added | Removed |\n|---|---:|---:|\n")
+		for _, section := range r.Sections {
+			fmt.Fprintf(&b, "| %s | +%d | -%d |\n", section.Title, r.SectionAdded[section.Title], r.SectionRemoved[section.Title])
+		}
+		b.WriteString("\n")
+	}
+	for _, section := range r.Sections {
+		fmt.Fprintf(&b, "## %s\n\n~~~diff\n", section.Title)
+		for _, line := range section.Lines {
+			prefix := "  "
+			switch line.Op {
+			case diffDelete:
+				prefix = "- "
+			case diffInsert:
+				prefix = "+ "
+			}
+			b.WriteString(prefix + line.Text + "\n")
+		}
+		b.WriteString("~~~\n\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// diffPage renders a diff report as a standalone page
+var diffPage = template.Must(template.New("diff").Funcs(template.FuncMap{
+	"lineClass": func(op int) string {
+		switch op {
+		case diffDelete:
+			return "del"
+		case diffInsert:
+			return "ins"
+		}
+		return "same"
+	},
+	"linePrefix": func(op int) string {
+		switch op {
+		case diffDelete:
+			return "-"
+		case diffInsert:
+			return "+"
+		}
+		return " "
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Canvas changes: {{.Report.Old}} → {{.Report.New}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; max-width: 60em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border-bottom: 1px solid #ddd; padding: 4px 12px; text-align: left; }
+td.num { text-align: right; font-family: monospace; }
+.lines { font-family: monospace; border: 1px solid #ddd; border-radius: 4px; }
+.lines div { padding: 2px 8px; white-space: pre-wrap; }
+.del { background: #ffebe9; color: #82071e; }
+.ins { background: #dafbe1; color: #116329; }
+.same { color: #555; }
+</style>
+</head>
+<body>
+<h1>Canvas changes</h1>
+<p><strong>{{.Report.Old}}</strong> → <strong>{{.Report.New}}</strong><br>{{.Report.NewType}} · {{.Summary}}</p>
+{{if .Report.Sections}}<table>
+<tr><th>Section</th><th>Added</th><th>Removed</th></tr>
+{{range .Report.Sections}}<tr><td><a href="#{{.Title}}">{{.Title}}</a></td><td class="num">+{{index $.Report.SectionAdded .Title}}</td><td class="num">-{{index $.Report.SectionRemoved .Title}}</td></tr>
+{{end}}</table>{{end}}
+{{range .Report.Sections}}<h2 id="{{.Title}}">{{.Title}}</h2>
+<div class="lines">{{range .Lines}}<div class="{{lineClass .Op}}">{{linePrefix .Op}} {{.Text}}</div>{{end}}</div>
+{{end}}</body>
+</html>
+`))
+
+// writeDiffHTML writes the report as a standalone HTML page
+func writeDiffHTML(w io.Writer, r diffReport) error {
+	return diffPage.Execute(w, map[string]any{"Report": r, "Summary": r.summary()})
+}
+
+// runDiff renders the changes between two canvas files for review:
+//
+//	business-canvas diff --format html old.json new.json > canvas-diff.html
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	format := flags.String("format", "md", "format of the rendered diff: md or html")
+	out := flags.String("out", "", "file to write the diff to instead of standard output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("give the old and the new canvas file")
+	}
+	write := writeDiffMarkdown
+	switch *format {
+	case "md", "markdown":
+	case "html":
+		write = writeDiffHTML
+	default:
+		return fmt.Errorf("unknown format %q, use md or html", *format)
+	}
+
+	var canvases [2]CanvasData
+	for i, path := range flags.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		canvases[i], err = readCanvasData(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	report := newDiffReport(flags.Arg(0), canvases[0], flags.Arg(1), canvases[1])
+
+	if *out == "" {
+		return write(os.Stdout, report)
+	}
+	var b strings.Builder
+	if err := write(&b, report); err != nil {
+		return err
+	}
+	return writeLocalFile(*out, []byte(b.String()))
+}
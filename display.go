@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+)
+
+// commandUsage lists the subcommands that run without a display
+const commandUsage = `Usage:
+  business-canvas                     open the app
+  business-canvas watch FILE...       re-run exports when canvas files change
+  business-canvas diff OLD NEW        render the changes between two canvas files
+  business-canvas patch CANVAS PATCH  apply a JSON Patch to a canvas file or workspace canvas
+  business-canvas verify HISTORY      verify the hash chain of an exported version history
+  business-canvas relay               relay collaboration sessions
+  business-canvas serve               serve organization workspaces and sessions
+  business-canvas backup FILE         back up the data folder of a server
+  business-canvas restore FILE        restore a backup into the data folder of a server
+  business-canvas help                show this help
+
+Run a subcommand with -h for its options.
+`
+
+// hasDisplay reports whether there is a desktop to open the window on. On
+// Linux and the BSDs that takes an X11 or Wayland session, missing over SSH
+// or in a container, where the app would fail to start.
+func hasDisplay() bool {
+	switch runtime.GOOS {
+	case "windows", "darwin", "android", "ios", "js":
+		return true
+	}
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
+
+// printUsage prints the subcommands of the binary
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, commandUsage)
+}
+
+// noDisplay explains that the app cannot open without a display and points
+// to the subcommands that can run instead, answering the exit code
+func noDisplay() int {
+	fmt.Fprintln(os.Stderr, "business-canvas: no display found (DISPLAY and WAYLAND_DISPLAY are unset), so the app cannot open.")
+	fmt.Fprintln(os.Stderr, "Connect with X11 forwarding or run it on a desktop, or use one of the subcommands, which need no display.")
+	fmt.Fprintln(os.Stderr)
+	printUsage(os.Stderr)
+	return 2
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Preference keys of Dropbox sync. The app key is of a Dropbox app the
+// user registers, as the app has no key of its own to ship.
+const (
+	prefDropboxAppKey   = "dropboxAppKey"
+	prefDropboxRefresh  = "dropboxRefreshToken"
+	prefDropboxFolder   = "dropboxFolder"
+	prefDropboxAutoSave = "dropboxAutoSave"
+)
+
+// defaultDropboxFolder is where canvases are synced in the Dropbox
+const defaultDropboxFolder = "/Business Canvas"
+
+// dropboxAutoSaveName is the file the latest auto-saved canvas is synced to
+const dropboxAutoSaveName = "Autosave.json"
+
+// Dropbox API endpoints
+const (
+	dropboxAuthorizeURL = "https://www.dropbox.com/oauth2/authorize"
+	dropboxTokenURL     = "https://api.dropboxapi.com/oauth2/token"
+	dropboxUploadURL    = "https://content.dropboxapi.com/2/files/upload"
+)
+
+// dropboxToken caches the short-lived access token of the session
+var dropboxToken struct {
+	sync.Mutex
+	value   string
+	expires time.Time
+}
+
+// dropboxAuthorization returns the page the user approves access on, with
+// the PKCE verifier to exchange the code it shows for a refresh token
+func dropboxAuthorization(appKey string) (string, string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", err
+	}
+	verifier := base64.RawURLEncoding.EncodeToString(random)
+	challenge := sha256.Sum256([]byte(verifier))
+
+	query := url.Values{
+		"client_id":             {appKey},
+		"response_type":         {"code"},
+		"token_access_type":     {"offline"},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	return dropboxAuthorizeURL + "?" + query.Encode(), verifier, nil
+}
+
+// dropboxTokenRequest posts a form to the token endpoint
+func dropboxTokenRequest(form url.Values) (refresh, access string, expiresIn int, err error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.PostForm(dropboxTokenURL, form)
+	if err != nil {
+		return "", "", 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", "", 0, fmt.Errorf("Dropbox responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", "", 0, err
+	}
+	return token.RefreshToken, token.AccessToken, token.ExpiresIn, nil
+}
+
+// dropboxExchangeCode trades the code shown after approving access for a
+// refresh token
+func dropboxExchangeCode(appKey, code, verifier string) (string, error) {
+	refresh, _, _, err := dropboxTokenRequest(url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"client_id":     {appKey},
+		"code_verifier": {verifier},
+	})
+	if err == nil && refresh == "" {
+		err = errors.New("Dropbox returned no refresh token")
+	}
+	return refresh, err
+}
+
+// dropboxAccessToken returns an access token, refreshing it when it is
+// about to expire
+func dropboxAccessToken(appKey, refresh string) (string, error) {
+	dropboxToken.Lock()
+	defer dropboxToken.Unlock()
+	if dropboxToken.value != "" && time.Until(dropboxToken.expires) > time.Minute {
+		return dropboxToken.value, nil
+	}
+
+	_, access, expiresIn, err := dropboxTokenRequest(url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refresh},
+		"client_id":     {appKey},
+	})
+	if err != nil {
+		return "", err
+	}
+	dropboxToken.value = access
+	dropboxToken.expires = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	return access, nil
+}
+
+// dropboxUpload writes a file into the Dropbox, replacing any previous copy
+func dropboxUpload(token, filePath string, content []byte) error {
+	arg, err := json.Marshal(map[string]interface{}{
+		"path": filePath,
+		"mode": "overwrite",
+		"mute": true,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, dropboxUploadURL, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Dropbox-API-Arg", string(arg))
+
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Dropbox responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// dropboxConnected reports whether the user connected a Dropbox
+func (c *Canvas) dropboxConnected() bool {
+	return c.prefs.String(prefDropboxAppKey) != "" && c.prefs.String(prefDropboxRefresh) != ""
+}
+
+// dropboxSave uploads a canvas file to the sync folder in the background,
+// alerting the user when the upload fails
+func (c *Canvas) dropboxSave(name string, content []byte) {
+	if !c.dropboxConnected() {
+		return
+	}
+	appKey, refresh := c.prefs.String(prefDropboxAppKey), c.prefs.String(prefDropboxRefresh)
+	folder := c.prefs.StringWithFallback(prefDropboxFolder, defaultDropboxFolder)
+
+	go func() {
+		token, err := dropboxAccessToken(appKey, refresh)
+		if err == nil {
+			err = dropboxUpload(token, path.Join("/", folder, name), content)
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("Dropbox sync of %s failed: %w", name, err), c.window)
+		}
+	}()
+}
+
+// dropboxAutoSave syncs the canvas after an auto-save when enabled
+func (c *Canvas) dropboxAutoSave() {
+	if !c.dropboxConnected() || !c.prefs.BoolWithFallback(prefDropboxAutoSave, true) {
+		return
+	}
+	content, err := json.MarshalIndent(c.canvasFileData(), "", "    ")
+	if err != nil {
+		fyne.LogError("Dropbox auto-save sync failed", err)
+		return
+	}
+	c.dropboxSave(dropboxAutoSaveName, content)
+}
+
+// showDropboxSettings connects a Dropbox with the OAuth flow and sets what
+// is synced to it
+func (c *Canvas) showDropboxSettings() {
+	appKey := widget.NewEntry()
+	appKey.SetPlaceHolder("App key from dropbox.com/developers")
+	appKey.SetText(c.prefs.String(prefDropboxAppKey))
+	folder := widget.NewEntry()
+	folder.SetText(c.prefs.StringWithFallback(prefDropboxFolder, defaultDropboxFolder))
+	autoSave := widget.NewCheck("Sync auto-saves in the background", nil)
+	autoSave.SetChecked(c.prefs.BoolWithFallback(prefDropboxAutoSave, true))
+
+	status := widget.NewLabel("Not connected")
+	if c.dropboxConnected() {
+		status.SetText("Connected")
+	}
+	connect := widget.NewButton("Connect...", func() {
+		key := strings.TrimSpace(appKey.Text)
+		if key == "" {
+			dialog.ShowError(errors.New("enter the app key of your Dropbox app first"), c.window)
+			return
+		}
+		authURL, verifier, err := dropboxAuthorization(key)
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if parsed, err := url.Parse(authURL); err == nil {
+			fyne.CurrentApp().OpenURL(parsed)
+		}
+
+		code := widget.NewEntry()
+		code.SetPlaceHolder("Code shown by Dropbox")
+		dialog.ShowForm("Connect Dropbox", "Connect", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("", widget.NewLabel("Allow access in the browser, then paste the code here.")),
+			widget.NewFormItem("Code", code),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			refresh, err := dropboxExchangeCode(key, strings.TrimSpace(code.Text), verifier)
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			c.prefs.SetString(prefDropboxAppKey, key)
+			c.prefs.SetString(prefDropboxRefresh, refresh)
+			status.SetText("Connected")
+		}, c.window)
+	})
+	disconnect := widget.NewButton("Disconnect", func() {
+		c.prefs.SetString(prefDropboxRefresh, "")
+		dropboxToken.Lock()
+		dropboxToken.value = ""
+		dropboxToken.Unlock()
+		status.SetText("Not connected")
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("App key", appKey),
+		widget.NewFormItem("Account", container.NewHBox(status, connect, disconnect)),
+		widget.NewFormItem("Folder", folder),
+		widget.NewFormItem("", autoSave),
+	}
+	form := dialog.NewForm("Dropbox Sync", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		c.prefs.SetString(prefDropboxAppKey, strings.TrimSpace(appKey.Text))
+		c.prefs.SetString(prefDropboxFolder, strings.TrimSpace(folder.Text))
+		c.prefs.SetBool(prefDropboxAutoSave, autoSave.Checked)
+	}, c.window)
+	form.Resize(fyne.NewSize(500, 0))
+	form.Show()
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// editorPollInterval is how often a section open in an external editor is
+// checked for changes
+const editorPollInterval = 500 * time.Millisecond
+
+// externalEditor is the editor command from $VISUAL or $EDITOR with its
+// arguments, nil when neither is set
+func externalEditor() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if command := strings.Fields(os.Getenv(name)); len(command) > 0 {
+			return command
+		}
+	}
+	return nil
+}
+
+// entrySection returns the title of the section edited in an entry
+func (c *Canvas) entrySection(entry *widget.Entry) string {
+	for _, section := range c.getCurrentData().sections() {
+		if c.sectionEntry(section.Title) == entry {
+			return section.Title
+		}
+	}
+	return ""
+}
+
+// editFocusedExternally opens the section being edited in an external editor
+func (c *Canvas) editFocusedExternally() {
+	entry, ok := c.window.Canvas().Focused().(*widget.Entry)
+	title := ""
+	if ok {
+		title = c.entrySection(entry)
+	}
+	if title == "" {
+		dialog.ShowInformation("External Editor", "Click into a section first", c.window)
+		return
+	}
+	c.editExternally(title, entry)
+}
+
+// editExternally writes a section to a temporary Markdown file, opens it in
+// $VISUAL, $EDITOR or the default app for Markdown files and pulls every
+// change saved there back into the section until editing is done
+func (c *Canvas) editExternally(title string, entry *widget.Entry) {
+	if entry.Disabled() {
+		dialog.ShowError(errors.New(title+" is locked"), c.window)
+		return
+	}
+	name := strings.Join(strings.Fields(title), "-")
+	file, err := os.CreateTemp("", name+"-*.md")
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	path := file.Name()
+	_, err = file.WriteString(entry.Text)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+
+	c.undoStack = append(c.undoStack, c.getCurrentData())
+	pull := func() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fyne.LogError("Reading "+path+" failed", err)
+			return
+		}
+		if text := string(content); text != entry.Text {
+			entry.SetText(text)
+		}
+	}
+
+	original := entry.Text
+	stop := make(chan struct{})
+	var once sync.Once
+	var editing dialog.Dialog
+	finish := func(keep bool) {
+		once.Do(func() {
+			close(stop)
+			if keep {
+				pull()
+			} else {
+				entry.SetText(original)
+			}
+			os.Remove(path)
+			editing.Hide()
+		})
+	}
+	go watchFile(path, editorPollInterval, stop, pull)
+
+	message := fmt.Sprintf("Editing %s in an external editor.\nChanges are pulled in whenever you save the file.", title)
+	editing = dialog.NewCustomConfirm("External Editor", "Done", "Discard", widget.NewLabel(message), finish, c.window)
+	editing.Show()
+
+	if command := externalEditor(); command != nil {
+		cmd := exec.Command(command[0], append(command[1:], path)...)
+		if err := cmd.Start(); err != nil {
+			close(stop)
+			os.Remove(path)
+			dialog.ShowError(err, c.window)
+			return
+		}
+		// The editor is done once it exits, GUI editors need to be told to
+		// wait, e.g. EDITOR="code --wait"
+		go func() {
+			cmd.Wait()
+			finish(true)
+		}()
+	} else if fileURL, err := url.Parse(storage.NewFileURI(path).String()); err == nil {
+		fyne.CurrentApp().OpenURL(fileURL)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Share of the page the Business Model Environment takes around a canvas,
+// the bands above and below and the columns either side
+const (
+	environmentBand   = 0.16
+	environmentColumn = 0.14
+)
+
+// loadEnvironmentMap reads the Business Model Environment a canvas names,
+// nil when it names none
+func loadEnvironmentMap(location string) (*CanvasData, error) {
+	if location == "" {
+		return nil, nil
+	}
+	uri, err := parseLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	data, err := readCanvasURI(uri)
+	if err != nil {
+		return nil, fmt.Errorf("environment map %s: %w", uri.Name(), err)
+	}
+	if data.canvasType().ID != canvasTypeEnv {
+		return nil, fmt.Errorf("%s is not a Business Model Environment", uri.Name())
+	}
+	return &data, nil
+}
+
+// drawEnvironment draws the forces of a Business Model Environment around
+// the page and narrows the margins to the space left inside, answering a
+// function that restores them
+func drawEnvironment(pdf *gofpdf.Fpdf, opts pdfOptions, env CanvasData) func() {
+	pageWidth, pageHeight := pdf.GetPageSize()
+	left, top, right, _ := pdf.GetMargins()
+	auto, bottom := pdf.GetAutoPageBreak()
+	width := pageWidth - left - right
+	height := pageHeight - top - bottom
+	band, column := height*environmentBand, width*environmentColumn
+
+	sections := env.sections()
+	cells := []pdfCell{
+		{X: left + width - column, Y: top + band, W: column, H: height - 2*band}, // Market Forces
+		{X: left, Y: top + band, W: column, H: height - 2*band},                  // Industry Forces
+		{X: left, Y: top, W: width, H: band},                                     // Key Trends
+		{X: left, Y: top + height - band, W: width, H: band},                     // Macroeconomic Forces
+	}
+	pdf.SetLineWidth(0.3)
+	for i, cell := range cells {
+		drawSection(pdf, opts, cell, sections[i].Title, sections[i].Text)
+	}
+
+	// The canvas sits inside with a small gap to the forces
+	gap := 4.0
+	pdf.SetMargins(left+column+gap, top+band+gap, right+column+gap)
+	pdf.SetAutoPageBreak(auto, bottom+band+gap)
+	return func() {
+		pdf.SetMargins(left, top, right)
+		pdf.SetAutoPageBreak(auto, bottom)
+	}
+}
+
+// linkEnvironmentMap sets the open Business Model Canvas in a Business
+// Model Environment file
+func (c *Canvas) linkEnvironmentMap(linked func()) {
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+		data, err := readCanvasData(reader)
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if data.canvasType().ID != canvasTypeEnv {
+			dialog.ShowError(fmt.Errorf("%s is not a Business Model Environment, create one with File > New...", reader.URI().Name()), c.window)
+			return
+		}
+		c.undoStack = append(c.undoStack, c.getCurrentData())
+		c.environmentMap = reader.URI().String()
+		linked()
+	}, c.window)
+}
+
+// showEnvironmentMap shows the Business Model Environment the open canvas
+// is set in, to link, open or unlink it
+func (c *Canvas) showEnvironmentMap() {
+	if c.canvasTypeID != canvasTypeBusiness {
+		dialog.ShowInformation("Business Model Environment", "Open a Business Model Canvas to set it in its environment", c.window)
+		return
+	}
+	var d dialog.Dialog
+	label := widget.NewLabel("")
+	openButton := widget.NewButton("Open", func() {
+		d.Hide()
+		c.openCanvasLocation(c.environmentMap)
+	})
+	unlinkButton := widget.NewButton("Unlink", nil)
+	refresh := func() {
+		label.SetText("Not set in an environment map. Create one with File > New... and link it here to show its forces around the canvas in PDF exports.")
+		openButton.Disable()
+		unlinkButton.Disable()
+		if c.environmentMap != "" {
+			label.SetText("Set in " + linkName(c.environmentMap) + ", drawn around the canvas in PDF exports.")
+			openButton.Enable()
+			unlinkButton.Enable()
+		}
+	}
+	unlinkButton.OnTapped = func() {
+		c.undoStack = append(c.undoStack, c.getCurrentData())
+		c.environmentMap = ""
+		refresh()
+	}
+	refresh()
+	label.Wrapping = fyne.TextWrapWord
+
+	buttons := container.NewHBox(
+		widget.NewButton("Link...", func() { c.linkEnvironmentMap(refresh) }),
+		openButton,
+		unlinkButton,
+	)
+	d = dialog.NewCustom("Business Model Environment", "Close", container.NewVBox(label, buttons), c.window)
+	d.Resize(fyne.NewSize(500, 180))
+	d.Show()
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// The assumptions log records what each section takes for granted and the
+// evidence gathered for or against it: interview notes, survey results or
+// any other file, kept in the canvas file so it travels with the canvas.
+
+// Statuses of an assumption
+const (
+	assumptionUntested    = "Untested"
+	assumptionValidated   = "Validated"
+	assumptionInvalidated = "Invalidated"
+)
+
+// assumptionStatuses are the statuses to choose from
+var assumptionStatuses = []string{assumptionUntested, assumptionValidated, assumptionInvalidated}
+
+// maxEvidenceSize bounds an attached file, as it is kept in the canvas file
+const maxEvidenceSize = 5 << 20
+
+// Assumption is a belief a section of the canvas rests on
+type Assumption struct {
+	Section   string     `json:"section"`
+	Statement string     `json:"statement"`
+	Status    string     `json:"status,omitempty"`
+	Evidence  []Evidence `json:"evidence,omitempty"`
+	Created   time.Time  `json:"created"`
+}
+
+// Evidence is a file attached to an assumption
+type Evidence struct {
+	Name    string    `json:"name"`
+	Added   time.Time `json:"added"`
+	Content []byte    `json:"content"`
+}
+
+// status is the status of an assumption, untested when not set
+func (a Assumption) status() string {
+	if a.Status == "" {
+		return assumptionUntested
+	}
+	return a.Status
+}
+
+// evidencePath is where an attachment is kept in exported bundles, unique
+// for every assumption and file
+func evidencePath(assumption, file int, name string) string {
+	return fmt.Sprintf("evidence/%d-%d-%s", assumption+1, file+1, path.Base(strings.ReplaceAll(name, "\\", "/")))
+}
+
+// writeAssumptionsLog writes the assumptions as CSV, one row each with the
+// bundle paths of their evidence
+func writeAssumptionsLog(w io.Writer, assumptions []Assumption) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"Section", "Assumption", "Status", "Recorded", "Evidence"})
+	for i, assumption := range assumptions {
+		var files []string
+		for j, evidence := range assumption.Evidence {
+			files = append(files, evidencePath(i, j, evidence.Name))
+		}
+		out.Write([]string{assumption.Section, assumption.Statement, assumption.status(), assumption.Created.Format(time.RFC3339), strings.Join(files, "; ")})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// updateAssumption replaces an assumption, keeping the previous canvas for
+// undo
+func (c *Canvas) updateAssumption(id int, assumption Assumption) {
+	c.undoStack = append(c.undoStack, c.getCurrentData())
+	c.assumptions = slices.Clone(c.assumptions)
+	c.assumptions[id] = assumption
+}
+
+// showAssumptions lists the assumptions of the canvas to add, edit and
+// remove them
+func (c *Canvas) showAssumptions() {
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(c.assumptions) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.DeleteIcon(), nil), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			assumption := c.assumptions[id]
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s: %s (%s, %s)", assumption.Section, assumption.Statement,
+				assumption.status(), plural(len(assumption.Evidence), "file")))
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				c.undoStack = append(c.undoStack, c.getCurrentData())
+				c.assumptions = slices.Delete(slices.Clone(c.assumptions), id, id+1)
+				list.UnselectAll()
+				list.Refresh()
+				c.refreshHealth()
+			}
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		list.UnselectAll()
+		c.showAssumption(id, list.Refresh)
+	}
+
+	var titles []string
+	for _, section := range c.getCurrentData().sections() {
+		titles = append(titles, section.Title)
+	}
+	section := widget.NewSelect(titles, nil)
+	if len(titles) > 0 {
+		section.SetSelected(titles[0])
+	}
+	statement := widget.NewEntry()
+	statement.SetPlaceHolder("e.g. Small agencies will pay monthly for this")
+	add := widget.NewButtonWithIcon("Add", theme.ContentAddIcon(), func() {
+		text := strings.TrimSpace(statement.Text)
+		if text == "" {
+			return
+		}
+		c.undoStack = append(c.undoStack, c.getCurrentData())
+		c.assumptions = append(slices.Clone(c.assumptions), Assumption{
+			Section:   section.Selected,
+			Statement: text,
+			Status:    assumptionUntested,
+			Created:   nowUTC(),
+		})
+		statement.SetText("")
+		list.Refresh()
+		c.refreshHealth()
+	})
+
+	form := container.NewBorder(nil, nil, section, add, statement)
+	content := container.NewBorder(nil, form, nil, nil, list)
+	d := dialog.NewCustom("Assumptions", "Close", content, c.window)
+	d.Resize(fyne.NewSize(700, 450))
+	d.Show()
+}
+
+// showAssumption edits the status and the evidence of an assumption
+func (c *Canvas) showAssumption(id int, changed func()) {
+	assumption := c.assumptions[id]
+
+	status := widget.NewSelect(assumptionStatuses, func(selected string) {
+		if selected == c.assumptions[id].status() {
+			return
+		}
+		updated := c.assumptions[id]
+		updated.Status = selected
+		c.updateAssumption(id, updated)
+		changed()
+	})
+	status.SetSelected(assumption.status())
+
+	var files *widget.List
+	files = widget.NewList(
+		func() int { return len(c.assumptions[id].Evidence) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.DeleteIcon(), nil), widget.NewLabel(""))
+		},
+		func(file widget.ListItemID, obj fyne.CanvasObject) {
+			evidence := c.assumptions[id].Evidence[file]
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s (%d KB, added %s)", evidence.Name, (len(evidence.Content)+1023)/1024, formatDate(evidence.Added)))
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				updated := c.assumptions[id]
+				updated.Evidence = slices.Delete(slices.Clone(updated.Evidence), file, file+1)
+				c.updateAssumption(id, updated)
+				files.Refresh()
+				changed()
+				c.refreshHealth()
+			}
+		},
+	)
+
+	attach := widget.NewButtonWithIcon("Attach File...", theme.ContentAddIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+			content, err := io.ReadAll(io.LimitReader(reader, maxEvidenceSize+1))
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if len(content) > maxEvidenceSize {
+				dialog.ShowError(errors.New("evidence files are kept in the canvas and may be 5 MB at most"), c.window)
+				return
+			}
+			updated := c.assumptions[id]
+			updated.Evidence = append(slices.Clone(updated.Evidence), Evidence{Name: reader.URI().Name(), Added: nowUTC(), Content: content})
+			c.updateAssumption(id, updated)
+			files.Refresh()
+			changed()
+			c.refreshHealth()
+		}, c.window)
+	})
+
+	statement := widget.NewLabel(assumption.Section + ": " + assumption.Statement)
+	statement.Wrapping = fyne.TextWrapWord
+	content := container.NewBorder(
+		container.NewVBox(statement, container.NewBorder(nil, nil, widget.NewLabel("Status"), nil, status)),
+		attach, nil, nil, files)
+	d := dialog.NewCustom("Assumption", "Close", content, c.window)
+	d.Resize(fyne.NewSize(600, 400))
+	d.Show()
+}
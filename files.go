@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/storage/repository"
+)
+
+// Every feature reads and writes files through readURI and writeURI, so a
+// canvas works the same wherever it lives: a local path, any URI Fyne
+// knows, or the remote storage of the settings under remote:///name.
+
+// remoteScheme addresses files in the remote storage configured in Settings
+const remoteScheme = "remote"
+
+// parseLocation turns a local path or a URI into a URI
+func parseLocation(location string) (fyne.URI, error) {
+	if strings.Contains(location, "://") {
+		return storage.ParseURI(location)
+	}
+	path, err := filepath.Abs(location)
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewFileURI(path), nil
+}
+
+// readURI reads the whole file at a URI
+func readURI(uri fyne.URI) ([]byte, error) {
+	if uri.Scheme() == "file" {
+		return os.ReadFile(uri.Path())
+	}
+	reader, err := storage.Reader(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	var content bytes.Buffer
+	_, err = content.ReadFrom(reader)
+	return content.Bytes(), err
+}
+
+// readCanvasURI reads the canvas file at a URI
+func readCanvasURI(uri fyne.URI) (CanvasData, error) {
+	content, err := readURI(uri)
+	if err != nil {
+		return CanvasData{}, err
+	}
+	return readCanvasData(bytes.NewReader(content))
+}
+
+// writeURI replaces the file at a URI. Local files are written next to the
+// file a symlink points to and renamed into place, so a crash never leaves a
+// half-written canvas and links are kept rather than replaced.
+func writeURI(uri fyne.URI, content []byte) error {
+	if uri.Scheme() == "file" {
+		return writeLocalFile(uri.Path(), content)
+	}
+	writer, err := storage.Writer(uri)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(content); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+func writeLocalFile(path string, content []byte) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
+
+// writeDialogFile writes the file a save dialog opened a writer for. Local
+// files go through writeLocalFile, so they are replaced by rename rather
+// than rewritten in place; other files are written through the writer.
+func writeDialogFile(writer fyne.URIWriteCloser, content []byte) error {
+	if writer.URI().Scheme() == "file" {
+		writer.Close()
+		return writeLocalFile(writer.URI().Path(), content)
+	}
+	if _, err := writer.Write(content); err != nil {
+		discardWriter(writer)
+		return err
+	}
+	return writer.Close()
+}
+
+// discardWriter gives up a writer without finishing its file. Remote
+// files are uploaded when their writer closes, so those are left alone
+// rather than replaced with what was written so far.
+func discardWriter(writer fyne.URIWriteCloser) {
+	if writer.URI().Scheme() != remoteScheme {
+		writer.Close()
+	}
+}
+
+// remoteRepository lets Fyne storage reach the remote storage of the
+// settings, so open and save dialogs, folders and every file feature work
+// on remote:/// URIs
+type remoteRepository struct {
+	canvas *Canvas
+}
+
+// remoteName is the name of the file a remote URI refers to
+func remoteName(u fyne.URI) string {
+	return strings.TrimPrefix(u.Path(), "/")
+}
+
+func (r remoteRepository) store() (remoteStore, error) {
+	if store := r.canvas.configuredStore(); store != nil {
+		return store, nil
+	}
+	return nil, repository.ErrOperationNotSupported
+}
+
+func (r remoteRepository) Exists(u fyne.URI) (bool, error) {
+	store, err := r.store()
+	if err != nil {
+		return false, err
+	}
+	names, err := store.List()
+	if err != nil {
+		return false, err
+	}
+	name := remoteName(u)
+	for _, existing := range names {
+		if existing == name || strings.HasPrefix(existing, name+"/") || name == "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r remoteRepository) Reader(u fyne.URI) (fyne.URIReadCloser, error) {
+	store, err := r.store()
+	if err != nil {
+		return nil, err
+	}
+	content, err := store.Get(remoteName(u))
+	if err != nil {
+		return nil, err
+	}
+	return &remoteReader{Reader: bytes.NewReader(content), uri: u}, nil
+}
+
+func (r remoteRepository) CanRead(u fyne.URI) (bool, error) {
+	return r.Exists(u)
+}
+
+func (r remoteRepository) Destroy(string) {}
+
+func (r remoteRepository) Writer(u fyne.URI) (fyne.URIWriteCloser, error) {
+	store, err := r.store()
+	if err != nil {
+		return nil, err
+	}
+	return &remoteWriter{store: store, uri: u}, nil
+}
+
+func (r remoteRepository) CanWrite(u fyne.URI) (bool, error) {
+	_, err := r.store()
+	return err == nil, nil
+}
+
+func (r remoteRepository) Delete(fyne.URI) error {
+	return repository.ErrOperationNotSupported
+}
+
+func (r remoteRepository) Parent(u fyne.URI) (fyne.URI, error) {
+	return repository.GenericParent(u)
+}
+
+func (r remoteRepository) Child(u fyne.URI, component string) (fyne.URI, error) {
+	return repository.GenericChild(u, component)
+}
+
+// CanList treats the root and every name prefix as a folder
+func (r remoteRepository) CanList(u fyne.URI) (bool, error) {
+	return strings.HasSuffix(u.Path(), "/") || u.Path() == "", nil
+}
+
+func (r remoteRepository) List(u fyne.URI) ([]fyne.URI, error) {
+	store, err := r.store()
+	if err != nil {
+		return nil, err
+	}
+	names, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	prefix := remoteName(u)
+	var children []fyne.URI
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		child, err := storage.ParseURI(remoteScheme + ":///" + name)
+		if err == nil {
+			children = append(children, child)
+		}
+	}
+	return children, nil
+}
+
+func (r remoteRepository) CreateListable(fyne.URI) error {
+	// Folders of the remote storage come into being with their files
+	return nil
+}
+
+// remoteReader reads a file fetched from the remote storage
+type remoteReader struct {
+	*bytes.Reader
+	uri fyne.URI
+}
+
+func (r *remoteReader) URI() fyne.URI { return r.uri }
+func (r *remoteReader) Close() error  { return nil }
+
+// remoteWriter collects a file and uploads it to the remote storage when
+// it is closed
+type remoteWriter struct {
+	bytes.Buffer
+	store remoteStore
+	uri   fyne.URI
+}
+
+func (w *remoteWriter) URI() fyne.URI { return w.uri }
+func (w *remoteWriter) Close() error  { return w.store.Put(remoteName(w.uri), w.Bytes()) }
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// openFileInterval is how often the open canvas file is checked for
+// changes made outside the app
+const openFileInterval = 2 * time.Second
+
+// watchOpenFile follows the canvas file that was opened or saved, nil when
+// the canvas has no file, so changes made to it on disk are noticed
+func (c *Canvas) watchOpenFile(file fyne.URI) {
+	if c.stopFileWatch != nil {
+		close(c.stopFileWatch)
+		c.stopFileWatch = nil
+	}
+	c.openFile = file
+	c.externalChange = false
+	if file == nil || file.Scheme() != "file" {
+		return
+	}
+
+	c.openStamp, _ = statFile(file.Path())
+	stop := make(chan struct{})
+	c.stopFileWatch = stop
+	go watchFile(file.Path(), openFileInterval, stop, func() {
+		c.openFileChanged(file)
+	})
+	go c.watchConflicts(file, stop)
+}
+
+// openFileChanged offers to reload the open file after it changed on disk,
+// unless the change is the app's own save
+func (c *Canvas) openFileChanged(file fyne.URI) {
+	stamp, err := statFile(file.Path())
+	if err != nil || stamp == c.openStamp || c.openFile != file {
+		return
+	}
+	c.openStamp = stamp
+
+	disk, err := readCanvasURI(file)
+	if err != nil {
+		fyne.LogError("Reading "+file.Path()+" failed", err)
+		return
+	}
+	diffs := diffCanvases(c.getCurrentData(), disk)
+	if len(diffs) == 0 {
+		return
+	}
+	c.externalChange = true
+
+	var changed *dialog.CustomDialog
+	reload := widget.NewButton("Reload", func() {
+		changed.Hide()
+		c.applyCanvasFile(disk)
+		c.externalChange = false
+	})
+	reload.Importance = widget.HighImportance
+	merge := widget.NewButton("Merge...", func() {
+		changed.Hide()
+		c.showMergeChooser(c.canvasFileData(), disk, func() {
+			c.externalChange = false
+		})
+	})
+	showDiff := widget.NewButton("Show Diff", func() {
+		preview := container.NewVScroll(diffRichText(diffs))
+		legend := widget.NewLabel("Red lines are only in the app, green lines only on disk")
+		diff := dialog.NewCustom("Changes on Disk", "Close", container.NewBorder(legend, nil, nil, nil, preview), c.window)
+		diff.Resize(fyne.NewSize(600, 500))
+		diff.Show()
+	})
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Canvas changed on disk", file.Name()+" was changed outside Business Canvas"))
+	message := widget.NewLabel(file.Name() + " was changed outside the app. Reload it, merge it with your version section by section, or keep your version and be asked before saving over the newer copy.")
+	message.Wrapping = fyne.TextWrapWord
+	changed = dialog.NewCustomWithoutButtons("File Changed on Disk", message, c.window)
+	changed.SetButtons([]fyne.CanvasObject{
+		widget.NewButton("Keep Mine", func() { changed.Hide() }),
+		showDiff,
+		merge,
+		reload,
+	})
+	changed.Resize(fyne.NewSize(500, 0))
+	// A newer change replaces the prompt about an earlier one
+	if c.changedPrompt != nil {
+		c.changedPrompt.Hide()
+	}
+	c.changedPrompt = changed
+	changed.Show()
+}
+
+// confirmOverwrite runs save, first asking when the open file changed on
+// disk and the user kept their version
+func (c *Canvas) confirmOverwrite(save func()) {
+	if !c.externalChange {
+		save()
+		return
+	}
+	dialog.ShowConfirm("Save Over Newer Copy?", c.openFile.Name()+" changed on disk since it was opened. Saving over it discards those changes. Continue?", func(ok bool) {
+		if ok {
+			save()
+		}
+	}, c.window)
+}
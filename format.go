@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// canvasFormatVersion is the version of the canvas file format written by
+// this build. Files without a version predate versioning and are version 1.
+const canvasFormatVersion = 2
+
+// canvasMigrations upgrade a decoded canvas file by one format version,
+// indexed by the version they upgrade from
+var canvasMigrations = map[int]func(map[string]interface{}){
+	// Version 1 files predate canvas types and are always Business Model
+	// Canvases
+	1: func(file map[string]interface{}) {
+		if _, ok := file["canvasType"]; !ok {
+			file["canvasType"] = canvasTypeBusiness
+		}
+	},
+}
+
+// migrateCanvasFile upgrades raw canvas file content to the current format
+// version, rejecting files written by a newer version of the app
+func migrateCanvasFile(content []byte) ([]byte, error) {
+	var file map[string]interface{}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	version := 1
+	if value, ok := file["formatVersion"].(float64); ok {
+		version = int(value)
+	}
+	if version > canvasFormatVersion {
+		return nil, fmt.Errorf("this canvas was saved by a newer version of Business Canvas (format %d, this version reads up to %d); please update the app", version, canvasFormatVersion)
+	}
+	if version == canvasFormatVersion {
+		return content, nil
+	}
+
+	for ; version < canvasFormatVersion; version++ {
+		if migrate := canvasMigrations[version]; migrate != nil {
+			migrate(file)
+		}
+	}
+	file["formatVersion"] = canvasFormatVersion
+	return json.Marshal(file)
+}
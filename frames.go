@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/software"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// frameSize is the size animation frames are rendered at
+var frameSize = fyne.NewSize(960, 540)
+
+// Frame delays are kept within these bounds so idle stretches are skipped
+// and quick edits are still visible
+const (
+	minFrameDelay  = 100 * time.Millisecond
+	maxFrameDelay  = 2 * time.Second
+	lastFrameDelay = 3 * time.Second
+
+	// versionFrameDelay is how long each version shows in an evolution
+	versionFrameDelay = 1500 * time.Millisecond
+)
+
+// frameBlock is a read-only block of a rendered canvas
+func frameBlock(section sectionContent) fyne.CanvasObject {
+	title := widget.NewLabelWithStyle(section.Title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	text := widget.NewLabel(strings.Join(sectionLines(section.Text), "\n"))
+	text.Wrapping = fyne.TextWrapWord
+	border := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	border.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	border.StrokeWidth = 1
+	return container.NewStack(border, container.NewBorder(title, nil, nil, nil, text))
+}
+
+// canvasFrameView lays out a read-only copy of the canvas for replays and
+// rendered frames
+func canvasFrameView(data CanvasData) fyne.CanvasObject {
+	kind := data.canvasType()
+	sections := data.sections()
+	grid := container.New(kind.Layout)
+	for _, section := range sections[:len(kind.Titles)] {
+		grid.Add(frameBlock(section))
+	}
+	if len(data.CustomSections) == 0 {
+		return grid
+	}
+	row := container.NewGridWithColumns(len(data.CustomSections))
+	for _, section := range sections[len(kind.Titles):] {
+		row.Add(frameBlock(section))
+	}
+	return container.NewBorder(nil, row, nil, nil, grid)
+}
+
+// renderFrames renders each state of a canvas to an image off screen,
+// captioned when captions are given
+func renderFrames(states []CanvasData, captions []string) []image.Image {
+	frames := make([]image.Image, 0, len(states))
+	for i, data := range states {
+		view := canvasFrameView(data)
+		if i < len(captions) {
+			caption := widget.NewLabelWithStyle(captions[i], fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+			view = container.NewBorder(caption, nil, nil, nil, view)
+		}
+		c := software.NewCanvas()
+		c.SetContent(container.NewStack(canvas.NewRectangle(theme.Color(theme.ColorNameBackground)), view))
+		c.Resize(frameSize)
+		frames = append(frames, c.Capture())
+	}
+	return frames
+}
+
+// frameDelay bounds the delay of a frame
+func frameDelay(d time.Duration) time.Duration {
+	return min(max(d, minFrameDelay), maxFrameDelay)
+}
+
+// writeGIF encodes frames as a looping animated GIF
+func writeGIF(w io.Writer, frames []image.Image, delays []time.Duration) error {
+	animation := &gif.GIF{}
+	for i, frame := range frames {
+		paletted := image.NewPaletted(frame.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, frame.Bounds(), frame, image.Point{})
+		animation.Image = append(animation.Image, paletted)
+		animation.Delay = append(animation.Delay, int(delays[i]/(10*time.Millisecond)))
+	}
+	return gif.EncodeAll(w, animation)
+}
+
+// writeMP4 encodes frames as an MP4 video with ffmpeg, which has to be
+// installed separately
+func writeMP4(w io.Writer, frames []image.Image, delays []time.Duration) error {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return errors.New("MP4 export needs ffmpeg installed and on the PATH, export a GIF instead")
+	}
+	dir, err := os.MkdirTemp("", "canvas-frames")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// The concat list gives each frame its own duration
+	var list strings.Builder
+	for i, frame := range frames {
+		name := filepath.Join(dir, fmt.Sprintf("frame-%05d.png", i))
+		file, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		err = png.Encode(file, frame)
+		file.Close()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&list, "file '%s'\nduration %.3f\n", name, delays[i].Seconds())
+	}
+	// The last frame has to be listed again for its duration to apply
+	fmt.Fprintf(&list, "file '%s'\n", filepath.Join(dir, fmt.Sprintf("frame-%05d.png", len(frames)-1)))
+	listFile := filepath.Join(dir, "frames.txt")
+	if err := os.WriteFile(listFile, []byte(list.String()), 0o600); err != nil {
+		return err
+	}
+
+	video := filepath.Join(dir, "canvas.mp4")
+	cmd := exec.Command(ffmpeg, "-y", "-f", "concat", "-safe", "0", "-i", listFile,
+		"-vf", "fps=25,format=yuv420p", "-c:v", "libx264", video)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %v\n%s", err, output)
+	}
+	file, err := os.Open(video)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// exportAnimation renders canvas states to an animated GIF, or an MP4
+// when saved with that extension
+func (c *Canvas) exportAnimation(fileName string, states []CanvasData, captions []string, delays []time.Duration) {
+	if len(states) == 0 {
+		dialog.ShowInformation("Export Animation", "There is nothing to animate", c.window)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		frames := renderFrames(states, captions)
+		if strings.ToLower(writer.URI().Extension()) == ".mp4" {
+			err = writeMP4(writer, frames, delays)
+		} else {
+			err = writeGIF(writer, frames, delays)
+		}
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		dialog.ShowInformation("Success", "Animation has been exported successfully", c.window)
+	}, c.window)
+	saveDialog.SetFileName(fileName)
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".gif", ".mp4"}))
+	saveDialog.Show()
+}
+
+// exportEvolution animates the canvas through the given versions, oldest
+// first, captioned with their timestamps and names
+func (c *Canvas) exportEvolution(versions []Version) {
+	var states []CanvasData
+	var captions []string
+	var delays []time.Duration
+	for _, version := range versions {
+		caption := formatStamp(version.Timestamp)
+		if version.Name != "" {
+			caption += " — " + version.Name
+		}
+		states = append(states, version.Data)
+		captions = append(captions, caption)
+		delays = append(delays, versionFrameDelay)
+	}
+	if len(delays) > 0 {
+		delays[len(delays)-1] = lastFrameDelay
+	}
+	c.exportAnimation("canvas-evolution.gif", states, captions, delays)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// exampleCanvas is a filled canvas of the gallery, a starting point for a
+// common kind of business
+type exampleCanvas struct {
+	Name        string
+	Description string
+	Data        CanvasData
+}
+
+// canvasGallery are the example canvases File > New from Template offers
+var canvasGallery = []exampleCanvas{
+	{
+		Name:        "SaaS Startup",
+		Description: "A subscription software product for small teams, sold self-serve with a sales-assisted tier.",
+		Data: CanvasData{
+			KeyPartners:      "- Cloud hosting provider\n- Payment processor\n- Integration partners (CRM, chat, calendar)\n- Agencies reselling to their clients",
+			KeyActivities:    "- Product development\n- Hosting and operations\n- Content marketing and SEO\n- Customer onboarding",
+			KeyResources:     "- Engineering team\n- Codebase and infrastructure\n- Customer usage data\n- Brand and content library",
+			ValueProposition: "- Set up in minutes without IT\n- Replaces spreadsheets and email threads\n- Works with the tools the team already uses\n- Pricing that grows with the team",
+			CustomerRel:      "- Self-serve onboarding\n- In-app help and community forum\n- Account managers for larger plans",
+			Channels:         "- Website and free trial\n- App marketplaces of partners\n- Content and search\n- Sales team for larger accounts",
+			CustomerSegments: "- Small teams of 5 to 50 people\n- Operations managers in growing companies\n- Agencies managing several clients",
+			CostStructure:    "- Salaries of engineering and support\n- Hosting costs per customer\n- Marketing and customer acquisition\n- Payment fees",
+			RevenueStreams:   "- Monthly and annual subscriptions per seat\n- Premium tier with advanced features\n- Paid onboarding for larger accounts",
+		},
+	},
+	{
+		Name:        "Marketplace",
+		Description: "A two-sided platform connecting local service providers with households, earning a commission per booking.",
+		Data: CanvasData{
+			KeyPartners:      "- Payment and escrow provider\n- Insurance partner covering bookings\n- Background check service\n- Local trade associations",
+			KeyActivities:    "- Recruiting and vetting providers\n- Matching demand with supply\n- Trust and safety\n- Running the platform",
+			KeyResources:     "- Network of vetted providers\n- Reviews and ratings\n- Matching and scheduling software\n- Brand trusted by both sides",
+			ValueProposition: "- Households: book a vetted provider in minutes with a fixed price\n- Providers: a steady flow of jobs without marketing\n- Both: payment guaranteed and disputes handled",
+			CustomerRel:      "- Ratings on both sides\n- Support for disputes\n- Loyalty discounts for repeat bookings",
+			Channels:         "- Mobile app and website\n- Local search ads\n- Referrals between neighbours\n- Provider sign-up events",
+			CustomerSegments: "- Busy households needing home services\n- Independent cleaners, handymen and gardeners\n- Property managers with recurring jobs",
+			CostStructure:    "- Acquiring both sides of the market\n- Vetting and insurance\n- Support and dispute handling\n- Platform development",
+			RevenueStreams:   "- Commission on every booking\n- Featured listings for providers\n- Subscription for property managers",
+		},
+	},
+	{
+		Name:        "Hardware",
+		Description: "A connected device for the home, sold through retail with a subscription for its companion service.",
+		Data: CanvasData{
+			KeyPartners:      "- Contract manufacturer\n- Component suppliers\n- Retail and e-commerce distributors\n- Certification labs",
+			KeyActivities:    "- Industrial design and engineering\n- Supply chain and manufacturing\n- Firmware and app development\n- Certification and quality control",
+			KeyResources:     "- Patents and designs\n- Manufacturing tooling\n- Inventory and working capital\n- Firmware and cloud service",
+			ValueProposition: "- Works out of the box with a phone app\n- Saves energy and money on the household bills\n- Regular updates that add features",
+			CustomerRel:      "- Setup guide in the app\n- Warranty and repair service\n- Updates and tips in the app",
+			Channels:         "- Own online shop\n- Electronics retailers\n- Online marketplaces\n- Energy providers bundling the device",
+			CustomerSegments: "- Homeowners interested in saving energy\n- Early adopters of smart home devices\n- Energy providers offering it to their customers",
+			CostStructure:    "- Bill of materials and manufacturing\n- Inventory, shipping and returns\n- Retail margins\n- Engineering and cloud hosting",
+			RevenueStreams:   "- Device sales\n- Subscription for the premium app features\n- Licensing to energy providers",
+		},
+	},
+	{
+		Name:        "Nonprofit",
+		Description: "A charity running after-school tutoring, funded by donors and grants rather than by the families it serves.",
+		Data: CanvasData{
+			KeyPartners:      "- Schools referring students\n- Foundations and grant makers\n- Companies sending volunteers\n- Libraries and community centres hosting sessions",
+			KeyActivities:    "- Recruiting and training volunteer tutors\n- Running tutoring sessions\n- Measuring learning outcomes\n- Fundraising and reporting to funders",
+			KeyResources:     "- Volunteer tutors\n- Curriculum and training material\n- Relationships with schools\n- Evidence of impact",
+			ValueProposition: "- Students: free help that improves their grades\n- Families: a safe place after school\n- Donors and funders: measurable impact on education",
+			CustomerRel:      "- Same tutor for a student all year\n- Progress reports to families\n- Impact reports and visits for donors",
+			Channels:         "- Schools and teachers\n- Community centres\n- Website and social media\n- Donor events and newsletters",
+			CustomerSegments: "- Beneficiaries: students from low-income families\n- Volunteers wanting to give their time\n- Donors, foundations and corporate sponsors",
+			CostStructure:    "- Programme coordinators\n- Training and material\n- Venue and travel costs\n- Fundraising and administration",
+			RevenueStreams:   "- Grants from foundations\n- Individual donations\n- Corporate sponsorship\n- Public funding for education programmes",
+		},
+	},
+}
+
+// galleryMarkdown shows an example canvas section by section
+func galleryMarkdown(example exampleCanvas) string {
+	var b strings.Builder
+	b.WriteString(example.Description + "\n\n")
+	for _, section := range example.Data.sections() {
+		b.WriteString("## " + section.Title + "\n\n")
+		for _, line := range sectionLines(section.Text) {
+			b.WriteString("- " + line + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// newFromTemplate offers the example canvases of the gallery and starts a
+// new canvas from the chosen one
+func (c *Canvas) newFromTemplate() {
+	preview := widget.NewRichTextFromMarkdown("")
+	preview.Wrapping = fyne.TextWrapWord
+	selected := -1
+
+	list := widget.NewList(
+		func() int { return len(canvasGallery) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(canvasGallery[id].Name)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		preview.ParseMarkdown(galleryMarkdown(canvasGallery[id]))
+		preview.Refresh()
+	}
+
+	split := container.NewHSplit(list, container.NewVScroll(preview))
+	split.Offset = 0.25
+	d := dialog.NewCustomConfirm("New from Template", "Create", "Cancel", split, func(ok bool) {
+		if !ok || selected < 0 {
+			return
+		}
+		example := canvasGallery[selected]
+		data := example.Data
+		data.FormatVersion = canvasFormatVersion
+		c.confirmReplace("A new canvas from "+example.Name, data, func() { c.startCanvas(data) })
+	}, c.window)
+	d.Resize(fyne.NewSize(800, 600))
+	d.Show()
+	list.Select(0)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Preference keys of Git-backed storage
+const (
+	prefGitCommit = "gitCommit"
+	prefGitPush   = "gitPush"
+)
+
+// runGit runs a git command in a directory, returning its trimmed output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitCommitFile commits a saved file to the Git repository it is in,
+// creating a repository in its folder when there is none, and pushes the
+// commit when asked to
+func gitCommitFile(path, message string, push bool) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("Git storage needs git installed and on the PATH")
+	}
+	dir := filepath.Dir(path)
+	if _, err := runGit(dir, "rev-parse", "--show-toplevel"); err != nil {
+		if _, err := runGit(dir, "init"); err != nil {
+			return err
+		}
+	}
+
+	name := filepath.Base(path)
+	if _, err := runGit(dir, "add", "--", name); err != nil {
+		return err
+	}
+	// Saving without changes leaves nothing to commit
+	if _, err := runGit(dir, "diff", "--cached", "--quiet", "--", name); err == nil {
+		return nil
+	}
+	if _, err := runGit(dir, "commit", "-m", message, "--", name); err != nil {
+		return err
+	}
+	if push {
+		_, err := runGit(dir, "push")
+		return err
+	}
+	return nil
+}
+
+// gitCommitMessage uses the name and changelog note of the latest version
+// saved since the last commit, falling back to a generic message
+func (c *Canvas) gitCommitMessage(name string) string {
+	for i := len(c.versions) - 1; i >= 0; i-- {
+		version := c.versions[i]
+		if version.Timestamp.Before(c.lastGitCommit) {
+			break
+		}
+		if version.Note == "" && version.Name == "" {
+			continue
+		}
+		if version.Name == "" {
+			subject, _, _ := strings.Cut(version.Note, "\n")
+			return subject + "\n\n" + version.Note
+		}
+		if version.Note == "" {
+			return version.Name
+		}
+		return version.Name + "\n\n" + version.Note
+	}
+	return "Update " + name
+}
+
+// gitSave commits a saved canvas file in the background when Git-backed
+// storage is on, alerting the user when the commit fails
+func (c *Canvas) gitSave(uri fyne.URI) {
+	if !c.prefs.Bool(prefGitCommit) || uri.Scheme() != "file" {
+		return
+	}
+	message := c.gitCommitMessage(uri.Name())
+	push := c.prefs.Bool(prefGitPush)
+	c.lastGitCommit = time.Now()
+
+	go func() {
+		if err := gitCommitFile(uri.Path(), message, push); err != nil {
+			dialog.ShowError(fmt.Errorf("committing %s failed: %w", uri.Name(), err), c.window)
+		}
+	}()
+}
+
+// gitSetting turns Git-backed storage on and off in the settings dialog
+func (c *Canvas) gitSetting() fyne.CanvasObject {
+	pushCheck := widget.NewCheck("Push after each commit", func(checked bool) {
+		c.prefs.SetBool(prefGitPush, checked)
+	})
+	pushCheck.SetChecked(c.prefs.Bool(prefGitPush))
+
+	commitCheck := widget.NewCheck("Commit to Git on every save", func(checked bool) {
+		c.prefs.SetBool(prefGitCommit, checked)
+		if checked {
+			pushCheck.Enable()
+		} else {
+			pushCheck.Disable()
+		}
+	})
+	commitCheck.SetChecked(c.prefs.Bool(prefGitCommit))
+	if !commitCheck.Checked {
+		pushCheck.Disable()
+	}
+
+	return container.NewVBox(commitCheck, pushCheck)
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// HealthFactor is one weighted input to the canvas health score
+type HealthFactor struct {
+	Name   string
+	Weight float64
+	Score  float64 // 0..1
+	Advice string
+}
+
+// HealthReport explains how the overall health score was reached
+type HealthReport struct {
+	Factors []HealthFactor
+}
+
+// Score returns the weighted health score from 0 to 100
+func (r HealthReport) Score() int {
+	var total, weights float64
+	for _, factor := range r.Factors {
+		total += factor.Score * factor.Weight
+		weights += factor.Weight
+	}
+	if weights == 0 {
+		return 0
+	}
+	return int(math.Round(total / weights * 100))
+}
+
+// staleAfter and staleLimit bound the freshness factor: a canvas saved
+// within staleAfter is fully fresh, one older than staleLimit scores zero
+const (
+	staleAfter = 30 * 24 * time.Hour
+	staleLimit = 180 * 24 * time.Hour
+)
+
+// ValidationSummary is the outcome of the validation rules, saved with the
+// canvas so the portfolio dashboard can score it without the rules
+type ValidationSummary struct {
+	Rules    int      `json:"rules"`
+	Findings []string `json:"findings,omitempty"`
+}
+
+// validationSummary summarizes validation results of the rules of the
+// canvas
+func (c *Canvas) validationSummary(results []ValidationResult) *ValidationSummary {
+	if c.validator == nil {
+		return nil
+	}
+	summary := &ValidationSummary{Rules: len(c.validator.rules)}
+	for _, result := range results {
+		summary.Findings = append(summary.Findings, result.Message)
+	}
+	return summary
+}
+
+func (c *Canvas) healthReport() HealthReport {
+	data := c.getCurrentData()
+	data.Validation = c.validationSummary(c.validator.Validate(c))
+	return canvasHealth(data, c.staleDays, c.lastReviewed(), time.Now())
+}
+
+// canvasHealth scores canvas data, with the staleness threshold of each
+// section and when it was last reviewed. Data without a validation summary
+// is scored without that factor.
+func canvasHealth(data CanvasData, staleDays func(string) int, reviewed, now time.Time) HealthReport {
+	// Completeness
+	var empty []string
+	sections := data.sections()
+	for _, section := range sections {
+		if strings.TrimSpace(section.Text) == "" {
+			empty = append(empty, section.Title)
+		}
+	}
+	completeness := HealthFactor{
+		Name:   "Completeness",
+		Weight: 30,
+		Score:  float64(len(sections)-len(empty)) / float64(len(sections)),
+		Advice: "All sections are filled in",
+	}
+	if len(empty) > 0 {
+		completeness.Advice = "Fill in: " + strings.Join(empty, ", ")
+	}
+	factors := []HealthFactor{completeness}
+
+	// Validation findings
+	if summary := data.Validation; summary != nil {
+		validation := HealthFactor{
+			Name:   "Validation",
+			Weight: 30,
+			Score:  1,
+			Advice: "No validation findings",
+		}
+		if summary.Rules > 0 {
+			validation.Score = math.Max(0, 1-float64(len(summary.Findings))/float64(summary.Rules))
+		}
+		if len(summary.Findings) > 0 {
+			validation.Advice = strings.Join(summary.Findings, "\n")
+		}
+		factors = append(factors, validation)
+	}
+
+	// Evidence coverage, the filled sections with evidence attached to one
+	// of their assumptions
+	evidenced := make(map[string]bool)
+	for _, assumption := range data.Assumptions {
+		if len(assumption.Evidence) > 0 {
+			evidenced[assumption.Section] = true
+		}
+	}
+	var unsupported []string
+	for _, section := range sections {
+		if strings.TrimSpace(section.Text) != "" && !evidenced[section.Title] {
+			unsupported = append(unsupported, section.Title)
+		}
+	}
+	evidence := HealthFactor{
+		Name:   "Evidence",
+		Weight: 20,
+		Score:  1,
+		Advice: "Every filled section has evidence for its assumptions",
+	}
+	if filled := len(sections) - len(empty); filled == 0 {
+		evidence.Score = 0
+		evidence.Advice = "Fill in sections and attach evidence to their assumptions in Tools > Assumptions..."
+	} else if len(unsupported) > 0 {
+		evidence.Score = 1 - float64(len(unsupported))/float64(filled)
+		evidence.Advice = "Attach evidence to assumptions of: " + strings.Join(unsupported, ", ")
+	}
+	factors = append(factors, evidence)
+
+	// Staleness, per section where edit times are known and for the
+	// canvas as a whole otherwise
+	freshness := HealthFactor{
+		Name:   "Freshness",
+		Weight: 20,
+		Score:  1,
+		Advice: "Canvas has been reviewed recently",
+	}
+	if len(data.SectionEdited) > 0 {
+		stale := staleSectionsOf(data, staleDays, now)
+		if len(stale) > 0 {
+			freshness.Score = 1 - float64(len(stale))/float64(len(sections))
+			var nudges []string
+			for _, section := range stale {
+				nudges = append(nudges, section.nudge())
+			}
+			freshness.Advice = strings.Join(nudges, "\n")
+		}
+	} else if !reviewed.IsZero() {
+		age := now.Sub(reviewed)
+		if age > staleAfter {
+			freshness.Score = math.Max(0, 1-float64(age-staleAfter)/float64(staleLimit-staleAfter))
+			freshness.Advice = fmt.Sprintf("Last saved %d days ago, review whether it is still accurate", int(age.Hours()/24))
+		}
+	}
+	factors = append(factors, freshness)
+
+	return HealthReport{Factors: factors}
+}
+
+// lastReviewed returns the most recent save or version time
+func (c *Canvas) lastReviewed() time.Time {
+	last := c.lastSaved
+	for _, version := range c.versions {
+		if version.Timestamp.After(last) {
+			last = version.Timestamp
+		}
+	}
+	return last
+}
+
+func (c *Canvas) refreshHealth() {
+	if c.healthButton == nil {
+		return
+	}
+	c.healthButton.SetText(fmt.Sprintf("Health: %d/100", c.healthReport().Score()))
+}
+
+func (c *Canvas) showHealthBreakdown() {
+	report := c.healthReport()
+
+	breakdown := container.NewVBox(
+		widget.NewLabelWithStyle(fmt.Sprintf("Overall health: %d/100", report.Score()), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	)
+	for _, factor := range report.Factors {
+		breakdown.Add(widget.NewSeparator())
+		breakdown.Add(widget.NewLabelWithStyle(
+			fmt.Sprintf("%s: %d%% (weight %.0f)", factor.Name, int(math.Round(factor.Score*100)), factor.Weight),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true},
+		))
+		advice := widget.NewLabel(factor.Advice)
+		advice.Wrapping = fyne.TextWrapWord
+		breakdown.Add(advice)
+	}
+
+	scroll := container.NewVScroll(breakdown)
+	scroll.SetMinSize(fyne.NewSize(500, 350))
+	dialog.ShowCustom("Canvas Health", "Close", scroll, c.window)
+}
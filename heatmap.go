@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Heatmap modes: what the overlay colors each block by
+const (
+	heatOff      = "Off"
+	heatComments = "Comment density"
+	heatChurn    = "Edit churn"
+)
+
+// heatPeriods are the periods the heatmap can look back over, zero is all
+// time
+var heatPeriods = []struct {
+	Name   string
+	Period time.Duration
+}{
+	{"Last 7 days", 7 * 24 * time.Hour},
+	{"Last 30 days", 30 * 24 * time.Hour},
+	{"Last 90 days", 90 * 24 * time.Hour},
+	{"All time", 0},
+}
+
+// heatCell is the overlay behind a block, colored by the heatmap
+func (c *Canvas) heatCell(section string, content fyne.CanvasObject) *fyne.Container {
+	cell := canvas.NewRectangle(color.Transparent)
+	c.heatCells[section] = cell
+	c.colorHeatCell(section, c.heatCounts())
+	return container.NewStack(cell, content)
+}
+
+// heatCounts counts comments or changes per section within the period
+func (c *Canvas) heatCounts() map[string]int {
+	counts := make(map[string]int)
+	since := time.Time{}
+	if c.heatPeriod > 0 {
+		since = time.Now().Add(-c.heatPeriod)
+	}
+
+	switch c.heatMode {
+	case heatComments:
+		for _, comment := range c.allComments() {
+			if comment.Timestamp.After(since) {
+				counts[comment.Section]++
+			}
+		}
+	case heatChurn:
+		versions := append([]Version(nil), c.versions...)
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i].Timestamp.Before(versions[j].Timestamp)
+		})
+		// Each saved version, and the canvas as it is now, that changed a
+		// section counts once for it
+		states := make([]CanvasData, 0, len(versions)+1)
+		var times []time.Time
+		for _, version := range versions {
+			states = append(states, version.Data)
+			times = append(times, version.Timestamp)
+		}
+		states = append(states, c.getCurrentData())
+		times = append(times, time.Now())
+		for i := 1; i < len(states); i++ {
+			if !times[i].After(since) {
+				continue
+			}
+			before := make(map[string]string)
+			for _, section := range states[i-1].sections() {
+				before[section.Title] = normalizeText(section.Text)
+			}
+			for _, section := range states[i].sections() {
+				if before[section.Title] != normalizeText(section.Text) {
+					counts[section.Title]++
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// heatColor shades from a faint yellow for the least to a strong red for
+// the most contested block, blocks without activity stay clear
+func heatColor(count, most int) color.Color {
+	if count == 0 || most == 0 {
+		return color.Transparent
+	}
+	t := float64(count) / float64(most)
+	return color.NRGBA{R: 0xe5, G: uint8(0xc0 * (1 - t)), B: 0x30, A: uint8(0x30 + 0x70*t)}
+}
+
+func (c *Canvas) colorHeatCell(section string, counts map[string]int) {
+	cell := c.heatCells[section]
+	if cell == nil {
+		return
+	}
+	most := 0
+	for _, count := range counts {
+		most = max(most, count)
+	}
+	cell.FillColor = heatColor(counts[section], most)
+	cell.Refresh()
+}
+
+// refreshHeatmap recolors every block after comments, versions or the mode
+// changed
+func (c *Canvas) refreshHeatmap() {
+	counts := c.heatCounts()
+	for section := range c.heatCells {
+		c.colorHeatCell(section, counts)
+	}
+}
+
+// heatDetail describes the heat of a block for its tooltip
+func (c *Canvas) heatDetail(section string) string {
+	switch c.heatMode {
+	case heatComments:
+		return fmt.Sprintf("%d comments in the heatmap period", c.heatCounts()[section])
+	case heatChurn:
+		return fmt.Sprintf("Changed in %d versions in the heatmap period", c.heatCounts()[section])
+	}
+	return ""
+}
+
+// sectionDetail is the attribution and heat of a block for its tooltip
+func (c *Canvas) sectionDetail(section string) string {
+	var lines []string
+	for _, line := range []string{c.attribution(section), c.heatDetail(section)} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// showHeatmap turns the heatmap overlay on or off and chooses its period
+func (c *Canvas) showHeatmap() {
+	mode := widget.NewRadioGroup([]string{heatOff, heatComments, heatChurn}, nil)
+	mode.SetSelected(c.heatMode)
+	if c.heatMode == "" {
+		mode.SetSelected(heatOff)
+	}
+	var names []string
+	for _, period := range heatPeriods {
+		names = append(names, period.Name)
+	}
+	period := widget.NewSelect(names, nil)
+	period.SetSelectedIndex(len(heatPeriods) - 1)
+	for i, option := range heatPeriods {
+		if option.Period == c.heatPeriod {
+			period.SetSelectedIndex(i)
+		}
+	}
+	legend := widget.NewLabel("Red blocks are contested: many comments or changes. Clear blocks are settled.")
+	legend.Wrapping = fyne.TextWrapWord
+
+	form := dialog.NewForm("Heatmap", "Apply", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Color by", mode),
+		widget.NewFormItem("Period", period),
+		widget.NewFormItem("", legend),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		c.heatMode = mode.Selected
+		if c.heatMode == heatOff {
+			c.heatMode = ""
+		}
+		c.heatPeriod = heatPeriods[period.SelectedIndex()].Period
+		c.refreshHeatmap()
+	}, c.window)
+	form.Resize(fyne.NewSize(420, 0))
+	form.Show()
+}
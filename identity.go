@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Preference keys for the user's identity
+const (
+	prefUserName     = "userName"
+	prefUserInitials = "userInitials"
+	prefUserColor    = "userColor"
+)
+
+// Identity is how this user is shown on comments, versions and avatars
+type Identity struct {
+	Name     string
+	Initials string
+	Color    string // #rrggbb
+}
+
+// identity returns the user's profile, filling in the initials and color
+// from the name when they are not set. The PDF branding author is used as
+// the name until one is set.
+func (c *Canvas) identity() Identity {
+	name := strings.TrimSpace(c.prefs.String(prefUserName))
+	if name == "" {
+		name = strings.TrimSpace(c.prefs.String(prefBrandingAuthor))
+	}
+	if name == "" {
+		name = "Anonymous"
+	}
+	identity := Identity{
+		Name:     name,
+		Initials: strings.TrimSpace(c.prefs.String(prefUserInitials)),
+		Color:    c.prefs.String(prefUserColor),
+	}
+	if identity.Initials == "" {
+		identity.Initials = initials(name)
+	}
+	if _, ok := parseHexColor(identity.Color); !ok {
+		identity.Color = hexColor(avatarColor(name))
+	}
+	return identity
+}
+
+// userName is the name this user is shown with to others
+func (c *Canvas) userName() string {
+	return c.identity().Name
+}
+
+// hexColor formats a color as #rrggbb
+func hexColor(col color.Color) string {
+	nrgba := color.NRGBAModel.Convert(col).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", nrgba.R, nrgba.G, nrgba.B)
+}
+
+// parseHexColor reads a #rrggbb color
+func parseHexColor(text string) (color.NRGBA, bool) {
+	col := color.NRGBA{A: 0xff}
+	if len(text) != 7 {
+		return col, false
+	}
+	if _, err := fmt.Sscanf(text, "#%02x%02x%02x", &col.R, &col.G, &col.B); err != nil {
+		return col, false
+	}
+	return col, true
+}
+
+// identityAvatar draws the initials of an identity in its color, falling
+// back to ones derived from the name when they were not recorded
+func identityAvatar(name, initialsText, colorText string, size float32) fyne.CanvasObject {
+	if name == "" {
+		name = "Anonymous"
+	}
+	if initialsText == "" {
+		initialsText = initials(name)
+	}
+	fill, ok := parseHexColor(colorText)
+	if !ok {
+		fill = avatarColor(name)
+	}
+	circle := canvas.NewCircle(fill)
+	text := canvas.NewText(initialsText, color.White)
+	text.TextStyle = fyne.TextStyle{Bold: true}
+	text.Alignment = fyne.TextAlignCenter
+	return container.NewGridWrap(fyne.NewSquareSize(size), container.NewStack(circle, container.NewCenter(text)))
+}
+
+// showIdentitySettings edits the name, initials and color the user is
+// shown with
+func (c *Canvas) showIdentitySettings() {
+	identity := c.identity()
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(c.prefs.String(prefUserName))
+	nameEntry.SetPlaceHolder(identity.Name)
+	initialsEntry := widget.NewEntry()
+	initialsEntry.SetText(c.prefs.String(prefUserInitials))
+	initialsEntry.SetPlaceHolder(identity.Initials)
+	initialsEntry.Validator = func(text string) error {
+		if len([]rune(strings.TrimSpace(text))) > 3 {
+			return fmt.Errorf("use up to 3 initials")
+		}
+		return nil
+	}
+
+	userColor := c.prefs.String(prefUserColor)
+	swatch := canvas.NewRectangle(color.Transparent)
+	showColor := func() {
+		col, ok := parseHexColor(userColor)
+		if !ok {
+			col = avatarColor(identity.Name)
+		}
+		swatch.FillColor = col
+		swatch.Refresh()
+	}
+	showColor()
+	swatch.SetMinSize(fyne.NewSquareSize(24))
+	chooseColor := widget.NewButton("Choose...", func() {
+		picker := dialog.NewColorPicker("Color", "Pick the color of your avatar", func(col color.Color) {
+			userColor = hexColor(col)
+			showColor()
+		}, c.window)
+		picker.Advanced = true
+		picker.Show()
+	})
+	clearColor := widget.NewButton("Automatic", func() {
+		userColor = ""
+		showColor()
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Initials", initialsEntry),
+		widget.NewFormItem("Color", container.NewHBox(swatch, chooseColor, clearColor)),
+	}
+
+	dialog.ShowForm("Your Profile", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		c.prefs.SetString(prefUserName, strings.TrimSpace(nameEntry.Text))
+		c.prefs.SetString(prefUserInitials, strings.TrimSpace(initialsEntry.Text))
+		c.prefs.SetString(prefUserColor, userColor)
+		c.refreshMentions()
+	}, c.window)
+}
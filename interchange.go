@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// importCanvas replaces the canvas with imported data, keeping the current
+// state on the undo stack
+func (c *Canvas) importCanvas(data CanvasData) {
+	c.confirmReplace("Importing", data, func() {
+		c.undoStack = append(c.undoStack, c.getCurrentData())
+		c.setCurrentData(data)
+		c.resetSectionEdits(data.SectionEdited, data.SectionEditors)
+		c.resetSectionCRDT(data.CRDT)
+		c.updateProgress()
+		dialog.ShowInformation("Success", "Canvas imported successfully", c.window)
+	})
+}
+
+// showInterchange offers import and export in formats of other tools
+func (c *Canvas) showInterchange() {
+	var interchange dialog.Dialog
+
+	importStrategyzer := widget.NewButton("Import Strategyzer (JSON/XLSX)...", func() {
+		interchange.Hide()
+		openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			var data CanvasData
+			if strings.ToLower(reader.URI().Extension()) == ".xlsx" {
+				data, err = readStrategyzerXLSX(reader)
+			} else {
+				data, err = readStrategyzerJSON(reader)
+			}
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			c.importCanvas(data)
+		}, c.window)
+		openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json", ".xlsx"}))
+		openDialog.Show()
+	})
+
+	exportStrategyzerJSON := widget.NewButton("Export Strategyzer JSON...", func() {
+		interchange.Hide()
+		canvas := toStrategyzer("Business Model Canvas", c.getCurrentData())
+		c.saveInterchangeFile("canvas-strategyzer.json", func(writer fyne.URIWriteCloser) error {
+			encoder := json.NewEncoder(writer)
+			encoder.SetIndent("", "    ")
+			return encoder.Encode(canvas)
+		})
+	})
+
+	exportStrategyzerXLSX := widget.NewButton("Export Strategyzer XLSX...", func() {
+		interchange.Hide()
+		canvas := toStrategyzer("Business Model Canvas", c.getCurrentData())
+		c.saveInterchangeFile("canvas-strategyzer.xlsx", func(writer fyne.URIWriteCloser) error {
+			return writeXLSX(writer, []xlsxSheet{{Name: "Canvas", Rows: strategyzerRows(canvas)}})
+		})
+	})
+
+	importCSV := widget.NewButton("Import CSV (section, content)...", func() {
+		interchange.Hide()
+		openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			data, skipped, err := readCanvasCSV(reader, findCanvasType(c.canvasTypeID))
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			c.importCanvas(data)
+			if len(skipped) > 0 {
+				dialog.ShowInformation("Skipped Rows", "No section matches: "+strings.Join(skipped, ", "), c.window)
+			}
+		}, c.window)
+		openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv"}))
+		openDialog.Show()
+	})
+
+	scriptMarkdown := widget.NewButton("Export Speaker Script (Markdown)...", func() {
+		interchange.Hide()
+		steps := speakerScript(c.getCurrentData())
+		c.saveInterchangeFile("speaker-script.md", func(writer fyne.URIWriteCloser) error {
+			return writeScriptMarkdown(writer, steps)
+		})
+	})
+
+	scriptPDF := widget.NewButton("Export Speaker Script (PDF)...", func() {
+		interchange.Hide()
+		opts, err := c.pdfOptions()
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		steps := speakerScript(c.getCurrentData())
+		c.saveInterchangeFile("speaker-script.pdf", func(writer fyne.URIWriteCloser) error {
+			return writeScriptPDF(writer, opts, steps)
+		})
+	})
+
+	commentsMarkdown := widget.NewButton("Export Comments Report (Markdown)...", func() {
+		interchange.Hide()
+		data, comments := c.getCurrentData(), c.allComments()
+		c.saveInterchangeFile("comments-report.md", func(writer fyne.URIWriteCloser) error {
+			return writeCommentsMarkdown(writer, data, comments)
+		})
+	})
+
+	commentsPDF := widget.NewButton("Export Comments Report (PDF)...", func() {
+		interchange.Hide()
+		opts, err := c.pdfOptions()
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		data, comments := c.getCurrentData(), c.allComments()
+		c.saveInterchangeFile("comments-report.pdf", func(writer fyne.URIWriteCloser) error {
+			return writeCommentsPDF(writer, opts, data, comments)
+		})
+	})
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("Spreadsheet", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		importCSV,
+		widget.NewLabelWithStyle("Strategyzer", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		importStrategyzer,
+		exportStrategyzerJSON,
+		exportStrategyzerXLSX,
+		widget.NewLabelWithStyle("Rehearsal", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		scriptMarkdown,
+		scriptPDF,
+		widget.NewLabelWithStyle("Review", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		commentsMarkdown,
+		commentsPDF,
+	)
+	interchange = dialog.NewCustom("Import / Export", "Close", content, c.window)
+	interchange.Show()
+}
+
+// saveInterchangeFile asks for a file name and writes an export to it
+func (c *Canvas) saveInterchangeFile(fileName string, write func(fyne.URIWriteCloser) error) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		if err := write(writer); err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		dialog.ShowInformation("Success", "Canvas has been exported successfully", c.window)
+	}, c.window)
+	saveDialog.SetFileName(fileName)
+	saveDialog.Show()
+}
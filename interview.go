@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// thinBlockWords is how few words a block may hold before it counts as
+// weakly evidenced
+const thinBlockWords = 15
+
+// interviewQuestions are customer-discovery questions for the blocks of the
+// Business Model Canvas, in reading order. They ask about past behaviour
+// rather than opinions about the idea.
+var interviewQuestions = [][]string{
+	{
+		"Who do you rely on today to get this done?",
+		"What would make you switch suppliers, and when did you last do it?",
+	},
+	{
+		"Walk me through the last time you dealt with this, step by step.",
+		"Which part of it takes the most time or effort?",
+	},
+	{
+		"What tools, people or data do you use to handle this today?",
+		"What did you have to build or buy to make it work?",
+	},
+	{
+		"What is the hardest part about this problem?",
+		"Why is that hard? What have you tried to solve it?",
+		"What don't you love about the solutions you've tried?",
+	},
+	{
+		"How do you usually get help when something goes wrong with this?",
+		"How often do you talk to the people who provide it?",
+	},
+	{
+		"How did you find the solution you use now?",
+		"Where do you go to learn about new ways to handle this?",
+	},
+	{
+		"Tell me about your role and what a typical week looks like.",
+		"Who else has this problem? Can you introduce me to them?",
+	},
+	{
+		"What does handling this cost you today, in money and time?",
+		"Who signs off on spending for it?",
+	},
+	{
+		"How much do you pay for the current solution, and how?",
+		"What would make it worth paying more?",
+		"Who decides on buying something like this, and what does the process look like?",
+	},
+}
+
+// interviewGap is a block the canvas has little evidence for, with the
+// questions to ask about it
+type interviewGap struct {
+	Section   string
+	Reason    string
+	Questions []string
+}
+
+// interviewGaps lists the empty, thin and flagged blocks with interview
+// questions for each, in section order
+func (c *Canvas) interviewGaps() []interviewGap {
+	data := c.getCurrentData()
+	findings := make(map[string][]string)
+	for _, result := range c.validator.Validate(c) {
+		findings[result.Section] = append(findings[result.Section], result.Message)
+	}
+	kind := data.canvasType()
+	prompts := append([]string(nil), kind.Prompts...)
+	for _, custom := range data.CustomSections {
+		prompts = append(prompts, custom.Prompt)
+	}
+
+	var gaps []interviewGap
+	for i, section := range data.sections() {
+		words := len(strings.Fields(section.Text))
+		var reason string
+		switch {
+		case words == 0:
+			reason = "Empty"
+		case len(findings[section.Title]) > 0:
+			reason = strings.Join(findings[section.Title], "; ")
+		case words < thinBlockWords:
+			reason = fmt.Sprintf("Only %d words", words)
+		default:
+			continue
+		}
+
+		var questions []string
+		if kind.ID == canvasTypeBusiness && i < len(interviewQuestions) {
+			questions = append(questions, interviewQuestions[i]...)
+		} else if i < len(prompts) && prompts[i] != "" {
+			questions = append(questions,
+				"Tell me about the last time this came up: "+prompts[i],
+				"What have you tried so far, and what happened?")
+		}
+		// Claims that are written down still need to hold up in interviews
+		for _, line := range sectionLines(section.Text) {
+			questions = append(questions, fmt.Sprintf("We believe %q. When did you last experience this?", line))
+		}
+		gaps = append(gaps, interviewGap{Section: section.Title, Reason: reason, Questions: questions})
+	}
+	return gaps
+}
+
+// interviewMarkdown formats the gaps as an interview guide
+func interviewMarkdown(kind string, gaps []interviewGap) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Customer Discovery Interview Guide\n\n")
+	fmt.Fprintf(&b, "Questions for the weakly evidenced blocks of the %s. Ask about past behaviour, not opinions about your idea, and note quotes verbatim.\n", kind)
+	for _, gap := range gaps {
+		fmt.Fprintf(&b, "\n## %s\n\n_%s_\n\n", gap.Section, gap.Reason)
+		for _, question := range gap.Questions {
+			fmt.Fprintf(&b, "- %s\n", question)
+		}
+	}
+	b.WriteString("\n## Wrap-up\n\n- Is there anything else I should have asked?\n- Who else should I talk to?\n")
+	return b.String()
+}
+
+// showInterviewGuide previews the interview guide for the canvas gaps and
+// exports it as Markdown
+func (c *Canvas) showInterviewGuide() {
+	gaps := c.interviewGaps()
+	if len(gaps) == 0 {
+		dialog.ShowInformation("Interview Guide", "Every block is filled in and passes validation", c.window)
+		return
+	}
+	guide := interviewMarkdown(c.getCurrentData().canvasType().Name, gaps)
+
+	preview := widget.NewRichTextFromMarkdown(guide)
+	preview.Wrapping = fyne.TextWrapWord
+	copyMarkdown := widget.NewButton("Copy Markdown", func() {
+		c.window.Clipboard().SetContent(guide)
+	})
+	save := widget.NewButton("Export Markdown...", func() {
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			if _, err := io.WriteString(writer, guide); err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			dialog.ShowInformation("Success", "Interview guide has been exported successfully", c.window)
+		}, c.window)
+		saveDialog.SetFileName("interview-guide.md")
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".md"}))
+		saveDialog.Show()
+	})
+
+	content := container.NewBorder(nil, container.NewHBox(copyMarkdown, save), nil, nil, container.NewVScroll(preview))
+	guideDialog := dialog.NewCustom("Interview Guide", "Close", content, c.window)
+	guideDialog.Resize(fyne.NewSize(600, 550))
+	guideDialog.Show()
+}
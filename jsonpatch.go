@@ -0,0 +1,415 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Canvas files can be edited by JSON Patch documents (RFC 6902), so scripts
+// change single fields without rewriting the whole file. A patched canvas
+// must still be a valid canvas file, or nothing is changed.
+
+// patchOperation is one operation of a JSON Patch document
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// jsonPointer splits a JSON Pointer (RFC 6901) into its reference tokens
+func jsonPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("path %q must start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses the index of an array element, "-" being the end of the
+// array when allowed
+func arrayIndex(token string, length int, end bool) (int, error) {
+	if token == "-" && end {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	limit := length - 1
+	if end {
+		limit = length
+	}
+	if index > limit {
+		return 0, fmt.Errorf("array index %d is out of range", index)
+	}
+	return index, nil
+}
+
+// patchValue applies change to the value at the end of tokens within doc,
+// answering the new document. change gets the parent container and the
+// last token, or a nil parent when the pointer is the whole document.
+func patchValue(doc any, tokens []string, change func(parent any, token string) (any, error)) (any, error) {
+	if len(tokens) == 0 {
+		return change(nil, "")
+	}
+	token := tokens[0]
+	switch node := doc.(type) {
+	case map[string]any:
+		if len(tokens) == 1 {
+			return change(node, token)
+		}
+		child, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf("%q does not exist", token)
+		}
+		updated, err := patchValue(child, tokens[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = updated
+		return node, nil
+	case []any:
+		if len(tokens) == 1 {
+			return change(node, token)
+		}
+		index, err := arrayIndex(token, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := patchValue(node[index], tokens[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = updated
+		return node, nil
+	}
+	return nil, fmt.Errorf("%q is not inside an object or array", token)
+}
+
+// getValue answers the value a pointer points to
+func getValue(doc any, pointer string) (any, error) {
+	tokens, err := jsonPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		switch node := doc.(type) {
+		case map[string]any:
+			child, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%s does not exist", pointer)
+			}
+			doc = child
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", pointer, err)
+			}
+			doc = node[index]
+		default:
+			return nil, fmt.Errorf("%s does not exist", pointer)
+		}
+	}
+	return doc, nil
+}
+
+// addValue adds a value at a pointer, inserting into arrays
+func addValue(doc any, pointer string, value any) (any, error) {
+	tokens, err := jsonPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return patchValue(doc, tokens, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case nil:
+			return value, nil
+		case map[string]any:
+			node[token] = value
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+			return append(node[:index], append([]any{value}, node[index:]...)...), nil
+		}
+		return nil, fmt.Errorf("%s: cannot add here", pointer)
+	})
+}
+
+// removeValue removes the value at a pointer
+func removeValue(doc any, pointer string) (any, error) {
+	tokens, err := jsonPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("cannot remove the whole canvas")
+	}
+	return patchValue(doc, tokens, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			if _, ok := node[token]; !ok {
+				return nil, fmt.Errorf("%s does not exist", pointer)
+			}
+			delete(node, token)
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", pointer, err)
+			}
+			return append(node[:index], node[index+1:]...), nil
+		}
+		return nil, fmt.Errorf("%s: cannot remove here", pointer)
+	})
+}
+
+// replaceValue replaces the value at a pointer, which must exist
+func replaceValue(doc any, pointer string, value any) (any, error) {
+	tokens, err := jsonPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return patchValue(doc, tokens, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case nil:
+			return value, nil
+		case map[string]any:
+			if _, ok := node[token]; !ok {
+				return nil, fmt.Errorf("%s does not exist", pointer)
+			}
+			node[token] = value
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", pointer, err)
+			}
+			node[index] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("%s: cannot replace here", pointer)
+	})
+}
+
+// decodeJSONValue decodes a JSON value keeping numbers as they were written
+func decodeJSONValue(content []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// copyJSONValue copies a decoded value so changes to one leave the other be
+func copyJSONValue(value any) any {
+	switch node := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(node))
+		for key, child := range node {
+			copied[key] = copyJSONValue(child)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(node))
+		for i, child := range node {
+			copied[i] = copyJSONValue(child)
+		}
+		return copied
+	}
+	return value
+}
+
+// applyJSONPatch applies the operations of a JSON Patch document in order,
+// failing on the first one that cannot be applied
+func applyJSONPatch(doc any, operations []patchOperation) (any, error) {
+	for i, op := range operations {
+		var err error
+		var value any
+		if op.Op == "add" || op.Op == "replace" || op.Op == "test" {
+			if op.Value == nil {
+				return nil, fmt.Errorf("operation %d (%s): value is missing", i+1, op.Op)
+			}
+			if value, err = decodeJSONValue(op.Value); err != nil {
+				return nil, fmt.Errorf("operation %d (%s): %w", i+1, op.Op, err)
+			}
+		}
+		switch op.Op {
+		case "add":
+			doc, err = addValue(doc, op.Path, value)
+		case "remove":
+			doc, err = removeValue(doc, op.Path)
+		case "replace":
+			doc, err = replaceValue(doc, op.Path, value)
+		case "move", "copy":
+			var moved any
+			if moved, err = getValue(doc, op.From); err != nil {
+				break
+			}
+			if op.Op == "move" {
+				if strings.HasPrefix(op.Path, op.From+"/") {
+					err = fmt.Errorf("cannot move %s into itself", op.From)
+					break
+				}
+				if doc, err = removeValue(doc, op.From); err != nil {
+					break
+				}
+			} else {
+				moved = copyJSONValue(moved)
+			}
+			doc, err = addValue(doc, op.Path, moved)
+		case "test":
+			var found any
+			if found, err = getValue(doc, op.Path); err == nil && !sameJSONValue(found, value) {
+				err = fmt.Errorf("%s does not hold the expected value", op.Path)
+			}
+		default:
+			err = fmt.Errorf("unknown operation %q", op.Op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i+1, op.Op, err)
+		}
+	}
+	return doc, nil
+}
+
+// sameJSONValue compares decoded values, numbers by their value
+func sameJSONValue(a, b any) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// patchCanvas applies a JSON Patch document to a canvas file, answering the
+// patched file once it has been checked to be a valid canvas
+func patchCanvas(content, patch []byte) ([]byte, error) {
+	var operations []patchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("not a JSON Patch document: %w", err)
+	}
+	// Paths refer to the current file format
+	content, err := migrateCanvasFile(content)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeJSONValue(content)
+	if err != nil {
+		return nil, err
+	}
+	if doc, err = applyJSONPatch(doc, operations); err != nil {
+		return nil, err
+	}
+	patched, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	data, err := checkCanvasFile(patched)
+	if err != nil {
+		return nil, fmt.Errorf("the patched canvas is invalid: %w", err)
+	}
+	// Written as the app writes canvases, so saved files diff cleanly
+	return json.MarshalIndent(data, "", "    ")
+}
+
+// checkCanvasFile checks that a file in the current format holds only the
+// fields of a canvas, with values of the right types
+func checkCanvasFile(content []byte) (CanvasData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	var data CanvasData
+	if err := decoder.Decode(&data); err != nil {
+		return data, err
+	}
+	if data.FormatVersion != canvasFormatVersion {
+		return data, fmt.Errorf("formatVersion must stay %d", canvasFormatVersion)
+	}
+	return data, nil
+}
+
+// runPatch applies a JSON Patch document to a canvas file, or to a canvas
+// in a server workspace, which keeps the version it replaces:
+//
+//	business-canvas patch canvas.json changes.json
+//	business-canvas patch --server https://canvas.example.com --org acme --token $TOKEN plan changes.json
+func runPatch(args []string) error {
+	flags := flag.NewFlagSet("patch", flag.ContinueOnError)
+	server := flags.String("server", "", "workspace server to patch a canvas on instead of a file")
+	org := flags.String("org", "", "organization of the canvas on the server")
+	token := flags.String("token", os.Getenv("BUSINESS_CANVAS_TOKEN"), "member token for the server")
+	commit := flags.Bool("git", false, "commit the patched file to the Git repository it is in")
+	dryRun := flags.Bool("dry-run", false, "print the patched canvas instead of saving it")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("give the canvas and the patch file, - reads the patch from standard input")
+	}
+	target, patchFile := flags.Arg(0), flags.Arg(1)
+	var patch []byte
+	var err error
+	if patchFile == "-" {
+		patch, err = io.ReadAll(os.Stdin)
+	} else {
+		patch, err = os.ReadFile(patchFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *server != "" {
+		if *dryRun || *commit {
+			return errors.New("--dry-run and --git apply to canvas files only")
+		}
+		store := workspaceStore{URL: *server, Org: *org, Token: *token}
+		version, err := store.Patch(target, patch)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("patched %s, now version %d\n", target, version)
+		return nil
+	}
+
+	content, err := os.ReadFile(target)
+	if err != nil {
+		return err
+	}
+	patched, err := patchCanvas(content, patch)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		_, err := os.Stdout.Write(append(patched, '\n'))
+		return err
+	}
+	if err := writeLocalFile(target, patched); err != nil {
+		return err
+	}
+	if *commit {
+		return gitCommitFile(target, "Patch "+patchFile, false)
+	}
+	return nil
+}
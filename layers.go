@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Layers of the Triple Layered Business Model Canvas, the economic layer is
+// the standard canvas
+const (
+	layerEconomic      = "Economic"
+	layerEnvironmental = "Environmental"
+	layerSocial        = "Social"
+)
+
+// canvasLayer describes an additional layer whose nine blocks sit in the
+// same grid positions as the economic canvas
+type canvasLayer struct {
+	Name    string
+	Titles  [9]string
+	Prompts [9]string
+}
+
+var canvasLayers = []*canvasLayer{
+	{
+		Name: layerEnvironmental,
+		Titles: [9]string{
+			"Supplies and Outsourcing",
+			"Production",
+			"Materials",
+			"Functional Value",
+			"End-of-Life",
+			"Distribution",
+			"Use Phase",
+			"Environmental Impacts",
+			"Environmental Benefits",
+		},
+		Prompts: [9]string{
+			"Which materials and production activities are outsourced or supplied by others?",
+			"What are the main value-creating production activities and their footprint?",
+			"Which bio-physical stocks are used to render the functional value?",
+			"What is the functional unit of service or product delivered to customers?",
+			"What happens when the customer ends consumption of the product?",
+			"How is the product physically delivered, and with what transport modes?",
+			"What is the impact of the customer's use of the product?",
+			"What are the ecological costs of the organization's actions?",
+			"What ecological value does the organization create through reducing impacts?",
+		},
+	},
+	{
+		Name: layerSocial,
+		Titles: [9]string{
+			"Local Communities",
+			"Governance",
+			"Employees",
+			"Social Value",
+			"Societal Culture",
+			"Scale of Outreach",
+			"End-User",
+			"Social Impacts",
+			"Social Benefits",
+		},
+		Prompts: [9]string{
+			"How does the organization build relationships with local communities?",
+			"How is the organization structured and how are decisions made?",
+			"What role do employees play, and how are they treated?",
+			"What is the mission of the organization focused on creating benefits for stakeholders?",
+			"What is the organization's potential impact on society as a whole?",
+			"How deep and broad are the relationships built with stakeholders over time?",
+			"How does the value proposition address the needs of the end-user?",
+			"What are the social costs of the organization?",
+			"What positive social value does the organization create?",
+		},
+	},
+}
+
+// findLayer returns an additional layer by name, nil for the economic layer
+func findLayer(name string) *canvasLayer {
+	for _, layer := range canvasLayers {
+		if layer.Name == name {
+			return layer
+		}
+	}
+	return nil
+}
+
+// layerData returns the stored blocks of an additional layer
+func (d CanvasData) layerData(name string) map[string]string {
+	switch name {
+	case layerEnvironmental:
+		return d.Environmental
+	case layerSocial:
+		return d.Social
+	}
+	return nil
+}
+
+// layerSections returns the layer blocks in reading order
+func (d CanvasData) layerSections(layer *canvasLayer) []sectionContent {
+	blocks := d.layerData(layer.Name)
+	sections := make([]sectionContent, len(layer.Titles))
+	for i, title := range layer.Titles {
+		sections[i] = sectionContent{title, blocks[title]}
+	}
+	return sections
+}
+
+// initLayers creates the editors for the additional layers
+func (c *Canvas) initLayers() {
+	c.activeLayer = layerEconomic
+	c.layerEntries = make(map[string][9]*widget.Entry)
+	for _, layer := range canvasLayers {
+		var entries [9]*widget.Entry
+		for i := range entries {
+			entries[i] = widget.NewMultiLineEntry()
+			entries[i].SetPlaceHolder(layer.Prompts[i])
+			c.setupDynamicValidation(entries[i], layer.Titles[i])
+		}
+		c.layerEntries[layer.Name] = entries
+	}
+}
+
+// layerText collects the non-empty blocks of a layer, nil when all are empty
+func (c *Canvas) layerText(name string) map[string]string {
+	layer := findLayer(name)
+	var blocks map[string]string
+	for i, entry := range c.layerEntries[name] {
+		if entry.Text == "" {
+			continue
+		}
+		if blocks == nil {
+			blocks = make(map[string]string)
+		}
+		blocks[layer.Titles[i]] = entry.Text
+	}
+	return blocks
+}
+
+func (c *Canvas) setLayerText(data CanvasData) {
+	for _, layer := range canvasLayers {
+		blocks := data.layerData(layer.Name)
+		for i, entry := range c.layerEntries[layer.Name] {
+			entry.SetText(blocks[layer.Titles[i]])
+		}
+	}
+}
+
+func (c *Canvas) createLayerContent(layer *canvasLayer) *fyne.Container {
+	grid := container.New(businessLayout)
+	for i, entry := range c.layerEntries[layer.Name] {
+		grid.Add(c.createSection(layer.Titles[i], entry, layer.Prompts[i]))
+	}
+	return grid
+}
+
+// createLayerSelect switches the grid between the canvas layers
+func (c *Canvas) createLayerSelect() *widget.Select {
+	options := []string{layerEconomic}
+	for _, layer := range canvasLayers {
+		options = append(options, layer.Name)
+	}
+
+	layerSelect := widget.NewSelect(options, func(selected string) {
+		if selected == c.activeLayer {
+			return
+		}
+		c.activeLayer = selected
+		c.refreshLayout()
+	})
+	layerSelect.SetSelected(c.activeLayer)
+	return layerSelect
+}
+
+// drawLayerPages adds a page for every additional layer that has content,
+// layers only apply to the Business Model Canvas
+func drawLayerPages(pdf *gofpdf.Fpdf, opts pdfOptions, data CanvasData) {
+	if data.canvasType().ID != canvasTypeBusiness {
+		return
+	}
+	for _, layer := range canvasLayers {
+		if len(data.layerData(layer.Name)) == 0 {
+			continue
+		}
+		pdf.AddPage()
+		_, top, _, _ := pdf.GetMargins()
+		pdf.SetFont(pdfFontFamily, "B", 10)
+		pdf.Text(10, top-3, layer.Name+" Layer")
+		drawCanvasGrid(pdf, opts, businessLayout, data.layerSections(layer), nil)
+	}
+}
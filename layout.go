@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+)
+
+// blockPlacement positions a block on a canvas layout grid, in cells
+type blockPlacement struct {
+	Col     int `json:"col"`
+	Row     int `json:"row"`
+	ColSpan int `json:"colSpan"`
+	RowSpan int `json:"rowSpan"`
+}
+
+// canvasLayout places blocks on a grid of equally sized cells. It is used
+// both as a fyne.Layout for the editor and to position blocks in exports.
+type canvasLayout struct {
+	Columns int              `json:"columns"`
+	Rows    int              `json:"rows"`
+	Blocks  []blockPlacement `json:"blocks"`
+}
+
+// businessLayout is the classic Business Model Canvas arrangement: five
+// columns over 60% of the height and two halves below
+var businessLayout = canvasLayout{
+	Columns: 10,
+	Rows:    10,
+	Blocks: []blockPlacement{
+		{Col: 0, Row: 0, ColSpan: 2, RowSpan: 6}, // Key Partners
+		{Col: 2, Row: 0, ColSpan: 2, RowSpan: 3}, // Key Activities
+		{Col: 2, Row: 3, ColSpan: 2, RowSpan: 3}, // Key Resources
+		{Col: 4, Row: 0, ColSpan: 2, RowSpan: 6}, // Value Proposition
+		{Col: 6, Row: 0, ColSpan: 2, RowSpan: 3}, // Customer Relationships
+		{Col: 6, Row: 3, ColSpan: 2, RowSpan: 3}, // Channels
+		{Col: 8, Row: 0, ColSpan: 2, RowSpan: 6}, // Customer Segments
+		{Col: 0, Row: 6, ColSpan: 5, RowSpan: 4}, // Cost Structure
+		{Col: 5, Row: 6, ColSpan: 5, RowSpan: 4}, // Revenue Streams
+	},
+}
+
+// teamLayout arranges the Team Canvas: people on the left, goals, purpose
+// and values in the middle, needs and rules on the right
+var teamLayout = canvasLayout{
+	Columns: 10,
+	Rows:    10,
+	Blocks: []blockPlacement{
+		{Col: 0, Row: 0, ColSpan: 2, RowSpan: 6}, // People & Roles
+		{Col: 2, Row: 0, ColSpan: 2, RowSpan: 3}, // Common Goals
+		{Col: 2, Row: 3, ColSpan: 2, RowSpan: 3}, // Personal Goals
+		{Col: 4, Row: 0, ColSpan: 2, RowSpan: 3}, // Purpose
+		{Col: 4, Row: 3, ColSpan: 2, RowSpan: 3}, // Values
+		{Col: 6, Row: 0, ColSpan: 2, RowSpan: 6}, // Needs & Expectations
+		{Col: 8, Row: 0, ColSpan: 2, RowSpan: 6}, // Rules & Activities
+		{Col: 0, Row: 6, ColSpan: 5, RowSpan: 4}, // Strengths & Assets
+		{Col: 5, Row: 6, ColSpan: 5, RowSpan: 4}, // Weaknesses & Risks
+	},
+}
+
+// cultureLayout arranges the Culture Map: outcomes above the behaviors that
+// produce them, with enablers and blockers side by side below
+var cultureLayout = canvasLayout{
+	Columns: 10,
+	Rows:    10,
+	Blocks: []blockPlacement{
+		{Col: 0, Row: 0, ColSpan: 10, RowSpan: 3}, // Outcomes
+		{Col: 0, Row: 3, ColSpan: 10, RowSpan: 3}, // Behaviors
+		{Col: 0, Row: 6, ColSpan: 5, RowSpan: 4},  // Enablers
+		{Col: 5, Row: 6, ColSpan: 5, RowSpan: 4},  // Blockers
+	},
+}
+
+// valueLayout arranges the Value Proposition Canvas: the value map on the
+// left facing the customer profile on the right, gains above pains
+var valueLayout = canvasLayout{
+	Columns: 10,
+	Rows:    10,
+	Blocks: []blockPlacement{
+		{Col: 8, Row: 0, ColSpan: 2, RowSpan: 10}, // Customer Jobs
+		{Col: 5, Row: 5, ColSpan: 3, RowSpan: 5},  // Pains
+		{Col: 5, Row: 0, ColSpan: 3, RowSpan: 5},  // Gains
+		{Col: 0, Row: 0, ColSpan: 2, RowSpan: 10}, // Products & Services
+		{Col: 2, Row: 5, ColSpan: 3, RowSpan: 5},  // Pain Relievers
+		{Col: 2, Row: 0, ColSpan: 3, RowSpan: 5},  // Gain Creators
+	},
+}
+
+// environmentLayout arranges the Business Model Environment as it wraps
+// around a canvas: trends above, industry and market forces either side and
+// macroeconomic forces below
+var environmentLayout = canvasLayout{
+	Columns: 10,
+	Rows:    10,
+	Blocks: []blockPlacement{
+		{Col: 5, Row: 3, ColSpan: 5, RowSpan: 4},  // Market Forces
+		{Col: 0, Row: 3, ColSpan: 5, RowSpan: 4},  // Industry Forces
+		{Col: 0, Row: 0, ColSpan: 10, RowSpan: 3}, // Key Trends
+		{Col: 0, Row: 7, ColSpan: 10, RowSpan: 3}, // Macroeconomic Forces
+	},
+}
+
+// Layout implements fyne.Layout, objects are placed in block order
+func (l canvasLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	cellWidth := size.Width / float32(l.Columns)
+	cellHeight := size.Height / float32(l.Rows)
+	for i, object := range objects {
+		if i >= len(l.Blocks) {
+			object.Hide()
+			continue
+		}
+		block := l.Blocks[i]
+		object.Move(fyne.NewPos(float32(block.Col)*cellWidth, float32(block.Row)*cellHeight))
+		object.Resize(fyne.NewSize(float32(block.ColSpan)*cellWidth, float32(block.RowSpan)*cellHeight))
+	}
+}
+
+// MinSize implements fyne.Layout, every cell must fit its smallest block
+func (l canvasLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	var cellWidth, cellHeight float32
+	for i, object := range objects {
+		if i >= len(l.Blocks) {
+			break
+		}
+		block := l.Blocks[i]
+		min := object.MinSize()
+		if w := min.Width / float32(block.ColSpan); w > cellWidth {
+			cellWidth = w
+		}
+		if h := min.Height / float32(block.RowSpan); h > cellHeight {
+			cellHeight = h
+		}
+	}
+	return fyne.NewSize(cellWidth*float32(l.Columns), cellHeight*float32(l.Rows))
+}
+
+// pdfCell is the box a section is drawn in on a PDF page
+type pdfCell struct {
+	X, Y, W, H float64
+}
+
+// layoutCells places up to count blocks of a layout within the box
+func layoutCells(layout canvasLayout, count int, x, y, w, h float64) []pdfCell {
+	cellWidth := w / float64(layout.Columns)
+	cellHeight := h / float64(layout.Rows)
+	var cells []pdfCell
+	for i, block := range layout.Blocks {
+		if i >= count {
+			break
+		}
+		cells = append(cells, pdfCell{
+			X: x + float64(block.Col)*cellWidth, Y: y + float64(block.Row)*cellHeight,
+			W: float64(block.ColSpan) * cellWidth, H: float64(block.RowSpan) * cellHeight,
+		})
+	}
+	return cells
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ItemLink ties an item of a section, one of its lines, to another canvas
+// file, such as a customer segment to its Value Proposition Canvas
+type ItemLink struct {
+	Section string `json:"section"`
+	Item    string `json:"item"`
+	Canvas  string `json:"canvas"`
+}
+
+// backlink is a canvas file linking to the open one
+type backlink struct {
+	Path string
+	Link ItemLink
+}
+
+// sectionText is the text of a section by title
+func sectionText(data CanvasData, title string) string {
+	for _, section := range data.sections() {
+		if section.Title == title {
+			return section.Text
+		}
+	}
+	return ""
+}
+
+// sectionLinks are the links of the items of a section
+func (c *Canvas) sectionLinks(section string) []ItemLink {
+	var links []ItemLink
+	for _, link := range c.itemLinks {
+		if link.Section == section {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// sameCanvasFile reports whether a link target names the file at uri
+func sameCanvasFile(location string, uri fyne.URI) bool {
+	target, err := parseLocation(location)
+	if err != nil || uri == nil {
+		return false
+	}
+	if target.Scheme() == "file" && uri.Scheme() == "file" {
+		return filepath.Clean(target.Path()) == filepath.Clean(uri.Path())
+	}
+	return target.String() == uri.String()
+}
+
+// linkName is the file name a link target is shown by
+func linkName(location string) string {
+	if uri, err := parseLocation(location); err == nil {
+		return uri.Name()
+	}
+	return location
+}
+
+// openCanvasLocation opens the canvas file at a path or URI
+func (c *Canvas) openCanvasLocation(location string) {
+	uri, err := parseLocation(location)
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	data, err := readCanvasURI(uri)
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	c.confirmReplace("Opening "+uri.Name(), data, func() {
+		c.applyCanvasFile(data)
+		c.watchOpenFile(uri)
+	})
+}
+
+// linkSectionItem links one of the items of a section to a canvas file
+func (c *Canvas) linkSectionItem(section string, entry *widget.Entry) {
+	items := sectionLines(entry.Text)
+	if len(items) == 0 {
+		dialog.ShowError(errors.New("the section has no items, list one per line"), c.window)
+		return
+	}
+	item := widget.NewSelect(items, nil)
+	item.SetSelectedIndex(0)
+	dialog.ShowForm("Link Item", "Choose Canvas...", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Item", item),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+			if _, err := readCanvasData(reader); err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			c.undoStack = append(c.undoStack, c.getCurrentData())
+			c.itemLinks = append(c.itemLinks, ItemLink{Section: section, Item: item.Selected, Canvas: reader.URI().String()})
+		}, c.window)
+	}, c.window)
+}
+
+// linkedCanvasesMenu lists the canvases the items of a section link to, for
+// the section menu
+func (c *Canvas) linkedCanvasesMenu(section string) *fyne.MenuItem {
+	var items []*fyne.MenuItem
+	for _, link := range c.sectionLinks(section) {
+		items = append(items, fyne.NewMenuItem(link.Item+" → "+linkName(link.Canvas), func() {
+			c.openCanvasLocation(link.Canvas)
+		}))
+	}
+	linked := fyne.NewMenuItem("Open Linked Canvas", nil)
+	linked.Icon = theme.NavigateNextIcon()
+	linked.Disabled = len(items) == 0
+	linked.ChildMenu = fyne.NewMenu("", items...)
+	return linked
+}
+
+// findBacklinks lists the canvases in a folder and its subfolders linking
+// to the file at uri, by an item link, as the Business Model Canvas of a
+// Value Proposition Canvas or as the environment of a canvas
+func findBacklinks(root string, uri fyne.URI) ([]backlink, error) {
+	var backlinks []backlink
+	err := walkCanvases(root, func(path string, data CanvasData) {
+		for _, link := range data.Links {
+			if sameCanvasFile(link.Canvas, uri) {
+				backlinks = append(backlinks, backlink{Path: path, Link: link})
+			}
+		}
+		if link := data.SegmentLink; link != nil && sameCanvasFile(link.Canvas, uri) {
+			backlinks = append(backlinks, backlink{Path: path, Link: ItemLink{Section: "Customer Segments", Item: link.Segment}})
+		}
+		if data.EnvironmentMap != "" && sameCanvasFile(data.EnvironmentMap, uri) {
+			backlinks = append(backlinks, backlink{Path: path, Link: ItemLink{Section: "Business Model Environment", Item: "set in this environment"}})
+		}
+	})
+	return backlinks, err
+}
+
+// backlinkFolder is where backlinks are looked for: the project, or else
+// the folder of the open file
+func (c *Canvas) backlinkFolder() string {
+	if c.project != "" {
+		return c.project
+	}
+	if c.openFile != nil && c.openFile.Scheme() == "file" {
+		return filepath.Dir(c.openFile.Path())
+	}
+	return ""
+}
+
+// showLinks shows the links of the canvas and the canvases linking to it,
+// opening one when it is chosen
+func (c *Canvas) showLinks() {
+	var d dialog.Dialog
+	current := c.getCurrentData()
+
+	var outgoing *widget.List
+	outgoing = widget.NewList(
+		func() int { return len(c.itemLinks) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.DeleteIcon(), nil), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			link := c.itemLinks[id]
+			row := obj.(*fyne.Container)
+			text := fmt.Sprintf("%s: %s → %s", link.Section, link.Item, linkName(link.Canvas))
+			if !slices.Contains(sectionLines(sectionText(current, link.Section)), link.Item) {
+				text += " (item no longer in the section)"
+			}
+			row.Objects[0].(*widget.Label).SetText(text)
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				c.undoStack = append(c.undoStack, c.getCurrentData())
+				c.itemLinks = slices.Delete(slices.Clone(c.itemLinks), id, id+1)
+				outgoing.UnselectAll()
+				outgoing.Refresh()
+			}
+		},
+	)
+	outgoing.OnSelected = func(id widget.ListItemID) {
+		d.Hide()
+		c.openCanvasLocation(c.itemLinks[id].Canvas)
+	}
+
+	var backlinks []backlink
+	status := widget.NewLabel("Save the canvas to a file to see which canvases link to it")
+	if folder := c.backlinkFolder(); folder != "" && c.openFile != nil {
+		found, err := findBacklinks(folder, c.openFile)
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		backlinks = found
+		status.SetText(fmt.Sprintf("%d canvases in %s link here", len(backlinks), filepath.Base(folder)))
+	}
+	incoming := widget.NewList(
+		func() int { return len(backlinks) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			link := backlinks[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s - %s: %s", filepath.Base(link.Path), link.Link.Section, link.Link.Item))
+		},
+	)
+	incoming.OnSelected = func(id widget.ListItemID) {
+		d.Hide()
+		c.openCanvasLocation(backlinks[id].Path)
+	}
+
+	content := container.NewGridWithRows(2,
+		container.NewBorder(widget.NewLabelWithStyle("Links", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), nil, nil, nil, outgoing),
+		container.NewBorder(container.NewVBox(widget.NewLabelWithStyle("Backlinks", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), status), nil, nil, nil, incoming),
+	)
+	d = dialog.NewCustom("Links", "Close", content, c.window)
+	d.Resize(fyne.NewSize(700, 500))
+	d.Show()
+}
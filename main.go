@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"image/color"
 	"io"
+	"os"
+	"slices"
+	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -14,6 +16,7 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/storage/repository"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"github.com/google/uuid"
@@ -22,6 +25,8 @@ import (
 
 // CanvasData represents the data structure for saving/loading
 type CanvasData struct {
+	FormatVersion    int    `json:"formatVersion"`
+	CanvasType       string `json:"canvasType,omitempty"`
 	KeyPartners      string `json:"keyPartners"`
 	KeyActivities    string `json:"keyActivities"`
 	KeyResources     string `json:"keyResources"`
@@ -31,14 +36,112 @@ type CanvasData struct {
 	CustomerSegments string `json:"customerSegments"`
 	CostStructure    string `json:"costStructure"`
 	RevenueStreams   string `json:"revenueStreams"`
+
+	// CustomSections are user-defined blocks shown after the standard nine
+	CustomSections []CustomSection `json:"customSections,omitempty"`
+
+	// Environmental and Social hold the additional layers of the Triple
+	// Layered Business Model Canvas, keyed by block title
+	Environmental map[string]string `json:"environmental,omitempty"`
+	Social        map[string]string `json:"social,omitempty"`
+
+	// SectionEdited records when each section last changed meaningfully
+	SectionEdited map[string]time.Time `json:"sectionEdited,omitempty"`
+	// SectionEditors records who made that change
+	SectionEditors map[string]string `json:"sectionEditors,omitempty"`
+
+	// PresenterNotes are hidden notes per section, shown only in the
+	// presenter view and left out of standard exports
+	PresenterNotes map[string]string `json:"presenterNotes,omitempty"`
+
+	// CRDT holds the character-level edit history of each section so
+	// concurrent edits from several machines can be merged. Only files
+	// carry it, see canvasFileData.
+	CRDT map[string]*SectionCRDT `json:"crdt,omitempty"`
+
+	// DateDisplay is how timestamps were shown where the canvas was saved,
+	// so exports made elsewhere show them the same
+	DateDisplay *DateDisplay `json:"dateDisplay,omitempty"`
+
+	// SegmentLink ties a Value Proposition Canvas to a customer segment of
+	// a Business Model Canvas
+	SegmentLink *SegmentLink `json:"segmentLink,omitempty"`
+
+	// Links tie items of sections to other canvas files
+	Links []ItemLink `json:"links,omitempty"`
+
+	// Assumptions are the beliefs sections rest on, with their evidence
+	Assumptions []Assumption `json:"assumptions,omitempty"`
+
+	// Validation is the outcome of the validation rules when last run
+	Validation *ValidationSummary `json:"validation,omitempty"`
+
+	// VersionChain are the hash-chained versions of the canvas, kept in
+	// the file so the history can be verified after it is opened again.
+	// Only files carry it, see canvasFileData.
+	VersionChain []Version `json:"versionChain,omitempty"`
+
+	// EnvironmentMap names the Business Model Environment file a Business
+	// Model Canvas is set in, drawn around it in PDF exports
+	EnvironmentMap string `json:"environmentMap,omitempty"`
+
+	// Settings override the app settings while the canvas is open
+	Settings *CanvasSettings `json:"settings,omitempty"`
+}
+
+// sectionContent pairs a section title with its text
+type sectionContent struct {
+	Title string
+	Text  string
+}
+
+// sections returns the canvas sections in reading order, titled for the
+// canvas type
+func (d CanvasData) sections() []sectionContent {
+	texts := []string{
+		d.KeyPartners,
+		d.KeyActivities,
+		d.KeyResources,
+		d.ValueProposition,
+		d.CustomerRel,
+		d.Channels,
+		d.CustomerSegments,
+		d.CostStructure,
+		d.RevenueStreams,
+	}
+	var sections []sectionContent
+	for i, title := range d.canvasType().Titles {
+		sections = append(sections, sectionContent{title, texts[i]})
+	}
+	for _, custom := range d.CustomSections {
+		sections = append(sections, sectionContent{custom.Title, custom.Text})
+	}
+	return sections
 }
 
 // Version represents a snapshot of the canvas
 type Version struct {
-	ID        string
-	Timestamp time.Time
-	Data      CanvasData
-	Comments  []Comment
+	ID         string
+	Timestamp  time.Time
+	Data       CanvasData
+	Comments   []Comment
+	Attendance []Attendance `json:",omitempty"`
+	Name       string       `json:",omitempty"`
+	Tags       []string     `json:",omitempty"`
+	Note       string       `json:",omitempty"`
+	Branch     string       `json:",omitempty"`
+	// Author is who saved the version, with the initials and color of
+	// their identity
+	Author         string `json:",omitempty"`
+	AuthorInitials string `json:",omitempty"`
+	AuthorColor    string `json:",omitempty"`
+	// PrevHash and Hash chain the version to the one saved before it when
+	// the hash chain is on
+	PrevHash string `json:",omitempty"`
+	Hash     string `json:",omitempty"`
+	// Pruned are the chained versions the retention policy removed right
+	// before this one, oldest first
+	Pruned []prunedVersion `json:",omitempty"`
 }
 
 // Comment represents user feedback on canvas sections
@@ -48,6 +151,11 @@ type Comment struct {
 	Text      string
 	Author    string
 	Timestamp time.Time
+	ParentID  string `json:",omitempty"` // comment this one replies to
+	Resolved  bool   `json:",omitempty"`
+	// AuthorInitials and AuthorColor are the rest of the author's identity
+	AuthorInitials string `json:",omitempty"`
+	AuthorColor    string `json:",omitempty"`
 }
 
 // Canvas represents the main application structure
@@ -72,10 +180,87 @@ type Canvas struct {
 	writer           fyne.Window
 	window           fyne.Window // Added missing field
 	versions         []Version
+	branch           string
+	recording        *SessionRecording
+	lastRecording    *SessionRecording
+	healthButton     *hintButton
+	prefs            fyne.Preferences
+	sectionEdited    map[string]time.Time
+	sectionEditors   map[string]string
+	knownConflicts   map[string]bool
+	heatCells        map[string]*canvas.Rectangle
+	heatMode         string
+	heatPeriod       time.Duration
+	segmentLink      *SegmentLink
+	itemLinks        []ItemLink
+	assumptions      []Assumption
+	lastValidation   []ValidationResult // findings of the last validation run
+	environmentMap   string
+	canvasSettings   *CanvasSettings
+	templateErrors   []templateError
+	validationConfig *ValidationConfig
+	validationErr    error
+	project          string
+	projectPick      string
+	projectPane      *fyne.Container
+	projectTree      *widget.Tree
+	lastEditLabel    *widget.Label
+	sectionBaseline  map[string]string
+	snapshotBase     map[string]string
+	settingData      bool
+	stalenessButton  *hintButton
+	mentionsButton   *hintButton
+	customBlocks     []*customBlock
+	mainArea         *fyne.Container
+	activeLayer      string
+	layerEntries     map[string][9]*widget.Entry
+	canvasTypeID     string
+	wordCloud        *fyne.Container
+	presenterNotes   map[string]string
+	sectionCRDT      map[string]*SectionCRDT
+	presence         map[string]PresenceUpdate
+	presenceBar      *fyne.Container
+	presenceSink     func(PresenceUpdate)
+	lastGitCommit    time.Time
+	typingLabels     map[string]*widget.Label
+	autoFits         map[*widget.Entry]*autoFitEntry
+	tooltips         *tooltipLayer
+	commentBadges    map[string]*hintButton
+	validationMarks  map[string]*hintButton
+	validationButton *hintButton
+	lockedSections   map[string]bool
+	savedData        CanvasData // canvas as last saved, loaded or started
+	collab           *collabHub
+	share            *shareServer
+	openFile         fyne.URI // canvas file last opened or saved
+	openStamp        fileStamp
+	stopFileWatch    chan struct{}
+	externalChange   bool // the open file changed on disk and was kept
+	changedPrompt    *dialog.CustomDialog
+	collabStatus     string
+	// collabMu guards the state the collaboration goroutines share with the
+	// UI: sectionCRDT, queuedEdits, presence, sectionEditors, sectionEdited,
+	// typedAt, remoteLocks and remoteTexts
+	collabMu    sync.Mutex
+	remoteLocks map[string]bool // sections locked while others type
+	remoteTexts map[string]string
+	typedAt     map[string]time.Time
+	queuedEdits map[string][]*SectionCRDT
 }
 
 func main() {
-	myApp := app.New()
+	// Subcommands such as watch run without opening the app
+	if len(os.Args) > 1 {
+		if handled, code := runCommand(os.Args[1:]); handled {
+			os.Exit(code)
+		}
+	}
+	// Over SSH or in a container the app cannot open, the subcommands still run
+	if !hasDisplay() {
+		os.Exit(noDisplay())
+	}
+
+	myApp := app.NewWithID("com.cardozasrvices.businesscanvas")
 	myWindow := myApp.NewWindow("Business Canvas")
 
 	// Create canvas with enhanced features
@@ -93,23 +278,58 @@ func main() {
 		autoSave:         true,
 		validator:        NewBusinessValidator(),
 		progressBar:      widget.NewProgressBar(),
+		sectionEdited:    make(map[string]time.Time),
+		sectionEditors:   make(map[string]string),
+		knownConflicts:   make(map[string]bool),
+		heatCells:        make(map[string]*canvas.Rectangle),
+		sectionBaseline:  make(map[string]string),
+		presenterNotes:   make(map[string]string),
+		sectionCRDT:      make(map[string]*SectionCRDT),
+		presence:         make(map[string]PresenceUpdate),
+		presenceBar:      container.NewHBox(),
+		typingLabels:     make(map[string]*widget.Label),
+		autoFits:         make(map[*widget.Entry]*autoFitEntry),
+		tooltips:         newTooltipLayer(),
+		commentBadges:    make(map[string]*hintButton),
+		validationMarks:  make(map[string]*hintButton),
+		lockedSections:   make(map[string]bool),
+		remoteLocks:      make(map[string]bool),
+		remoteTexts:      make(map[string]string),
+		typedAt:          make(map[string]time.Time),
+		queuedEdits:      make(map[string][]*SectionCRDT),
+		projectPane:      container.NewStack(),
 	}
 
 	canvas.window = myWindow
+	canvas.prefs = canvasPreferences{Preferences: myApp.Preferences(), canvas: canvas}
+	repository.Register(remoteScheme, remoteRepository{canvas: canvas})
+	// Template canvas types are loaded before anything shows the types
+	canvas.templateErrors = loadCanvasTemplates(templatesFolder())
+	applyDateSettings(canvas.prefs)
+	canvas.applyAccessibility()
+	canvas.validationConfig, canvas.validationErr = loadValidationConfig(validationFolder())
+	canvas.loadValidator()
 	// Initialize the canvas
 	canvas.initialize()
 
-	// Create toolbar
+	// Create menu bar and toolbar
+	canvas.setupMainMenu()
 	toolbar := canvas.createToolbar()
 
 	// Create main content
-	mainContent := canvas.createMainContent()
+	canvas.mainArea = container.NewStack(canvas.createMainContent())
+	canvas.resetSnapshotBase()
+	canvas.markSaved()
+	canvas.reopenProject()
 
 	// Create status bar
 	statusBar := canvas.createStatusBar()
 
 	// Combine all elements
-	myWindow.SetContent(container.NewBorder(toolbar, statusBar, nil, nil, mainContent))
+	myWindow.SetContent(container.NewStack(
+		container.NewBorder(toolbar, statusBar, canvas.projectPane, nil, canvas.mainArea),
+		canvas.tooltips.layer,
+	))
 	myWindow.Resize(fyne.NewSize(1400, 900))
 	myWindow.Show()
 
@@ -118,186 +338,236 @@ func main() {
 		if canvas.autoSave {
 			go canvas.autoSaveRoutine()
 		}
+		go canvas.presenceRoutine()
 	})
 
 	myApp.Run()
 }
 
 func (c *Canvas) initialize() {
-	// Set placeholders with enhanced descriptions
-	c.keyPartners.SetPlaceHolder("Who are your key partners and suppliers? What resources are you acquiring from them?")
-	c.keyActivities.SetPlaceHolder("What key activities does your value proposition require?")
-	c.keyResources.SetPlaceHolder("What key resources does your value proposition require?")
-	c.valueProposition.SetPlaceHolder("What value do you deliver to customers? Which problems are you solving?")
-	c.customerRel.SetPlaceHolder("What type of relationship does each customer segment expect?")
-	c.channels.SetPlaceHolder("Through which channels do your customers want to be reached?")
-	c.customerSegments.SetPlaceHolder("For whom are you creating value? Who are your most important customers?")
-	c.costStructure.SetPlaceHolder("What are the most important costs inherent in your business model?")
-	c.revenueStreams.SetPlaceHolder("For what value are your customers willing to pay? How would they prefer to pay?")
-
-	// Initialize validation
-	c.validator = NewBusinessValidator()
+	// Set placeholders, validation rules and dynamic validation for the
+	// selected canvas type
+	c.applyCanvasType(canvasTypeBusiness)
+
+	// Set up the environmental and social layers
+	c.initLayers()
 
 	// Set up keyboard shortcuts
 	c.setupKeyboardShortcuts()
+}
 
-	// Set up dynamic validation
-	c.setupDynamicValidation(c.keyPartners, "Key Partners")
-	c.setupDynamicValidation(c.keyActivities, "Key Activities")
-	c.setupDynamicValidation(c.keyResources, "Key Resources")
-	c.setupDynamicValidation(c.valueProposition, "Value Proposition")
-	c.setupDynamicValidation(c.customerRel, "Customer Relationships")
-	c.setupDynamicValidation(c.channels, "Channels")
-	c.setupDynamicValidation(c.customerSegments, "Customer Segments")
-	c.setupDynamicValidation(c.costStructure, "Cost Structure")
-	c.setupDynamicValidation(c.revenueStreams, "Revenue Streams")
+// toggleTheme switches between the light and dark themes
+func (c *Canvas) toggleTheme() {
+	if c.currentTheme == "professional" {
+		c.currentTheme = "light"
+		c.setTheme(theme.LightTheme())
+	} else {
+		c.currentTheme = "professional"
+		c.setTheme(theme.DarkTheme())
+	}
 }
 
 func (c *Canvas) createToolbar() *widget.Toolbar {
-	themeToggle := widget.NewToolbarAction(theme.ColorPaletteIcon(), func() {
-		if c.currentTheme == "professional" {
-			c.currentTheme = "light"
-			myApp := fyne.CurrentApp()
-			myApp.Settings().SetTheme(theme.LightTheme())
-		} else {
-			c.currentTheme = "professional"
-			myApp := fyne.CurrentApp()
-			myApp.Settings().SetTheme(theme.DarkTheme())
-		}
-	})
+	themeToggle := c.toolbarAction(theme.ColorPaletteIcon(), "Toggle light and dark theme", c.toggleTheme)
 
-	saveAction := widget.NewToolbarAction(theme.DocumentSaveIcon(), func() {
+	saveAction := c.toolbarAction(theme.DocumentSaveIcon(), "Save canvas", func() {
 		c.saveCanvas()
 	})
 
-	loadAction := widget.NewToolbarAction(theme.FolderOpenIcon(), func() {
+	loadAction := c.toolbarAction(theme.FolderOpenIcon(), "Open canvas", func() {
 		c.loadCanvas()
 	})
 
-	exportAction := widget.NewToolbarAction(theme.DocumentCreateIcon(), func() {
+	mergeAction := c.toolbarAction(theme.ContentPasteIcon(), "Merge from file", func() {
+		c.mergeFromFile()
+	})
+
+	interchangeAction := c.toolbarAction(theme.UploadIcon(), "Import and export other formats", func() {
+		c.showInterchange()
+	})
+
+	exportAction := c.toolbarAction(theme.DocumentCreateIcon(), "Export to PDF", func() {
 		c.exportToPDF()
 	})
 
-	validateAction := widget.NewToolbarAction(theme.ViewRefreshIcon(), func() {
+	okrAction := c.toolbarAction(theme.NavigateNextIcon(), "Generate OKRs", func() {
+		c.showOKRGenerator()
+	})
+
+	wordCloudAction := c.toolbarAction(theme.VisibilityIcon(), "Word cloud", func() {
+		c.showWordCloud()
+	})
+
+	summaryAction := c.toolbarAction(theme.FileTextIcon(), "Export summary", func() {
+		c.exportSummary()
+	})
+
+	copyAction := c.toolbarAction(theme.ContentCopyIcon(), "Copy canvas as text", func() {
+		c.copyAsText()
+	})
+
+	benchmarkAction := c.toolbarAction(theme.SearchIcon(), "Compare with benchmarks", func() {
+		c.showBenchmarkComparison()
+	})
+
+	validateAction := c.toolbarAction(theme.ViewRefreshIcon(), "Validate canvas", func() {
 		c.validateCanvas()
 	})
 
-	historyAction := widget.NewToolbarAction(theme.HistoryIcon(), func() {
+	boardPackAction := c.toolbarAction(theme.DocumentPrintIcon(), "Export board pack", func() {
+		c.exportBoardPack()
+	})
+
+	dataRoomAction := c.toolbarAction(theme.DownloadIcon(), "Export data room", func() {
+		c.exportDataRoom()
+	})
+
+	xlsxAction := c.toolbarAction(theme.GridIcon(), "Export to Excel", func() {
+		c.exportToXLSX()
+	})
+
+	presentAction := c.toolbarAction(theme.ComputerIcon(), "Present", func() {
+		c.startPresentation()
+	})
+
+	historyAction := c.toolbarAction(theme.HistoryIcon(), "Version history", func() {
 		c.showVersionHistory()
 	})
 
-	settingsAction := widget.NewToolbarAction(theme.SettingsIcon(), func() {
+	agendaAction := c.toolbarAction(theme.ListIcon(), "Workshop agenda", func() {
+		c.showAgendaBuilder()
+	})
+
+	recordAction := c.toolbarAction(theme.MediaRecordIcon(), "Record session", func() {
+		c.showRecording()
+	})
+
+	customAction := c.toolbarAction(theme.ContentAddIcon(), "Custom sections", func() {
+		c.showCustomSections()
+	})
+
+	settingsAction := c.toolbarAction(theme.SettingsIcon(), "Settings", func() {
 		c.showSettings()
 	})
 
 	return widget.NewToolbar(
 		saveAction,
 		loadAction,
+		mergeAction,
+		interchangeAction,
 		widget.NewToolbarSeparator(),
 		exportAction,
+		boardPackAction,
+		dataRoomAction,
+		xlsxAction,
+		summaryAction,
+		copyAction,
+		okrAction,
 		validateAction,
+		benchmarkAction,
 		widget.NewToolbarSeparator(),
 		historyAction,
+		presentAction,
+		agendaAction,
+		recordAction,
+		wordCloudAction,
+		customAction,
 		settingsAction,
 		widget.NewToolbarSeparator(),
 		themeToggle,
+		widget.NewToolbarSpacer(),
+		&presenceToolbarItem{avatars: c.presenceBar},
 	)
 }
 
 func (c *Canvas) createMainContent() *fyne.Container {
-	// Create section containers with tooltips
-	keyPartnersContainer := createSection("Key Partners", c.keyPartners, "Who are your key partners and suppliers? What resources are you acquiring from them?")
-	keyActivitiesContainer := createSection("Key Activities", c.keyActivities, "What key activities does your value proposition require?")
-	keyResourcesContainer := createSection("Key Resources", c.keyResources, "What key resources does your value proposition require?")
-	valuePropContainer := createSection("Value Proposition", c.valueProposition, "What value do you deliver to customers? Which problems are you solving?")
-	customerRelContainer := createSection("Customer Relationships", c.customerRel, "What type of relationship does each customer segment expect?")
-	channelsContainer := createSection("Channels", c.channels, "Through which channels do your customers want to be reached?")
-	customerSegContainer := createSection("Customer Segments", c.customerSegments, "For whom are you creating value? Who are your most important customers?")
-	costContainer := createSection("Cost Structure", c.costStructure, "What are the most important costs inherent in your business model?")
-	revenueContainer := createSection("Revenue Streams", c.revenueStreams, "For what value are your customers willing to pay? How would they prefer to pay?")
-
-	// Create the top grid
-	topGrid := container.NewGridWithColumns(5,
-		keyPartnersContainer,
-		container.NewGridWithRows(2,
-			keyActivitiesContainer,
-			keyResourcesContainer,
-		),
-		valuePropContainer,
-		container.NewGridWithRows(2,
-			customerRelContainer,
-			channelsContainer,
-		),
-		customerSegContainer,
-	)
-
-	// Create the bottom grid
-	bottomGrid := container.NewGridWithColumns(2,
-		costContainer,
-		revenueContainer,
-	)
-
-	// Combine top and bottom grids
-	return container.NewGridWithRows(2,
-		topGrid,
-		bottomGrid,
-	)
-}
+	// Environmental and social layers replace the nine standard sections of
+	// the Business Model Canvas
+	if layer := findLayer(c.activeLayer); layer != nil && c.canvasTypeID == canvasTypeBusiness {
+		return c.createLayerContent(layer)
+	}
 
-// HoverableRect implements desktop.Hoverable
-type HoverableRect struct {
-	canvas.Rectangle
-	popup   *widget.PopUp
-	tooltip string
-}
+	// Create section containers with tooltips, placed by the layout of
+	// the canvas type
+	kind := findCanvasType(c.canvasTypeID)
+	grid := container.New(kind.Layout)
+	for i, entry := range c.standardEntries()[:len(kind.Titles)] {
+		grid.Add(c.withTypingIndicator(kind.Titles[i], c.createSection(kind.Titles[i], entry, kind.Prompts[i], c.validationMarker(kind.Titles[i]), c.commentButton(kind.Titles[i]))))
+	}
 
-func NewHoverableRect(tooltip string) *HoverableRect {
-	rect := &HoverableRect{
-		tooltip: tooltip,
+	// A Value Proposition Canvas names the customer segment it is for
+	var top fyne.CanvasObject
+	if kind.ID == canvasTypeValue {
+		top = c.createSegmentBar()
 	}
-	rect.FillColor = color.Transparent
-	return rect
-}
 
-func (h *HoverableRect) MouseIn(e *desktop.MouseEvent) {
-	if h.popup == nil {
-		tooltipLabel := widget.NewLabel(h.tooltip)
-		canvas := fyne.CurrentApp().Driver().AllWindows()[0].Canvas()
-		h.popup = widget.NewPopUp(tooltipLabel, canvas)
+	// Custom sections get an extra row below the standard canvas
+	if len(c.customBlocks) > 0 {
+		return container.NewBorder(top, c.createCustomRow(), nil, nil, grid)
 	}
-	h.popup.ShowAtPosition(e.Position)
+	if top != nil {
+		return container.NewBorder(top, nil, nil, nil, grid)
+	}
+
+	return grid
 }
 
-func (h *HoverableRect) MouseOut() {
-	if h.popup != nil {
-		h.popup.Hide()
+func (c *Canvas) createSection(title string, entry *widget.Entry, tooltip string, actions ...fyne.CanvasObject) *fyne.Container {
+	// The prompt shows when hovering the header, clicking it starts editing
+	// and right-clicking it opens the section menu
+	header := newHintArea(c.tooltips, tooltip, widget.NewLabel(title), func() {
+		c.window.Canvas().Focus(entry)
+	})
+	header.onSecondaryTapped = func(e *fyne.PointEvent) {
+		c.showSectionMenu(title, entry, e.AbsolutePosition)
+	}
+	header.detail = func() string { return c.sectionDetail(title) }
+	c.setSectionLocked(title, entry, c.lockedSections[title])
+	var label fyne.CanvasObject = header
+	if len(actions) > 0 {
+		label = container.NewBorder(nil, nil, nil, container.NewHBox(actions...), label)
 	}
-}
 
-func (h *HoverableRect) MouseMoved(*desktop.MouseEvent) {
+	return c.heatCell(title, container.NewBorder(
+		label, nil, nil, nil,
+		container.NewPadded(c.autoFit(entry)),
+	))
 }
 
-func createSection(title string, entry *widget.Entry, tooltip string) *fyne.Container {
-	label := widget.NewLabel(title)
+func (c *Canvas) createStatusBar() *fyne.Container {
+	c.healthButton = newHintButton(c.tooltips, "Canvas health score, click for the breakdown", "", nil, func() {
+		c.showHealthBreakdown()
+	})
+	c.healthButton.Importance = widget.LowImportance
+	c.refreshHealth()
+
+	c.stalenessButton = newHintButton(c.tooltips, "Sections that have not been edited for a while", "", nil, func() {
+		c.showStalenessDigest()
+	})
+	c.stalenessButton.Importance = widget.WarningImportance
+	c.refreshStaleness()
 
-	// Create a container for the entry
-	entryContainer := container.NewStack(entry)
+	c.mentionsButton = newHintButton(c.tooltips, "Comments mentioning you", "", nil, func() {
+		c.showMentions()
+	})
+	c.refreshMentions()
 
-	// Add hoverable area
-	hoverArea := NewHoverableRect(tooltip)
-	hoverArea.Resize(entry.Size())
-	entryContainer.Add(hoverArea)
+	c.validationButton = newHintButton(c.tooltips, "Failing validation rules by severity, click for the list", "", nil, c.validateCanvas)
+	c.refreshValidation()
 
-	return container.NewBorder(
-		label, nil, nil, nil,
-		container.NewPadded(entryContainer),
-	)
-}
+	c.lastEditLabel = widget.NewLabel("")
+	c.refreshLastEdit()
 
-func (c *Canvas) createStatusBar() *fyne.Container {
 	return container.NewHBox(
 		widget.NewLabel("Status: Ready"),
-		c.progressBar,
+		widget.NewLabel("Layer:"),
+		c.createLayerSelect(),
+		newHintArea(c.tooltips, "Share of sections with content", c.progressBar, nil),
+		c.healthButton,
+		c.validationButton,
+		c.mentionsButton,
+		c.stalenessButton,
+		c.lastEditLabel,
 	)
 }
 
@@ -324,11 +594,10 @@ func (c *Canvas) showSettings() {
 		for _, option := range themeOptions {
 			if option.Name == selected {
 				c.currentTheme = option.Value
-				myApp := fyne.CurrentApp()
 				if c.currentTheme == "professional" {
-					myApp.Settings().SetTheme(theme.DarkTheme())
+					c.setTheme(theme.DarkTheme())
 				} else {
-					myApp.Settings().SetTheme(theme.LightTheme())
+					c.setTheme(theme.LightTheme())
 				}
 				break
 			}
@@ -340,7 +609,79 @@ func (c *Canvas) showSettings() {
 	checkFormItem := widget.NewFormItem("Auto-save", autoSaveCheck)
 	themeFormItem := widget.NewFormItem("Theme", themeSelect)
 
-	itemList := []*widget.FormItem{checkFormItem, themeFormItem}
+	fontFormItem := widget.NewFormItem("PDF font", c.pdfFontSetting())
+	pageFormItem := widget.NewFormItem("PDF page", c.pdfPageSetting())
+	autoFitFormItem := widget.NewFormItem("Auto-fit text", c.autoFitSetting())
+	dateFormItem := widget.NewFormItem("Dates", c.dateSetting())
+	accessibilityFormItem := widget.NewFormItem("Accessibility", c.accessibilitySetting())
+	identityFormItem := widget.NewFormItem("Your profile", widget.NewButton("Edit...", func() {
+		c.showIdentitySettings()
+	}))
+	brandingFormItem := widget.NewFormItem("PDF branding", widget.NewButton("Edit...", func() {
+		c.showBrandingSettings()
+	}))
+
+	coloredCheck := widget.NewCheck("Colored sections matching the theme", func(checked bool) {
+		c.prefs.SetBool(prefPDFColored, checked)
+	})
+	coloredCheck.SetChecked(c.prefs.Bool(prefPDFColored))
+	coloredFormItem := widget.NewFormItem("PDF colors", coloredCheck)
+
+	commentsCheck := widget.NewCheck("Append comments annex", func(checked bool) {
+		c.prefs.SetBool(prefPDFComments, checked)
+	})
+	commentsCheck.SetChecked(c.prefs.Bool(prefPDFComments))
+	commentsFormItem := widget.NewFormItem("PDF comments", commentsCheck)
+
+	wordCloudCheck := widget.NewCheck("Add word cloud page", func(checked bool) {
+		c.prefs.SetBool(prefPDFWordCloud, checked)
+	})
+	wordCloudCheck.SetChecked(c.prefs.Bool(prefPDFWordCloud))
+	wordCloudFormItem := widget.NewFormItem("PDF word cloud", wordCloudCheck)
+
+	notesCheck := widget.NewCheck("Include presenter notes (internal)", func(checked bool) {
+		c.prefs.SetBool(prefPDFPresenterNotes, checked)
+	})
+	notesCheck.SetChecked(c.prefs.Bool(prefPDFPresenterNotes))
+	notesFormItem := widget.NewFormItem("PDF notes", notesCheck)
+
+	statsCheck := widget.NewCheck("Add statistics footer to PDF and HTML", func(checked bool) {
+		c.prefs.SetBool(prefExportStats, checked)
+	})
+	statsCheck.SetChecked(c.prefs.Bool(prefExportStats))
+	statsFormItem := widget.NewFormItem("Export stats", statsCheck)
+
+	canvasTypeFormItem := widget.NewFormItem("Canvas type", c.createCanvasTypeSelect())
+
+	snapshotFormItem := widget.NewFormItem("Auto-snapshot", c.snapshotSetting())
+	autoSaveFormItem := widget.NewFormItem("Auto-save threshold", c.autoSaveSetting())
+	retentionFormItem := widget.NewFormItem("Version retention", c.retentionSetting())
+	chainFormItem := widget.NewFormItem("Version integrity", c.hashChainSetting())
+
+	wordLimitsFormItem := widget.NewFormItem("Word limits", widget.NewButton("Per Section...", func() {
+		c.showWordLimits()
+	}))
+	stalenessFormItem := widget.NewFormItem("Staleness", widget.NewButton("Thresholds...", func() {
+		c.showStalenessSettings()
+	}))
+
+	gitFormItem := widget.NewFormItem("Git storage", c.gitSetting())
+
+	mirrorFormItem := widget.NewFormItem("Mirror backups", widget.NewButton("Configure...", func() {
+		c.showMirrorSettings()
+	}))
+
+	remoteFormItem := widget.NewFormItem("Remote storage", widget.NewButton("Configure...", func() {
+		c.showRemoteSettings()
+	}))
+
+	dropboxFormItem := widget.NewFormItem("Dropbox sync", widget.NewButton("Configure...", func() {
+		c.showDropboxSettings()
+	}))
+
+	profileFormItem := widget.NewFormItem("Settings profile", c.profileSetting())
+
+	itemList := []*widget.FormItem{canvasTypeFormItem, checkFormItem, themeFormItem, accessibilityFormItem, autoFitFormItem, dateFormItem, fontFormItem, pageFormItem, coloredFormItem, commentsFormItem, wordCloudFormItem, notesFormItem, statsFormItem, identityFormItem, brandingFormItem, wordLimitsFormItem, stalenessFormItem, autoSaveFormItem, snapshotFormItem, retentionFormItem, chainFormItem, gitFormItem, mirrorFormItem, remoteFormItem, dropboxFormItem, profileFormItem}
 
 	if c.currentTheme == "professional" {
 		currentThemeLabel.SetText("Current Theme: Professional (Dark)")
@@ -357,11 +698,12 @@ func (c *Canvas) showSettings() {
 }
 
 func (c *Canvas) autoSaveRoutine() {
-	ticker := time.NewTicker(5 * time.Minute)
+	ticker := time.NewTicker(time.Minute)
 	defer ticker.Stop()
 	for range ticker.C {
-		if c.autoSave && time.Since(c.lastSaved) >= 5*time.Minute {
-			c.saveCurrentVersion()
+		if c.autoSave && time.Since(c.lastSaved) >= c.autoSaveInterval() {
+			c.autoSaveVersion()
+			c.dropboxAutoSave()
 		}
 	}
 }
@@ -369,18 +711,35 @@ func (c *Canvas) autoSaveRoutine() {
 func (c *Canvas) saveCurrentVersion() {
 	version := Version{
 		ID:        uuid.New().String(),
-		Timestamp: time.Now(),
+		Timestamp: nowUTC(),
 		Data:      c.getCurrentData(),
+		Branch:    c.branch,
+	}
+	identity := c.identity()
+	version.Author, version.AuthorInitials, version.AuthorColor = identity.Name, identity.Initials, identity.Color
+	if c.prefs.Bool(prefHashChain) {
+		chainVersion(&version, c.versions)
 	}
-	c.versions = append(c.versions, version)
+	c.versions = pruneVersions(append(c.versions, version), time.Now(), c.retentionPolicy())
 	c.lastSaved = time.Now()
+	c.resetSnapshotBase()
+	c.refreshHeatmap()
 
 	// Update progress
 	c.updateProgress()
 }
 
 func (c *Canvas) getCurrentData() CanvasData {
+	c.collabMu.Lock()
+	edited := make(map[string]time.Time, len(c.sectionEdited))
+	for section, t := range c.sectionEdited {
+		edited[section] = t
+	}
+	c.collabMu.Unlock()
+
 	return CanvasData{
+		FormatVersion:    canvasFormatVersion,
+		CanvasType:       c.canvasTypeID,
 		KeyPartners:      c.keyPartners.Text,
 		KeyActivities:    c.keyActivities.Text,
 		KeyResources:     c.keyResources.Text,
@@ -390,115 +749,234 @@ func (c *Canvas) getCurrentData() CanvasData {
 		CustomerSegments: c.customerSegments.Text,
 		CostStructure:    c.costStructure.Text,
 		RevenueStreams:   c.revenueStreams.Text,
+		CustomSections:   c.customData(),
+		Environmental:    c.layerText(layerEnvironmental),
+		Social:           c.layerText(layerSocial),
+		SectionEdited:    edited,
+		SectionEditors:   c.sectionEditorsData(),
+		PresenterNotes:   c.presenterNotesData(),
+		DateDisplay:      dateDisplay(c.prefs),
+		SegmentLink:      c.segmentLink,
+		Links:            c.itemLinks,
+		Assumptions:      c.assumptions,
+		Validation:       c.validationSummary(c.lastValidation),
+		EnvironmentMap:   c.environmentMap,
+		Settings:         c.canvasSettings,
 	}
 }
 
-func (c *Canvas) updateProgress() {
-	// Calculate progress based on filled sections
-	totalSections := 9.0
-	filledSections := 0.0
+// canvasFileData is the canvas as saved to files, with the edit history of
+// its sections. getCurrentData leaves the history out, as it runs on every
+// keystroke. Tombstones of deleted characters are kept, since a copy made
+// before the delete may still be merged with the file.
+func (c *Canvas) canvasFileData() CanvasData {
+	data := c.getCurrentData()
+	data.CRDT = c.sectionCRDTData()
+	data.VersionChain = chainedVersions(c.versions)
+	return data
+}
 
-	if len(c.keyPartners.Text) > 0 {
-		filledSections++
-		c.updateSectionColor(c.keyPartners, true)
-	} else {
-		c.updateSectionColor(c.keyPartners, false)
-	}
-	if len(c.keyActivities.Text) > 0 {
-		filledSections++
-		c.updateSectionColor(c.keyActivities, true)
-	} else {
-		c.updateSectionColor(c.keyActivities, false)
-	}
-	if len(c.keyResources.Text) > 0 {
-		filledSections++
-		c.updateSectionColor(c.keyResources, true)
-	} else {
-		c.updateSectionColor(c.keyResources, false)
-	}
-	if len(c.valueProposition.Text) > 0 {
-		filledSections++
-		c.updateSectionColor(c.valueProposition, true)
-	} else {
-		c.updateSectionColor(c.valueProposition, false)
+// setCurrentData replaces the canvas content, rebuilding custom sections
+// when the set of custom blocks differs
+func (c *Canvas) setCurrentData(data CanvasData) {
+	// Replacing the whole canvas is not an edit to snapshot
+	c.settingData = true
+	defer func() {
+		c.settingData = false
+		c.resetSnapshotBase()
+	}()
+
+	if kind := data.canvasType(); kind.ID != c.canvasTypeID {
+		c.applyCanvasType(kind.ID)
 	}
-	if len(c.customerRel.Text) > 0 {
-		filledSections++
-		c.updateSectionColor(c.customerRel, true)
-	} else {
-		c.updateSectionColor(c.customerRel, false)
+	c.keyPartners.SetText(data.KeyPartners)
+	c.keyActivities.SetText(data.KeyActivities)
+	c.keyResources.SetText(data.KeyResources)
+	c.valueProposition.SetText(data.ValueProposition)
+	c.customerRel.SetText(data.CustomerRel)
+	c.channels.SetText(data.Channels)
+	c.customerSegments.SetText(data.CustomerSegments)
+	c.costStructure.SetText(data.CostStructure)
+	c.revenueStreams.SetText(data.RevenueStreams)
+	c.setCustomSections(data.CustomSections)
+	c.setLayerText(data)
+	c.setPresenterNotes(data.PresenterNotes)
+	if c.canvasSettings != data.Settings {
+		c.applyCanvasSettings(data.Settings)
 	}
-	if len(c.channels.Text) > 0 {
-		filledSections++
-		c.updateSectionColor(c.channels, true)
-	} else {
-		c.updateSectionColor(c.channels, false)
+	c.itemLinks = data.Links
+	c.assumptions = data.Assumptions
+	c.environmentMap = data.EnvironmentMap
+	if !sameSegmentLink(c.segmentLink, data.SegmentLink) {
+		c.segmentLink = data.SegmentLink
+		c.refreshLayout()
 	}
-	if len(c.customerSegments.Text) > 0 {
-		filledSections++
-		c.updateSectionColor(c.customerSegments, true)
-	} else {
-		c.updateSectionColor(c.customerSegments, false)
-	}
-	if len(c.costStructure.Text) > 0 {
-		filledSections++
-		c.updateSectionColor(c.costStructure, true)
-	} else {
-		c.updateSectionColor(c.costStructure, false)
+}
+
+func (c *Canvas) updateProgress() {
+	// Calculate progress based on filled sections of the canvas type
+	kind := findCanvasType(c.canvasTypeID)
+	totalSections := float64(len(kind.Titles) + len(c.customBlocks))
+	filledSections := 0.0
+
+	for _, entry := range c.standardEntries()[:len(kind.Titles)] {
+		if len(entry.Text) > 0 {
+			filledSections++
+		}
 	}
-	if len(c.revenueStreams.Text) > 0 {
-		filledSections++
-		c.updateSectionColor(c.revenueStreams, true)
-	} else {
-		c.updateSectionColor(c.revenueStreams, false)
+
+	for _, block := range c.customBlocks {
+		if len(block.entry.Text) > 0 {
+			filledSections++
+		}
 	}
 
 	c.progressBar.SetValue(filledSections / totalSections)
+	c.refreshValidation()
+	c.refreshHealth()
 }
 
 func (c *Canvas) validateCanvas() {
 	results := c.validator.Validate(c)
+	sortBySeverity(results)
 	if len(results) > 0 {
 		var message string
 		for _, result := range results {
-			message += fmt.Sprintf("• %s: %s\n", result.Section, result.Message)
+			message += fmt.Sprintf("• %s: %s (%s)", result.Section, result.Message, result.Severity)
+			if result.Pack != "" {
+				message += " [" + result.Pack + "]"
+			}
+			message += "\n"
 		}
-		dialog.ShowInformation("Validation Results", message, c.window)
+		dialog.ShowCustomConfirm("Validation Results", "Create Task List", "Close", widget.NewLabel(message),
+			func(create bool) {
+				if create {
+					c.exportTaskList()
+				}
+			}, c.window)
 	} else {
 		dialog.ShowInformation("Validation Results", "All sections look good!", c.window)
 	}
 }
 
 func (c *Canvas) showVersionHistory() {
-	if len(c.versions) == 0 {
-		dialog.ShowInformation("Version History", "No previous versions found", c.window)
-		return
-	}
-
-	var items []string
-	for _, version := range c.versions {
-		items = append(items, version.Timestamp.Format("2006-01-02 15:04:05"))
+	// Versions matching the search, by index into c.versions
+	var shown []int
+	search := widget.NewEntry()
+	search.SetPlaceHolder("Search by name, tag or date")
+	allBranches := widget.NewCheck("All branches", nil)
+	filter := func() {
+		shown = nil
+		for i, version := range c.versions {
+			if !allBranches.Checked && version.branchName() != c.currentBranch() {
+				continue
+			}
+			if version.matches(search.Text) {
+				shown = append(shown, i)
+			}
+		}
 	}
+	filter()
 
 	list := widget.NewList(
-		func() int { return len(items) },
-		func() fyne.CanvasObject { return widget.NewLabel("Template") },
+		func() int { return len(shown) },
+		func() fyne.CanvasObject {
+			compareButton := widget.NewButtonWithIcon("Compare", theme.VisibilityIcon(), nil)
+			exportButton := widget.NewButtonWithIcon("Export PDF", theme.DocumentCreateIcon(), nil)
+			return container.NewBorder(nil, nil, nil, container.NewHBox(compareButton, exportButton), widget.NewLabel("Template"))
+		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
-			obj.(*widget.Label).SetText(items[id])
+			version := c.versions[shown[id]]
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(version.label())
+			buttons := row.Objects[1].(*fyne.Container).Objects
+			buttons[0].(*widget.Button).OnTapped = func() {
+				c.showVersionCompare(version)
+			}
+			buttons[1].(*widget.Button).OnTapped = func() {
+				c.exportVersionToPDF(version)
+			}
 		},
 	)
 
-	// Add restore button
+	refresh := func() {
+		filter()
+		list.UnselectAll()
+		list.Refresh()
+	}
+
+	// Switch between branches, saving the current state first
+	branchSelect := widget.NewSelect(c.branches(), nil)
+	branchSelect.SetSelected(c.currentBranch())
+	branchSelect.OnChanged = func(branch string) {
+		c.switchBranch(branch)
+		refresh()
+	}
+
+	// Restore over the current branch or into a new one
 	list.OnSelected = func(id widget.ListItemID) {
-		dialog.ShowConfirm("Restore Version", "Do you want to restore this version?",
-			func(restore bool) {
-				if restore {
-					c.restoreVersion(c.versions[id])
-				}
-			}, c.window)
+		version := c.versions[shown[id]]
+		var restore *dialog.CustomDialog
+		restore = dialog.NewCustomWithoutButtons("Restore Version",
+			widget.NewLabel("Restore this version over the current branch, or into a new branch\nto explore an alternative next to it?"), c.window)
+		restore.SetButtons([]fyne.CanvasObject{
+			widget.NewButton("Cancel", func() {
+				restore.Hide()
+				list.UnselectAll()
+			}),
+			widget.NewButton("Restore Section...", func() {
+				restore.Hide()
+				c.showRestoreSection(version)
+			}),
+			widget.NewButton("Restore as Branch...", func() {
+				restore.Hide()
+				c.restoreAsBranch(version, func() {
+					branchSelect.SetOptions(c.branches())
+					branchSelect.SetSelected(c.currentBranch())
+					refresh()
+				})
+			}),
+			widget.NewButtonWithIcon("Restore...", theme.HistoryIcon(), func() {
+				restore.Hide()
+				c.showVersionCompare(version)
+			}),
+		})
+		restore.Show()
 	}
 
-	dialog.ShowCustom("Version History", "Close", list, c.window)
+	search.OnChanged = func(string) { refresh() }
+	allBranches.OnChanged = func(bool) { refresh() }
+
+	// Save a named version of the current state
+	saveVersion := widget.NewButtonWithIcon("Save Version...", theme.DocumentSaveIcon(), func() {
+		c.showSaveVersion(func() {
+			filter()
+			list.Refresh()
+		})
+	})
+
+	// Export every version at once for archiving
+	exportAll := widget.NewButtonWithIcon("Export History...", theme.DownloadIcon(), func() {
+		c.exportHistory()
+	})
+
+	// Animate the listed versions, for retrospectives and demo days
+	exportEvolution := widget.NewButtonWithIcon("Export Evolution...", theme.MediaVideoIcon(), func() {
+		versions := make([]Version, 0, len(shown))
+		for _, i := range shown {
+			versions = append(versions, c.versions[i])
+		}
+		c.exportEvolution(versions)
+	})
+
+	sideBySide := widget.NewButtonWithIcon("Side by Side...", theme.ViewRestoreIcon(), func() {
+		c.showSideBySide()
+	})
+
+	top := container.NewBorder(nil, nil, container.NewHBox(widget.NewLabel("Branch:"), branchSelect), allBranches, search)
+	history := dialog.NewCustom("Version History", "Close", container.NewBorder(top, container.NewHBox(saveVersion, sideBySide, exportAll, exportEvolution), nil, nil, list), c.window)
+	history.Resize(fyne.NewSize(700, 450))
+	history.Show()
 }
 
 func (c *Canvas) restoreVersion(version Version) {
@@ -506,15 +984,7 @@ func (c *Canvas) restoreVersion(version Version) {
 	c.undoStack = append(c.undoStack, c.getCurrentData())
 
 	// Restore the selected version
-	c.keyPartners.SetText(version.Data.KeyPartners)
-	c.keyActivities.SetText(version.Data.KeyActivities)
-	c.keyResources.SetText(version.Data.KeyResources)
-	c.valueProposition.SetText(version.Data.ValueProposition)
-	c.customerRel.SetText(version.Data.CustomerRel)
-	c.channels.SetText(version.Data.Channels)
-	c.customerSegments.SetText(version.Data.CustomerSegments)
-	c.costStructure.SetText(version.Data.CostStructure)
-	c.revenueStreams.SetText(version.Data.RevenueStreams)
+	c.setCurrentData(version.Data)
 
 	// Update progress and colors
 	c.updateProgress()
@@ -522,72 +992,160 @@ func (c *Canvas) restoreVersion(version Version) {
 	dialog.ShowInformation("Success", "Version restored successfully", c.window)
 }
 
+// restoreSection restores a single section from a version, leaving the
+// rest of the canvas untouched
+func (c *Canvas) restoreSection(version Version, title string) {
+	data := c.getCurrentData()
+	restored := false
+	for _, section := range version.Data.sections() {
+		if section.Title == title {
+			data.setSectionText(title, section.Text)
+			restored = true
+		}
+	}
+	if !restored {
+		return
+	}
+
+	// A custom section removed since the version is added back
+	if !slices.ContainsFunc(data.sections(), func(s sectionContent) bool { return s.Title == title }) {
+		for _, custom := range version.Data.CustomSections {
+			if custom.Title == title {
+				data.CustomSections = append(slices.Clone(data.CustomSections), custom)
+			}
+		}
+	}
+
+	c.undoStack = append(c.undoStack, c.getCurrentData())
+	c.setCurrentData(data)
+	c.updateProgress()
+
+	dialog.ShowInformation("Success", title+" restored successfully", c.window)
+}
+
 // BusinessValidator handles canvas validation
 type BusinessValidator struct {
 	rules []ValidationRule
 }
 
 type ValidationRule struct {
-	Section string
-	Check   func(*Canvas) bool
-	Message string
+	Section  string
+	Check    func(*Canvas) bool
+	Message  string
+	Pack     string // rule pack the rule comes from, empty for built-in rules
+	Severity string // error, warning or info, warning when empty
+	minWords int    // default minimum of built-in word count rules
 }
 
 type ValidationResult struct {
-	Section string
-	Message string
+	Section  string
+	Message  string
+	Pack     string
+	Severity string
 }
 
 func NewBusinessValidator() *BusinessValidator {
 	return &BusinessValidator{
 		rules: []ValidationRule{
+			wordRule("Value Proposition", 15, "Consider adding more detail about your value proposition", func(c *Canvas) string { return c.valueProposition.Text }),
+			wordRule("Customer Segments", 8, "Customer segments need more specific details", func(c *Canvas) string { return c.customerSegments.Text }),
+			wordRule("Key Activities", 8, "Add more details about your key activities", func(c *Canvas) string { return c.keyActivities.Text }),
+			wordRule("Cost Structure", 8, "Elaborate on your cost structure", func(c *Canvas) string { return c.costStructure.Text }),
+			wordRule("Revenue Streams", 8, "Provide more information about revenue streams", func(c *Canvas) string { return c.revenueStreams.Text }),
+		},
+	}
+}
+
+// NewMissionValidator returns the rules for the Mission Model Canvas
+func NewMissionValidator() *BusinessValidator {
+	return &BusinessValidator{
+		rules: []ValidationRule{
+			wordRule("Value Proposition", 15, "Describe the value you create for beneficiaries in more detail", func(c *Canvas) string { return c.valueProposition.Text }),
+			wordRule("Beneficiaries", 8, "Be specific about who benefits from the mission", func(c *Canvas) string { return c.customerSegments.Text }),
+			wordRule("Buy-In & Support", 8, "Identify whose buy-in and support the mission needs", func(c *Canvas) string { return c.customerRel.Text }),
+			wordRule("Mission Budget / Cost", 8, "Elaborate on how the mission is funded", func(c *Canvas) string { return c.costStructure.Text }),
+			wordRule("Mission Achievement", 8, "Define the impact factors that show mission achievement", func(c *Canvas) string { return c.revenueStreams.Text }),
+		},
+	}
+}
+
+// NewTeamValidator returns the rules for the Team Canvas
+func NewTeamValidator() *BusinessValidator {
+	return &BusinessValidator{
+		rules: []ValidationRule{
+			wordRule("People & Roles", 8, "List every team member and their role", func(c *Canvas) string { return c.keyPartners.Text }),
+			wordRule("Common Goals", 8, "Describe what the team wants to achieve together", func(c *Canvas) string { return c.keyActivities.Text }),
+			wordRule("Purpose", 8, "Explain why the team exists in more detail", func(c *Canvas) string { return c.valueProposition.Text }),
+			wordRule("Rules & Activities", 8, "Agree on the rules and rituals the team follows", func(c *Canvas) string { return c.customerSegments.Text }),
+		},
+	}
+}
+
+// NewCultureValidator returns the rules for the Culture Map
+func NewCultureValidator() *BusinessValidator {
+	return &BusinessValidator{
+		rules: []ValidationRule{
+			wordRule("Outcomes", 8, "Describe the outcomes the culture produces", func(c *Canvas) string { return c.keyPartners.Text }),
+			wordRule("Behaviors", 15, "Describe the behaviors behind the outcomes in more detail", func(c *Canvas) string { return c.keyActivities.Text }),
+			wordRule("Enablers", 8, "Identify what enables the desired behaviors", func(c *Canvas) string { return c.keyResources.Text }),
+			wordRule("Blockers", 8, "Identify what blocks the desired behaviors", func(c *Canvas) string { return c.valueProposition.Text }),
+		},
+	}
+}
+
+// NewValueValidator returns the rules for the Value Proposition Canvas
+func NewValueValidator() *BusinessValidator {
+	return &BusinessValidator{
+		rules: []ValidationRule{
+			wordRule("Customer Jobs", 8, "Describe the jobs the customer is trying to get done", func(c *Canvas) string { return c.keyPartners.Text }),
+			wordRule("Pains", 8, "Identify the customer's pains", func(c *Canvas) string { return c.keyActivities.Text }),
+			wordRule("Gains", 8, "Identify the gains the customer wants", func(c *Canvas) string { return c.keyResources.Text }),
+			wordRule("Products & Services", 1, "List the products and services you offer", func(c *Canvas) string { return c.valueProposition.Text }),
 			{
-				Section: "Value Proposition",
-				Check: func(c *Canvas) bool {
-					return len(c.valueProposition.Text) >= 100
-				},
-				Message: "Consider adding more detail about your value proposition",
-			},
-			{
-				Section: "Customer Segments",
-				Check: func(c *Canvas) bool {
-					return len(c.customerSegments.Text) >= 50
-				},
-				Message: "Customer segments need more specific details",
-			},
-			{
-				Section: "Key Activities",
-				Check: func(c *Canvas) bool {
-					return len(c.keyActivities.Text) >= 50
-				},
-				Message: "Add more details about your key activities",
-			},
-			{
-				Section: "Cost Structure",
+				Section: "Pain Relievers",
 				Check: func(c *Canvas) bool {
-					return len(c.costStructure.Text) >= 50
+					return len(c.keyActivities.Text) == 0 || len(c.customerRel.Text) > 0
 				},
-				Message: "Elaborate on your cost structure",
+				Message: "Explain how you relieve the pains you identified",
 			},
 			{
-				Section: "Revenue Streams",
+				Section: "Gain Creators",
 				Check: func(c *Canvas) bool {
-					return len(c.revenueStreams.Text) >= 50
+					return len(c.keyResources.Text) == 0 || len(c.channels.Text) > 0
 				},
-				Message: "Provide more information about revenue streams",
+				Message: "Explain how you create the gains you identified",
 			},
 		},
 	}
 }
 
+// NewEnvironmentValidator returns the rules for the Business Model
+// Environment
+func NewEnvironmentValidator() *BusinessValidator {
+	return &BusinessValidator{
+		rules: []ValidationRule{
+			wordRule("Market Forces", 8, "Describe the market the business model competes in", func(c *Canvas) string { return c.keyPartners.Text }),
+			wordRule("Industry Forces", 8, "Identify competitors, new entrants and substitutes", func(c *Canvas) string { return c.keyActivities.Text }),
+			wordRule("Key Trends", 1, "List the trends that could change the business model", func(c *Canvas) string { return c.keyResources.Text }),
+			wordRule("Macroeconomic Forces", 1, "Describe the economic conditions the business model depends on", func(c *Canvas) string { return c.valueProposition.Text }),
+		},
+	}
+}
+
 func (v *BusinessValidator) Validate(canvas *Canvas) []ValidationResult {
 	var results []ValidationResult
 
 	for _, rule := range v.rules {
 		if !rule.Check(canvas) {
+			severity := rule.Severity
+			if severity == "" {
+				severity = severityWarning
+			}
 			results = append(results, ValidationResult{
-				Section: rule.Section,
-				Message: rule.Message,
+				Section:  rule.Section,
+				Message:  rule.Message,
+				Pack:     rule.Pack,
+				Severity: severity,
 			})
 		}
 	}
@@ -596,35 +1154,8 @@ func (v *BusinessValidator) Validate(canvas *Canvas) []ValidationResult {
 }
 
 func (c *Canvas) setupKeyboardShortcuts() {
-	c.window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyS, Modifier: fyne.KeyModifierControl},
-		func(shortcut fyne.Shortcut) {
-			c.saveCanvas()
-		},
-	)
-
-	c.window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyO, Modifier: fyne.KeyModifierControl},
-		func(shortcut fyne.Shortcut) {
-			c.loadCanvas()
-		},
-	)
-
-	c.window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierControl},
-		func(shortcut fyne.Shortcut) {
-			c.undo()
-		},
-	)
-
-	c.window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyY, Modifier: fyne.KeyModifierControl},
-		func(shortcut fyne.Shortcut) {
-			c.redo()
-		},
-	)
-
-	c.window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyP, Modifier: fyne.KeyModifierControl},
-		func(shortcut fyne.Shortcut) {
-			c.exportToPDF()
-		},
-	)
+	// Save, open, undo, redo and PDF export are registered with the menu
+	// bar, see setupMainMenu
 
 	// For clipboard operations
 	c.window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyC, Modifier: fyne.KeyModifierControl},
@@ -664,27 +1195,26 @@ func (c *Canvas) setupKeyboardShortcuts() {
 
 func (c *Canvas) setupDynamicValidation(entry *widget.Entry, section string) {
 	entry.OnChanged = func(s string) {
-		results := c.validator.Validate(c)
-		isValid := true
-		for _, result := range results {
-			if result.Section == section {
-				isValid = false
-				break
-			}
+		// Text from a collaboration session is attributed by applyCollab
+		// and not sent back
+		local := !c.takeRemoteText(section, s)
+		c.refreshValidation()
+		c.markSectionEdited(section, s, local)
+		c.recordEdit(section, s)
+		c.recordSectionCRDT(section, s)
+		if local {
+			c.publishTyping(section)
+			c.publishEdit(section)
 		}
-		c.updateSectionColor(entry, isValid)
+		c.refitSection(entry)
+		c.snapshotOnChange(section, s)
+		c.refreshStaleness()
+		c.refreshHealth()
+		c.refreshWordCloud()
+		c.refreshShare()
 	}
 }
 
-func (c *Canvas) updateSectionColor(entry *widget.Entry, isValid bool) {
-	if isValid {
-		entry.TextStyle = fyne.TextStyle{} // Reset to default
-	} else {
-		entry.TextStyle = fyne.TextStyle{Italic: true} // Visual indicator for invalid
-	}
-	entry.Refresh() // Refresh the widget to show changes
-}
-
 func (c *Canvas) undo() {
 	if len(c.undoStack) > 0 {
 		// Save current state to redo stack
@@ -695,15 +1225,7 @@ func (c *Canvas) undo() {
 		c.undoStack = c.undoStack[:len(c.undoStack)-1]
 
 		// Restore the state
-		c.keyPartners.SetText(lastState.KeyPartners)
-		c.keyActivities.SetText(lastState.KeyActivities)
-		c.keyResources.SetText(lastState.KeyResources)
-		c.valueProposition.SetText(lastState.ValueProposition)
-		c.customerRel.SetText(lastState.CustomerRel)
-		c.channels.SetText(lastState.Channels)
-		c.customerSegments.SetText(lastState.CustomerSegments)
-		c.costStructure.SetText(lastState.CostStructure)
-		c.revenueStreams.SetText(lastState.RevenueStreams)
+		c.setCurrentData(lastState)
 
 		// Update progress and colors
 		c.updateProgress()
@@ -720,15 +1242,7 @@ func (c *Canvas) redo() {
 		c.redoStack = c.redoStack[:len(c.redoStack)-1]
 
 		// Restore the state
-		c.keyPartners.SetText(lastState.KeyPartners)
-		c.keyActivities.SetText(lastState.KeyActivities)
-		c.keyResources.SetText(lastState.KeyResources)
-		c.valueProposition.SetText(lastState.ValueProposition)
-		c.customerRel.SetText(lastState.CustomerRel)
-		c.channels.SetText(lastState.Channels)
-		c.customerSegments.SetText(lastState.CustomerSegments)
-		c.costStructure.SetText(lastState.CostStructure)
-		c.revenueStreams.SetText(lastState.RevenueStreams)
+		c.setCurrentData(lastState)
 
 		// Update progress and colors
 		c.updateProgress()
@@ -736,6 +1250,16 @@ func (c *Canvas) redo() {
 }
 
 func (c *Canvas) saveCanvas() {
+	c.saveCanvasThen(nil)
+}
+
+// saveCanvasThen saves the canvas to a file, calling saved once it was
+// written
+func (c *Canvas) saveCanvasThen(saved func()) {
+	c.confirmOverwrite(func() { c.showSaveDialog(saved) })
+}
+
+func (c *Canvas) showSaveDialog(saved func()) {
 	dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
 		if err != nil {
 			dialog.ShowError(err, c.window)
@@ -744,26 +1268,35 @@ func (c *Canvas) saveCanvas() {
 		if writer == nil {
 			return
 		}
-		defer writer.Close()
-
-		// Save current state to undo stack
-		c.undoStack = append(c.undoStack, c.getCurrentData())
-
 		// Prepare data
-		data := c.getCurrentData()
+		data := c.canvasFileData()
 		jsonData, err := json.MarshalIndent(data, "", "    ")
 		if err != nil {
+			discardWriter(writer)
 			dialog.ShowError(err, c.window)
 			return
 		}
 
-		// Write to file
-		_, err = writer.Write(jsonData)
-		if err != nil {
+		// The app's own write is not a change made outside it
+		c.watchOpenFile(nil)
+
+		// Save current state to undo stack
+		c.undoStack = append(c.undoStack, data)
+
+		if err := writeDialogFile(writer, jsonData); err != nil {
 			dialog.ShowError(err, c.window)
 			return
 		}
-
+		c.mirrorSave(writer.URI().Name(), jsonData)
+		c.dropboxSave(writer.URI().Name(), jsonData)
+		c.gitSave(writer.URI())
+		c.markSaved()
+		c.watchOpenFile(writer.URI())
+
+		if saved != nil {
+			saved()
+			return
+		}
 		dialog.ShowInformation("Success", "Canvas saved successfully", c.window)
 	}, c.window)
 }
@@ -779,93 +1312,111 @@ func (c *Canvas) loadCanvas() {
 		}
 		defer reader.Close()
 
-		// Save current state to undo stack
-		c.undoStack = append(c.undoStack, c.getCurrentData())
-
-		// Read file contents
-		data, err := io.ReadAll(reader)
-		if err != nil {
-			dialog.ShowError(err, c.window)
-			return
-		}
-
-		// Parse JSON
-		var canvasData CanvasData
-		err = json.Unmarshal(data, &canvasData)
+		// Read and parse file contents
+		canvasData, err := readCanvasData(reader)
 		if err != nil {
 			dialog.ShowError(err, c.window)
 			return
 		}
 
-		// Update canvas fields
-		c.keyPartners.SetText(canvasData.KeyPartners)
-		c.keyActivities.SetText(canvasData.KeyActivities)
-		c.keyResources.SetText(canvasData.KeyResources)
-		c.valueProposition.SetText(canvasData.ValueProposition)
-		c.customerRel.SetText(canvasData.CustomerRel)
-		c.channels.SetText(canvasData.Channels)
-		c.customerSegments.SetText(canvasData.CustomerSegments)
-		c.costStructure.SetText(canvasData.CostStructure)
-		c.revenueStreams.SetText(canvasData.RevenueStreams)
-
-		// Update progress and colors
-		c.updateProgress()
-
-		dialog.ShowInformation("Success", "Canvas loaded successfully", c.window)
+		file := reader.URI()
+		c.openCanvasData(file.Name(), canvasData, func() {
+			c.watchOpenFile(file)
+		})
 	}, c.window)
 }
 
-func (c *Canvas) exportToPDF() {
-	pdf := gofpdf.New("L", "mm", "A3", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 16)
-
-	// Page settings
-	pageWidth := 420.0  // A3 landscape width
-	pageHeight := 297.0 // A3 landscape height
-	margin := 10.0
-
-	// Calculate section dimensions
-	topHeight := (pageHeight - 2*margin) * 0.6
-	bottomHeight := (pageHeight - 2*margin) * 0.4
-	colWidth := (pageWidth - 2*margin) / 5
-
-	// Draw borders and titles
-	pdf.SetLineWidth(0.3)
+// openCanvasData replaces the canvas with one read from a file, asking
+// first when there are unsaved changes, and calls opened once it is open
+func (c *Canvas) openCanvasData(name string, canvasData CanvasData, opened func()) {
+	c.confirmReplace("Opening "+name, canvasData, func() {
+		c.applyCanvasFile(canvasData)
+		opened()
+		dialog.ShowInformation("Success", "Canvas loaded successfully", c.window)
+	})
+}
 
-	// Top sections
-	y := margin
-	// Key Partners
-	drawSection(pdf, margin, y, colWidth, topHeight, "Key Partners", c.keyPartners.Text)
+// applyCanvasFile puts the content of a canvas file on screen
+func (c *Canvas) applyCanvasFile(canvasData CanvasData) {
+	// Save current state to undo stack
+	c.undoStack = append(c.undoStack, c.getCurrentData())
 
-	// Key Activities & Resources
-	x := margin + colWidth
-	drawSection(pdf, x, y, colWidth, topHeight/2, "Key Activities", c.keyActivities.Text)
-	drawSection(pdf, x, y+topHeight/2, colWidth, topHeight/2, "Key Resources", c.keyResources.Text)
+	// Update canvas fields
+	c.setCurrentData(canvasData)
+	c.resetSectionEdits(canvasData.SectionEdited, canvasData.SectionEditors)
+	c.resetSectionCRDT(canvasData.CRDT)
+	if len(canvasData.VersionChain) > 0 {
+		c.versions = canvasData.VersionChain
+	}
+	c.markSaved()
 
-	// Value Proposition
-	x += colWidth
-	drawSection(pdf, x, y, colWidth, topHeight, "Value Proposition", c.valueProposition.Text)
+	// Update progress and colors
+	c.updateProgress()
+	c.showWeeklyDigest()
+}
 
-	// Customer Relationships & Channels
-	x += colWidth
-	drawSection(pdf, x, y, colWidth, topHeight/2, "Customer Relationships", c.customerRel.Text)
-	drawSection(pdf, x, y+topHeight/2, colWidth, topHeight/2, "Channels", c.channels.Text)
+// readCanvasData parses a saved canvas file, migrating older formats
+func readCanvasData(reader io.Reader) (CanvasData, error) {
+	var canvasData CanvasData
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return canvasData, err
+	}
+	data, err = migrateCanvasFile(data)
+	if err != nil {
+		return canvasData, err
+	}
+	err = json.Unmarshal(data, &canvasData)
+	return canvasData, err
+}
 
-	// Customer Segments
-	x += colWidth
-	drawSection(pdf, x, y, colWidth, topHeight, "Customer Segments", c.customerSegments.Text)
+// writeCanvasPDF renders a single canvas page as a PDF
+func writeCanvasPDF(w io.Writer, opts pdfOptions, data CanvasData) error {
+	opts.canvasPage = choosePDFPage(opts, data)
+	pdf := newPDF(opts)
+	pdf.AddPage()
+	drawCanvasPage(pdf, opts, data)
+	drawLayerPages(pdf, opts, data)
+	if opts.WordCloud {
+		drawWordCloudPage(pdf, data)
+	}
+	drawCommentsAnnex(pdf, data, opts.Comments)
+	if opts.PresenterNotes {
+		drawPresenterNotesAnnex(pdf, data)
+	}
+	return pdf.Output(w)
+}
 
-	// Bottom sections
-	y = margin + topHeight
-	// Cost Structure
-	drawSection(pdf, margin, y, (pageWidth-2*margin)/2, bottomHeight, "Cost Structure", c.costStructure.Text)
+func (c *Canvas) exportToPDF() {
+	opts, err := c.pdfOptions()
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	if opts.Environment, err = loadEnvironmentMap(c.environmentMap); err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	c.savePDF(opts, c.getCurrentData(), "canvas.pdf")
+}
 
-	// Revenue Streams
-	drawSection(pdf, margin+(pageWidth-2*margin)/2, y, (pageWidth-2*margin)/2, bottomHeight, "Revenue Streams", c.revenueStreams.Text)
+// exportVersionToPDF exports a historical snapshot without touching the
+// current editing state
+func (c *Canvas) exportVersionToPDF(version Version) {
+	opts, err := c.pdfOptions()
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	if opts.Comments != nil {
+		opts.Comments = version.Comments
+	}
+	c.savePDF(opts, version.Data, "canvas-"+version.Timestamp.UTC().Format("2006-01-02-150405")+".pdf")
+}
 
+func (c *Canvas) savePDF(opts pdfOptions, data CanvasData, fileName string) {
 	// Save PDF
-	dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
 		if err != nil {
 			dialog.ShowError(err, c.window)
 			return
@@ -875,7 +1426,7 @@ func (c *Canvas) exportToPDF() {
 		}
 		defer writer.Close()
 
-		err = pdf.Output(writer)
+		err = writeCanvasPDF(writer, opts, data)
 		if err != nil {
 			dialog.ShowError(err, c.window)
 			return
@@ -883,17 +1434,73 @@ func (c *Canvas) exportToPDF() {
 
 		dialog.ShowInformation("Success", "PDF has been exported successfully", c.window)
 	}, c.window)
+	saveDialog.SetFileName(fileName)
+	saveDialog.Show()
+}
+
+// drawCanvasPage lays out the canvas sections on the current PDF page
+func drawCanvasPage(pdf *gofpdf.Fpdf, opts pdfOptions, data CanvasData) {
+	kind := data.canvasType()
+	if opts.Environment != nil && kind.ID == canvasTypeBusiness {
+		defer drawEnvironment(pdf, opts, *opts.Environment)()
+	}
+	drawCanvasGrid(pdf, opts, kind.Layout, data.sections()[:len(kind.Titles)], data.CustomSections)
+}
+
+// drawCanvasGrid draws the sections in the given layout with any custom
+// sections in an extra row
+func drawCanvasGrid(pdf *gofpdf.Fpdf, opts pdfOptions, layout canvasLayout, sections []sectionContent, custom []CustomSection) {
+	pdf.SetFont(pdfFontFamily, "B", 16)
+
+	// Draw borders and titles
+	pdf.SetLineWidth(0.3)
+	for _, section := range custom {
+		sections = append(sections, sectionContent{Title: section.Title, Text: section.Text})
+	}
+	for i, cell := range canvasGridCells(pdf, layout, len(sections)-len(custom), len(custom)) {
+		drawSection(pdf, opts, cell, sections[i].Title, sections[i].Text)
+	}
+}
+
+// canvasGridCells places the layout blocks on the page, with custom
+// sections in an extra row below
+func canvasGridCells(pdf *gofpdf.Fpdf, layout canvasLayout, blocks, custom int) []pdfCell {
+	// Page settings, the margins leave room for any branding header/footer
+	pageWidth, pageHeight := pdf.GetPageSize()
+	left, top, right, _ := pdf.GetMargins()
+	_, bottom := pdf.GetAutoPageBreak()
+	width := pageWidth - left - right
+	height := pageHeight - top - bottom
+
+	// Custom sections take an extra row
+	gridHeight := height
+	if custom > 0 {
+		gridHeight = height * 0.8
+	}
+
+	cells := layoutCells(layout, blocks, left, top, width, gridHeight)
+	for i := 0; i < custom; i++ {
+		customWidth := width / float64(custom)
+		cells = append(cells, pdfCell{X: left + float64(i)*customWidth, Y: top + gridHeight, W: customWidth, H: height - gridHeight})
+	}
+	return cells
 }
 
-func drawSection(pdf *gofpdf.Fpdf, x, y, w, h float64, title, content string) {
-	pdf.Rect(x, y, w, h, "D") // "D" means draw border only
+func drawSection(pdf *gofpdf.Fpdf, opts pdfOptions, cell pdfCell, title, content string) {
+	if opts.Palette != nil {
+		drawColoredSection(pdf, opts, cell, title, content)
+		return
+	}
+
+	pdf.Rect(cell.X, cell.Y, cell.W, cell.H, "D") // "D" means draw border only
 
 	// Draw title
-	pdf.SetFont("Arial", "B", 12)
-	pdf.Text(x+5, y+10, title)
+	pdf.SetFont(pdfFontFamily, "B", 12)
+	pdf.Text(cell.X+5, cell.Y+10, title)
 
 	// Draw content
-	pdf.SetFont("Arial", "", 10)
-	pdf.SetXY(x+5, y+15)
-	pdf.MultiCell(w-10, 5, content, "", "", false)
+	scale := opts.page().Scale
+	pdf.SetFont(pdfFontFamily, "", 10*scale)
+	pdf.SetXY(cell.X+5, cell.Y+15)
+	pdf.MultiCell(cell.W-10, 5*scale, content, "", "", false)
 }
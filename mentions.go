@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// prefMentionsSeen is when the user last opened their mentions inbox
+const prefMentionsSeen = "mentionsSeen"
+
+// mentionPattern matches a single word @mention, which may contain dots
+// and dashes but not end with them
+var mentionPattern = regexp.MustCompile(`@[\p{L}\p{N}_]+(?:[.-][\p{L}\p{N}_]+)*`)
+
+// isNameRune reports whether r can be part of a name, so a mention must not
+// be directly preceded or followed by one
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// mentionBounded reports whether the mention at text[start:end] stands on
+// its own rather than being part of an email address or a longer name
+func mentionBounded(text string, start, end int) bool {
+	if before, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isNameRune(before) {
+		return false
+	}
+	after, _ := utf8.DecodeRuneInString(text[end:])
+	return end == len(text) || !isNameRune(after)
+}
+
+// people returns everyone who commented on or saved a version of the
+// canvas, and this user, so mentions of names with spaces can be found
+func (c *Canvas) people() []string {
+	seen := make(map[string]bool)
+	var people []string
+	add := func(name string) {
+		if name != "" && !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			people = append(people, name)
+		}
+	}
+	add(c.userName())
+	for _, version := range c.versions {
+		add(version.Author)
+	}
+	for _, comment := range c.allComments() {
+		add(comment.Author)
+	}
+	return people
+}
+
+// mentionSpans finds the byte ranges of the @mentions in a text. Full
+// names of known people are matched first, longest name first, then any
+// other single word mention.
+func mentionSpans(text string, people []string) [][2]int {
+	people = slices.Clone(people)
+	sort.Slice(people, func(i, j int) bool { return len(people[i]) > len(people[j]) })
+
+	var spans [][2]int
+	taken := func(start, end int) bool {
+		for _, span := range spans {
+			if start < span[1] && end > span[0] {
+				return true
+			}
+		}
+		return false
+	}
+	lower := strings.ToLower(text)
+	for _, person := range people {
+		name := "@" + strings.ToLower(person)
+		for at := 0; ; {
+			i := strings.Index(lower[at:], name)
+			if i < 0 {
+				break
+			}
+			start, end := at+i, at+i+len(name)
+			if !taken(start, end) && mentionBounded(text, start, end) {
+				spans = append(spans, [2]int{start, end})
+			}
+			at = end
+		}
+	}
+	for _, span := range mentionPattern.FindAllStringIndex(text, -1) {
+		if !taken(span[0], span[1]) && mentionBounded(text, span[0], span[1]) {
+			spans = append(spans, [2]int{span[0], span[1]})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	return spans
+}
+
+// mentions reports whether a text mentions a person by full or first name
+func mentions(text, person string, people []string) bool {
+	first, _, _ := strings.Cut(person, " ")
+	for _, span := range mentionSpans(text, people) {
+		mentioned := text[span[0]+1 : span[1]]
+		if strings.EqualFold(mentioned, person) || strings.EqualFold(mentioned, first) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionRichText shows a comment text with its mentions highlighted
+func mentionRichText(text string, people []string) *widget.RichText {
+	var segments []widget.RichTextSegment
+	mention := widget.RichTextStyleStrong
+	mention.Inline = true
+	mention.ColorName = theme.ColorNamePrimary
+	plain := widget.RichTextStyleInline
+	at := 0
+	for _, span := range mentionSpans(text, people) {
+		if span[0] > at {
+			segments = append(segments, &widget.TextSegment{Style: plain, Text: text[at:span[0]]})
+		}
+		segments = append(segments, &widget.TextSegment{Style: mention, Text: text[span[0]:span[1]]})
+		at = span[1]
+	}
+	if at < len(text) {
+		segments = append(segments, &widget.TextSegment{Style: plain, Text: text[at:]})
+	}
+	rich := widget.NewRichText(segments...)
+	rich.Wrapping = fyne.TextWrapWord
+	return rich
+}
+
+// mentionInbox returns the comments by others that mention this user,
+// newest first
+func (c *Canvas) mentionInbox() []Comment {
+	me := c.userName()
+	people := c.people()
+	var inbox []Comment
+	for _, comment := range c.allComments() {
+		if !strings.EqualFold(comment.Author, me) && mentions(comment.Text, me, people) {
+			inbox = append(inbox, comment)
+		}
+	}
+	sort.SliceStable(inbox, func(i, j int) bool { return inbox[i].Timestamp.After(inbox[j].Timestamp) })
+	return inbox
+}
+
+// unreadMentions counts the mentions since the inbox was last opened
+func (c *Canvas) unreadMentions() int {
+	seen, _ := time.Parse(time.RFC3339, c.prefs.String(prefMentionsSeen))
+	unread := 0
+	for _, comment := range c.mentionInbox() {
+		if comment.Timestamp.After(seen) {
+			unread++
+		}
+	}
+	return unread
+}
+
+// refreshMentions updates the unread mentions in the status bar
+func (c *Canvas) refreshMentions() {
+	if c.mentionsButton == nil {
+		return
+	}
+	unread := c.unreadMentions()
+	c.mentionsButton.SetText(fmt.Sprintf("@ %d", unread))
+	if unread > 0 {
+		c.mentionsButton.Importance = widget.HighImportance
+	} else {
+		c.mentionsButton.Importance = widget.LowImportance
+	}
+	c.mentionsButton.Refresh()
+}
+
+// showMentions lists the comments mentioning this user, opening the
+// comments of a section when one is picked
+func (c *Canvas) showMentions() {
+	inbox := c.mentionInbox()
+	seen, _ := time.Parse(time.RFC3339, c.prefs.String(prefMentionsSeen))
+	c.prefs.SetString(prefMentionsSeen, time.Now().Format(time.RFC3339))
+	c.refreshMentions()
+	if len(inbox) == 0 {
+		dialog.ShowInformation("Mentions", "Nobody has mentioned you yet", c.window)
+		return
+	}
+
+	var mentionsDialog dialog.Dialog
+	people := c.people()
+	list := container.NewVBox()
+	for _, comment := range inbox {
+		author := comment.Author
+		if author == "" {
+			author = "Anonymous"
+		}
+		title := fmt.Sprintf("%s on %s", author, comment.Section)
+		if comment.Timestamp.After(seen) {
+			title = "New: " + title
+		}
+		open := widget.NewButton("Open", func() {
+			mentionsDialog.Hide()
+			c.showComments(comment.Section)
+		})
+		subtitle := formatStamp(comment.Timestamp)
+		list.Add(widget.NewCard(title, subtitle, container.NewBorder(nil, nil, nil, open, mentionRichText(comment.Text, people))))
+	}
+	mentionsDialog = dialog.NewCustom("Mentions of "+c.userName(), "Close", container.NewVScroll(list), c.window)
+	mentionsDialog.Resize(fyne.NewSize(500, 500))
+	mentionsDialog.Show()
+}
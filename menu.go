@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// menuShortcut is an accelerator using the platform's shortcut modifier,
+// with shift when asked
+func menuShortcut(key fyne.KeyName, shift bool) fyne.Shortcut {
+	modifier := fyne.KeyModifierShortcutDefault
+	if shift {
+		modifier |= fyne.KeyModifierShift
+	}
+	return &desktop.CustomShortcut{KeyName: key, Modifier: modifier}
+}
+
+// menuItem creates a menu item and registers its accelerator on the
+// window, as only some platforms trigger menu accelerators themselves
+func (c *Canvas) menuItem(label string, shortcut fyne.Shortcut, action func()) *fyne.MenuItem {
+	item := fyne.NewMenuItem(label, action)
+	if shortcut != nil {
+		item.Shortcut = shortcut
+		c.window.Canvas().AddShortcut(shortcut, func(fyne.Shortcut) {
+			action()
+		})
+	}
+	return item
+}
+
+// setupMainMenu gives every action of the app a place in the menu bar
+func (c *Canvas) setupMainMenu() {
+	separator := fyne.NewMenuItemSeparator
+
+	recentProjects := fyne.NewMenuItem("Recent Projects", nil)
+	recentProjects.ChildMenu = c.recentProjectsMenu()
+	file := fyne.NewMenu("File",
+		c.menuItem("New...", menuShortcut(fyne.KeyN, false), c.newCanvas),
+		c.menuItem("New from Template...", nil, c.newFromTemplate),
+		c.menuItem("Open...", menuShortcut(fyne.KeyO, false), c.loadCanvas),
+		c.menuItem("Save...", menuShortcut(fyne.KeyS, false), c.saveCanvas),
+		c.menuItem("Merge from File...", nil, c.mergeFromFile),
+		separator(),
+		c.menuItem("Open Project Folder...", nil, c.openProjectFolder),
+		recentProjects,
+		c.menuItem("Search Project...", menuShortcut(fyne.KeyF, true), c.showProjectSearch),
+		c.menuItem("Close Project", nil, c.closeProject),
+		separator(),
+		c.menuItem("Open from Remote...", nil, c.openFromRemote),
+		c.menuItem("Save to Remote...", nil, c.saveToRemote),
+		separator(),
+		c.menuItem("Export PDF...", menuShortcut(fyne.KeyP, false), c.exportToPDF),
+		c.menuItem("Export Excel...", nil, c.exportToXLSX),
+		c.menuItem("Export Summary...", nil, c.exportSummary),
+		c.menuItem("Export Flashcards...", nil, c.exportFlashcards),
+		c.menuItem("Export Board Pack...", nil, c.exportBoardPack),
+		c.menuItem("Export Data Room...", nil, c.exportDataRoom),
+		c.menuItem("Export Task List...", nil, c.exportTaskList),
+		c.menuItem("Export Version History...", nil, c.exportHistory),
+		c.menuItem("Import / Export...", menuShortcut(fyne.KeyE, true), c.showInterchange),
+		separator(),
+		c.menuItem("Settings...", menuShortcut(fyne.KeyComma, false), c.showSettings),
+		c.menuItem("Canvas Settings...", nil, c.showCanvasSettings),
+	)
+
+	edit := fyne.NewMenu("Edit",
+		c.menuItem("Undo", menuShortcut(fyne.KeyZ, false), c.undo),
+		c.menuItem("Redo", menuShortcut(fyne.KeyY, false), c.redo),
+		separator(),
+		c.menuItem("Copy Canvas as Text", menuShortcut(fyne.KeyC, true), c.copyAsText),
+		c.menuItem("Copy Canvas as Markdown Table", nil, c.copyAsTable),
+		c.menuItem("Edit Section in External Editor", menuShortcut(fyne.KeyX, true), c.editFocusedExternally),
+		separator(),
+		c.menuItem("Save Version...", menuShortcut(fyne.KeyS, true), func() {
+			c.showSaveVersion(nil)
+		}),
+		c.menuItem("Version History...", menuShortcut(fyne.KeyH, false), c.showVersionHistory),
+		c.menuItem("Verify Version History", nil, c.verifyHistory),
+	)
+
+	autoFit := c.menuItem("Auto-fit Text", nil, nil)
+	autoFit.Checked = c.prefs.Bool(prefAutoFit)
+	autoFit.Action = func() {
+		c.prefs.SetBool(prefAutoFit, !c.prefs.Bool(prefAutoFit))
+		for _, fit := range c.autoFits {
+			fit.fit()
+		}
+		c.setupMainMenu()
+	}
+	view := fyne.NewMenu("View",
+		c.menuItem("Toggle Theme", menuShortcut(fyne.KeyT, true), c.toggleTheme),
+		autoFit,
+		separator(),
+		c.menuItem("Present", menuShortcut(fyne.KeyF5, false), c.startPresentation),
+		c.menuItem("Compare Versions Side by Side", nil, c.showSideBySide),
+		c.menuItem("Word Cloud", nil, c.showWordCloud),
+		c.menuItem("Heatmap...", nil, c.showHeatmap),
+		separator(),
+		c.menuItem("Canvas Health...", nil, c.showHealthBreakdown),
+		c.menuItem("Stale Sections...", nil, c.showStalenessDigest),
+		c.menuItem("Mentions...", menuShortcut(fyne.KeyM, true), c.showMentions),
+		c.menuItem("Links...", nil, c.showLinks),
+	)
+
+	snippet := fyne.NewMenuItem("Snippet", nil)
+	var snippets []*fyne.MenuItem
+	for _, text := range c.prefs.StringList(prefSnippets) {
+		snippets = append(snippets, fyne.NewMenuItem(snippetLabel(text), func() {
+			if entry, ok := c.window.Canvas().Focused().(*widget.Entry); ok && !entry.Disabled() {
+				c.insertSnippet(entry, text)
+			}
+		}))
+	}
+	if len(snippets) == 0 {
+		none := fyne.NewMenuItem("No snippets yet", nil)
+		none.Disabled = true
+		snippets = append(snippets, none)
+	}
+	snippet.ChildMenu = fyne.NewMenu("", snippets...)
+	insert := fyne.NewMenu("Insert",
+		snippet,
+		c.menuItem("New Snippet...", nil, c.showNewSnippet),
+		separator(),
+		c.menuItem("Custom Sections...", nil, c.showCustomSections),
+	)
+
+	tools := fyne.NewMenu("Tools",
+		c.menuItem("Validate Canvas", menuShortcut(fyne.KeyV, true), c.validateCanvas),
+		c.menuItem("Validation Scripts...", nil, c.showScriptRules),
+		c.menuItem("Validation Rules...", nil, c.showValidationConfig),
+		c.menuItem("Rule Packs...", nil, c.showRulePacks),
+		c.menuItem("Generate OKRs...", nil, c.showOKRGenerator),
+		c.menuItem("Interview Guide...", nil, c.showInterviewGuide),
+		c.menuItem("Assumptions...", nil, c.showAssumptions),
+		c.menuItem("Value Proposition Canvas for Segment...", nil, c.newValueCanvas),
+		c.menuItem("Business Model Environment...", nil, c.showEnvironmentMap),
+		c.menuItem("Compare with Benchmarks...", nil, c.showBenchmarkComparison),
+		separator(),
+		c.menuItem("Workshop Agenda...", nil, c.showAgendaBuilder),
+		c.menuItem("Record Session...", nil, c.showRecording),
+		c.menuItem("Collaborate...", nil, c.showCollaboration),
+		c.menuItem("Share...", nil, c.showShare),
+		c.menuItem("Sync Workspace Libraries", nil, c.syncWorkspaceLibraries),
+		c.menuItem("Workspace Dashboard", nil, c.openWorkspaceDashboard),
+		separator(),
+		c.menuItem("Your Profile...", nil, c.showIdentitySettings),
+		c.menuItem("PDF Branding...", nil, c.showBrandingSettings),
+		c.menuItem("Mirror...", nil, c.showMirrorSettings),
+		c.menuItem("Remote Storage...", nil, c.showRemoteSettings),
+		c.menuItem("Dropbox Sync...", nil, c.showDropboxSettings),
+		c.menuItem("Staleness Thresholds...", nil, c.showStalenessSettings),
+		c.menuItem("Canvas Templates...", nil, c.showCanvasTemplates),
+	)
+
+	menu := fyne.NewMainMenu(file, edit, view, insert, tools)
+	menu.Items = append(menu.Items, fyne.NewMenu("Help",
+		fyne.NewMenuItem("Keyboard Shortcuts", func() {
+			c.showShortcuts(menu)
+		}),
+		fyne.NewMenuItem("About", c.showAbout),
+	))
+	c.window.SetMainMenu(menu)
+}
+
+// shortcutLabel describes an accelerator like "Ctrl+Shift+S"
+func shortcutLabel(shortcut fyne.Shortcut) string {
+	custom, ok := shortcut.(*desktop.CustomShortcut)
+	if !ok {
+		return shortcut.ShortcutName()
+	}
+	var keys []string
+	modifier := custom.Modifier
+	if modifier&fyne.KeyModifierShortcutDefault != 0 {
+		keys = append(keys, "Ctrl")
+		if fyne.KeyModifierShortcutDefault == fyne.KeyModifierSuper {
+			keys[0] = "Cmd"
+		}
+	}
+	if modifier&fyne.KeyModifierShift != 0 {
+		keys = append(keys, "Shift")
+	}
+	return strings.Join(append(keys, string(custom.KeyName)), "+")
+}
+
+// showShortcuts lists the accelerators of the menu bar
+func (c *Canvas) showShortcuts(menu *fyne.MainMenu) {
+	var lines []string
+	for _, m := range menu.Items {
+		for _, item := range m.Items {
+			if item.Shortcut != nil {
+				lines = append(lines, fmt.Sprintf("%s: %s → %s", shortcutLabel(item.Shortcut), m.Label, item.Label))
+			}
+		}
+	}
+	text := widget.NewLabel(strings.Join(lines, "\n"))
+	dialog.ShowCustom("Keyboard Shortcuts", "Close", text, c.window)
+}
+
+// showAbout shows the name and version of the app
+func (c *Canvas) showAbout() {
+	metadata := fyne.CurrentApp().Metadata()
+	name := metadata.Name
+	if name == "" {
+		name = "Business Canvas"
+	}
+	dialog.ShowInformation("About", fmt.Sprintf("%s %s", name, metadata.Version), c.window)
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Merge choices for a section that differs between two canvases. Merging
+// edits needs the edit history of both canvases.
+const (
+	mergeKeepMine    = "Keep mine"
+	mergeTakeTheirs  = "Take theirs"
+	mergeConcatenate = "Concatenate"
+	mergeEdits       = "Merge edits"
+)
+
+// sectionMerge is a section that differs between two canvases. Standard
+// blocks are matched by position, custom sections by title.
+type sectionMerge struct {
+	Title  string
+	Block  int // standard block index, -1 for a custom section
+	Mine   string
+	Theirs string
+	Merged string // both texts with the edits of each, if they can be merged
+	Choice string
+}
+
+// choices returns the merge choices for the section
+func (m sectionMerge) choices() []string {
+	choices := []string{mergeKeepMine, mergeTakeTheirs, mergeConcatenate}
+	if m.Choice == mergeEdits || m.Merged != "" {
+		choices = append(choices, mergeEdits)
+	}
+	return choices
+}
+
+// withMergedEdits merges the edit histories of the section, choosing the
+// result by default when there is one
+func (m sectionMerge) withMergedEdits(mine, theirs CanvasData) sectionMerge {
+	if text, ok := mergedText(mine.CRDT[m.Title], theirs.CRDT[m.Title]); ok {
+		m.Merged = text
+		m.Choice = mergeEdits
+	}
+	return m
+}
+
+// diffSections lists the sections whose text differs, merging the edits of
+// both by default when their histories allow it and keeping mine otherwise
+func diffSections(mine, theirs CanvasData) []sectionMerge {
+	var merges []sectionMerge
+	mineFields, theirFields := mine.standardFields(), theirs.standardFields()
+	for i, title := range mine.canvasType().Titles {
+		if *mineFields[i] != *theirFields[i] {
+			merge := sectionMerge{Title: title, Block: i, Mine: *mineFields[i], Theirs: *theirFields[i], Choice: mergeKeepMine}
+			merges = append(merges, merge.withMergedEdits(mine, theirs))
+		}
+	}
+
+	mineCustom := make(map[string]string)
+	for _, custom := range mine.CustomSections {
+		mineCustom[custom.Title] = custom.Text
+	}
+	for _, custom := range theirs.CustomSections {
+		if text, ok := mineCustom[custom.Title]; !ok || text != custom.Text {
+			merge := sectionMerge{Title: custom.Title, Block: -1, Mine: text, Theirs: custom.Text, Choice: mergeKeepMine}
+			merges = append(merges, merge.withMergedEdits(mine, theirs))
+		}
+	}
+	return merges
+}
+
+// concatenateText appends the lines of theirs that mine does not have yet
+func concatenateText(mine, theirs string) string {
+	have := make(map[string]bool)
+	for _, line := range strings.Split(mine, "\n") {
+		have[strings.TrimSpace(line)] = true
+	}
+	merged := mine
+	for _, line := range strings.Split(theirs, "\n") {
+		if strings.TrimSpace(line) == "" || have[strings.TrimSpace(line)] {
+			continue
+		}
+		if merged != "" {
+			merged += "\n"
+		}
+		merged += line
+	}
+	return merged
+}
+
+// applyMerges returns mine with the chosen text of each differing section,
+// adding custom sections only theirs has when they are taken
+func applyMerges(mine, theirs CanvasData, merges []sectionMerge) CanvasData {
+	merged := mine
+	merged.CustomSections = append([]CustomSection(nil), mine.CustomSections...)
+	fields := merged.standardFields()
+
+	for _, merge := range merges {
+		text := merge.Mine
+		switch merge.Choice {
+		case mergeTakeTheirs:
+			text = merge.Theirs
+		case mergeConcatenate:
+			text = concatenateText(merge.Mine, merge.Theirs)
+		case mergeEdits:
+			text = merge.Merged
+		}
+
+		if merge.Block >= 0 {
+			*fields[merge.Block] = text
+			continue
+		}
+		found := false
+		for i := range merged.CustomSections {
+			if merged.CustomSections[i].Title == merge.Title {
+				merged.CustomSections[i].Text = text
+				found = true
+			}
+		}
+		if !found && merge.Choice != mergeKeepMine {
+			for _, custom := range theirs.CustomSections {
+				if custom.Title == merge.Title {
+					custom.Text = text
+					merged.CustomSections = append(merged.CustomSections, custom)
+				}
+			}
+		}
+	}
+	return merged
+}
+
+// mergeFromFile loads a second canvas and lets the user choose per section
+// which text to keep
+func (c *Canvas) mergeFromFile() {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		theirs, err := readCanvasData(reader)
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		c.showMergeChooser(c.canvasFileData(), theirs, nil)
+	}, c.window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	openDialog.Show()
+}
+
+// showMergeChooser lets the user choose per section between two canvases
+// and calls merged, when not nil, once they are merged
+func (c *Canvas) showMergeChooser(mine, theirs CanvasData, merged func()) {
+	merges := diffSections(mine, theirs)
+	if len(merges) == 0 {
+		dialog.ShowInformation("Merge", "Both canvases have the same content", c.window)
+		return
+	}
+
+	rows := container.NewVBox()
+	for i := range merges {
+		merge := &merges[i]
+		mineText := widget.NewLabel("Mine:\n" + merge.Mine)
+		mineText.Wrapping = fyne.TextWrapWord
+		theirText := widget.NewLabel("Theirs:\n" + merge.Theirs)
+		theirText.Wrapping = fyne.TextWrapWord
+
+		var preview fyne.CanvasObject = container.NewGridWithColumns(2, mineText, theirText)
+		if merge.Choice == mergeEdits {
+			mergedText := widget.NewLabel("Merged:\n" + merge.Merged)
+			mergedText.Wrapping = fyne.TextWrapWord
+			preview = container.NewVBox(preview, mergedText)
+		}
+
+		choice := widget.NewRadioGroup(merge.choices(), func(selected string) {
+			merge.Choice = selected
+		})
+		choice.Horizontal = true
+		choice.Required = true
+		choice.SetSelected(merge.Choice)
+
+		rows.Add(widget.NewCard(merge.Title, "", container.NewVBox(preview, choice)))
+	}
+
+	mergeDialog := dialog.NewCustomConfirm("Merge From File", "Merge", "Cancel", container.NewVScroll(rows), func(ok bool) {
+		if !ok {
+			return
+		}
+		c.undoStack = append(c.undoStack, c.getCurrentData())
+		// Merged sections take on the edit history of both canvases
+		for _, merge := range merges {
+			if merge.Choice == mergeEdits {
+				c.mergeSectionCRDT(merge.Title, theirs.CRDT[merge.Title])
+			}
+		}
+		c.setCurrentData(applyMerges(mine, theirs, merges))
+		c.updateProgress()
+		if merged != nil {
+			merged()
+		}
+	}, c.window)
+	mergeDialog.Resize(fyne.NewSize(700, 600))
+	mergeDialog.Show()
+}
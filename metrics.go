@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// The relay and the workspace server report their health at /healthz and
+// Prometheus metrics at /metrics, so operators monitor them like any other
+// service. The metrics hold counts and timings only, no names or content.
+
+// durationBuckets are the upper bounds in seconds of the timing histograms
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// histogram counts timings into durationBuckets
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(d time.Duration) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(durationBuckets))
+	}
+	seconds := d.Seconds()
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// write prints the histogram in the text format, labels being the labels
+// of its series without braces
+func (h *histogram) write(w io.Writer, name, labels string) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+	for i, bound := range durationBuckets {
+		count := uint64(0)
+		if h.counts != nil {
+			count = h.counts[i]
+		}
+		fmt.Fprintf(w, "%s_bucket{%s%sle=\"%g\"} %d\n", name, labels, sep, bound, count)
+	}
+	fmt.Fprintf(w, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, h.count)
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s_sum%s %g\n", name, labels, h.sum)
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels, h.count)
+}
+
+// metricGauge is a value read when the metrics are scraped
+type metricGauge struct {
+	Name  string
+	Help  string
+	Value func() float64
+}
+
+// collabGauge reports the peers connected to a relay
+func collabGauge(peers func() int) metricGauge {
+	return metricGauge{
+		Name:  "business_canvas_collab_connections",
+		Help:  "Peers connected to collaboration sessions.",
+		Value: func() float64 { return float64(peers()) },
+	}
+}
+
+// requestKey groups the requests counted by the metrics
+type requestKey struct {
+	Method string
+	Route  string
+	Code   int
+}
+
+// serverMetrics collects the metrics of a server
+type serverMetrics struct {
+	mu       sync.Mutex
+	started  time.Time
+	requests map[requestKey]uint64
+	latency  map[string]*histogram
+	saves    histogram
+	exports  map[string]*histogram
+	gauges   []metricGauge
+}
+
+func newServerMetrics(gauges ...metricGauge) *serverMetrics {
+	return &serverMetrics{
+		started:  time.Now(),
+		requests: make(map[requestKey]uint64),
+		latency:  make(map[string]*histogram),
+		exports:  make(map[string]*histogram),
+		gauges:   gauges,
+	}
+}
+
+func (m *serverMetrics) observeRequest(method, route string, code int, d time.Duration, timed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestKey{method, route, code}]++
+	if !timed {
+		return
+	}
+	if m.latency[route] == nil {
+		m.latency[route] = &histogram{}
+	}
+	m.latency[route].observe(d)
+}
+
+// observeSave times a write of workspace data to disk
+func (m *serverMetrics) observeSave(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saves.observe(d)
+}
+
+// observeExport times an export in a format
+func (m *serverMetrics) observeExport(format string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.exports[format] == nil {
+		m.exports[format] = &histogram{}
+	}
+	m.exports[format].observe(d)
+}
+
+// write prints every metric in the Prometheus text format
+func (m *serverMetrics) write(w io.Writer) {
+	// Gauges read state under other locks, which may be held while
+	// observing, so they are read before taking the lock of the metrics
+	values := make([]float64, len(m.gauges))
+	for i, gauge := range m.gauges {
+		values[i] = gauge.Value()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP business_canvas_uptime_seconds Seconds since the server started.")
+	fmt.Fprintln(w, "# TYPE business_canvas_uptime_seconds gauge")
+	fmt.Fprintf(w, "business_canvas_uptime_seconds %g\n", time.Since(m.started).Seconds())
+	for i, gauge := range m.gauges {
+		fmt.Fprintf(w, "# HELP %s %s\n", gauge.Name, gauge.Help)
+		fmt.Fprintf(w, "# TYPE %s gauge\n", gauge.Name)
+		fmt.Fprintf(w, "%s %g\n", gauge.Name, values[i])
+	}
+
+	keys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Route != keys[j].Route {
+			return keys[i].Route < keys[j].Route
+		}
+		if keys[i].Method != keys[j].Method {
+			return keys[i].Method < keys[j].Method
+		}
+		return keys[i].Code < keys[j].Code
+	})
+	fmt.Fprintln(w, "# HELP business_canvas_http_requests_total Requests served, by method, route and status code.")
+	fmt.Fprintln(w, "# TYPE business_canvas_http_requests_total counter")
+	for _, key := range keys {
+		fmt.Fprintf(w, "business_canvas_http_requests_total{method=%s,route=%s,code=\"%d\"} %d\n",
+			strconv.Quote(key.Method), strconv.Quote(key.Route), key.Code, m.requests[key])
+	}
+
+	fmt.Fprintln(w, "# HELP business_canvas_http_request_duration_seconds Time taken to answer requests, by route.")
+	fmt.Fprintln(w, "# TYPE business_canvas_http_request_duration_seconds histogram")
+	for _, route := range sortedKeys(m.latency) {
+		m.latency[route].write(w, "business_canvas_http_request_duration_seconds", "route="+strconv.Quote(route))
+	}
+
+	fmt.Fprintln(w, "# HELP business_canvas_save_duration_seconds Time taken to write workspace data to disk.")
+	fmt.Fprintln(w, "# TYPE business_canvas_save_duration_seconds histogram")
+	m.saves.write(w, "business_canvas_save_duration_seconds", "")
+
+	fmt.Fprintln(w, "# HELP business_canvas_export_duration_seconds Time taken by exports, by format.")
+	fmt.Fprintln(w, "# TYPE business_canvas_export_duration_seconds histogram")
+	for _, format := range sortedKeys(m.exports) {
+		m.exports[format].write(w, "business_canvas_export_duration_seconds", "format="+strconv.Quote(format))
+	}
+}
+
+// statusRecorder remembers the status a handler answered with, passing
+// hijacking on for collaboration sessions
+type statusRecorder struct {
+	http.ResponseWriter
+	status   int
+	hijacked bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	r.hijacked, r.status = true, http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// instrument adds /healthz and /metrics to a mux and counts and times the
+// requests it serves by their route pattern. Collaboration sessions are
+// counted but not timed, as they last as long as the session.
+func (m *serverMetrics) instrument(mux *http.ServeMux, healthy func() error) http.Handler {
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		if err := healthy(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.write(w)
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		mux.ServeHTTP(recorder, r)
+		m.observeRequest(r.Method, route, recorder.status, time.Since(start), !recorder.hijacked)
+	})
+}
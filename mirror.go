@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Preference keys of mirror backups. The target is a folder URI, or an
+// http(s) WebDAV collection URL.
+const (
+	prefMirrorTarget   = "mirrorTarget"
+	prefMirrorUser     = "mirrorUser"
+	prefMirrorPassword = "mirrorPassword"
+)
+
+// mirrorSave writes a copy of a saved canvas file to the mirror target in
+// the background, alerting the user when the backup fails
+func (c *Canvas) mirrorSave(name string, content []byte) {
+	target := c.prefs.String(prefMirrorTarget)
+	if target == "" {
+		return
+	}
+	user, password := c.prefs.String(prefMirrorUser), c.prefs.String(prefMirrorPassword)
+
+	go func() {
+		var err error
+		if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+			err = webdavStore{URL: target, User: user, Password: password}.Put(name, content)
+		} else {
+			err = mirrorFolder(target, name, content)
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("mirror backup of %s failed: %w", name, err), c.window)
+		}
+	}()
+}
+
+func mirrorFolder(target, name string, content []byte) error {
+	dir, err := parseLocation(target)
+	if err != nil {
+		return err
+	}
+	return writeArchiveFile(dir, name, func(w io.Writer) error {
+		_, err := w.Write(content)
+		return err
+	})
+}
+
+// showMirrorSettings configures where every save is mirrored to
+func (c *Canvas) showMirrorSettings() {
+	targetEntry := widget.NewEntry()
+	targetEntry.SetPlaceHolder("Folder or https://webdav.example.com/backups")
+	targetEntry.SetText(c.prefs.String(prefMirrorTarget))
+	chooseFolder := widget.NewButton("Choose Folder...", func() {
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if dir != nil {
+				targetEntry.SetText(dir.String())
+			}
+		}, c.window)
+	})
+
+	userEntry := widget.NewEntry()
+	userEntry.SetText(c.prefs.String(prefMirrorUser))
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetText(c.prefs.String(prefMirrorPassword))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Mirror to", container.NewBorder(nil, nil, nil, chooseFolder, targetEntry)),
+		widget.NewFormItem("WebDAV user", userEntry),
+		widget.NewFormItem("WebDAV password", passwordEntry),
+	}
+	form := dialog.NewForm("Mirror Backups", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		c.prefs.SetString(prefMirrorTarget, strings.TrimSpace(targetEntry.Text))
+		c.prefs.SetString(prefMirrorUser, userEntry.Text)
+		c.prefs.SetString(prefMirrorPassword, passwordEntry.Text)
+	}, c.window)
+	form.Resize(fyne.NewSize(500, 0))
+	form.Show()
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// templateFromFile is the template option that starts from a saved canvas
+const templateFromFile = "Saved canvas..."
+
+// markSaved records the canvas as it is now as matching its file, for
+// telling whether there are unsaved changes
+func (c *Canvas) markSaved() {
+	c.savedData = c.getCurrentData()
+}
+
+// hasUnsavedChanges reports whether the canvas changed since it was last
+// saved, loaded or started
+func (c *Canvas) hasUnsavedChanges() bool {
+	chars, items := canvasChange(c.savedData, c.getCurrentData())
+	return chars > 0 || items > 0
+}
+
+// newCanvas asks for the template of a new canvas, a blank canvas of any
+// type or a copy of a saved canvas, and starts it once unsaved changes to
+// the current one are dealt with
+func (c *Canvas) newCanvas() {
+	var options []string
+	for _, kind := range canvasTypes {
+		options = append(options, kind.Name)
+	}
+	options = append(options, templateFromFile)
+	template := widget.NewRadioGroup(options, nil)
+	template.Required = true
+	template.SetSelected(findCanvasType(c.canvasTypeID).Name)
+
+	dialog.ShowForm("New Canvas", "Create", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Template", template),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		if template.Selected == templateFromFile {
+			c.newCanvasFromFile()
+			return
+		}
+		for _, kind := range canvasTypes {
+			if kind.Name == template.Selected {
+				data := CanvasData{CanvasType: kind.ID}
+				c.confirmReplace("A new canvas", data, func() { c.startCanvas(data) })
+			}
+		}
+	}, c.window)
+}
+
+// newCanvasFromFile starts a new canvas with the content of a saved one,
+// leaving the history of both behind
+func (c *Canvas) newCanvasFromFile() {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		data, err := readCanvasData(reader)
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		data.SectionEdited = nil
+		data.SectionEditors = nil
+		data.CRDT = nil
+		c.confirmReplace("A new canvas", data, func() { c.startCanvas(data) })
+	}, c.window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	openDialog.Show()
+}
+
+// startCanvas replaces the canvas with a new one, clearing versions,
+// branches, recordings and locks of the previous canvas. Undo only goes
+// back to the previous canvas.
+func (c *Canvas) startCanvas(data CanvasData) {
+	if c.recording != nil {
+		c.lastRecording = c.recording
+		c.recording = nil
+	}
+	c.undoStack = []CanvasData{c.getCurrentData()}
+	c.redoStack = nil
+	c.versions = nil
+	c.branch = ""
+	c.lockedSections = make(map[string]bool)
+	c.watchOpenFile(nil)
+
+	c.setCurrentData(data)
+	c.resetSectionEdits(data.SectionEdited, data.SectionEditors)
+	c.resetSectionCRDT(data.CRDT)
+	c.lastSaved = time.Now()
+	c.refreshLayout()
+	c.refreshMentions()
+	c.markSaved()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// prefPDFPresenterNotes makes PDF exports an internal variant including
+// the presenter notes
+const prefPDFPresenterNotes = "pdfPresenterNotes"
+
+// presenterNotesData copies the non-empty presenter notes, nil when there
+// are none
+func (c *Canvas) presenterNotesData() map[string]string {
+	var notes map[string]string
+	for section, note := range c.presenterNotes {
+		if strings.TrimSpace(note) == "" {
+			continue
+		}
+		if notes == nil {
+			notes = make(map[string]string)
+		}
+		notes[section] = note
+	}
+	return notes
+}
+
+func (c *Canvas) setPresenterNotes(notes map[string]string) {
+	c.presenterNotes = make(map[string]string, len(notes))
+	for section, note := range notes {
+		c.presenterNotes[section] = note
+	}
+}
+
+// withoutPresenterNotes strips the hidden notes from canvas data shared
+// outside the team
+func withoutPresenterNotes(data CanvasData) CanvasData {
+	data.PresenterNotes = nil
+	return data
+}
+
+// drawPresenterNotesAnnex lists the presenter notes by section on a new
+// page of an internal export
+func drawPresenterNotesAnnex(pdf *gofpdf.Fpdf, data CanvasData) {
+	if len(data.PresenterNotes) == 0 {
+		return
+	}
+
+	pdf.AddPage()
+	left, top, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	width := pageWidth - left - right
+
+	pdf.SetFont(pdfFontFamily, "B", 20)
+	pdf.SetXY(left, top)
+	pdf.CellFormat(width, 12, "Presenter Notes (internal)", "", 1, "L", false, 0, "")
+
+	for _, section := range data.sections() {
+		note := data.PresenterNotes[section.Title]
+		if note == "" {
+			continue
+		}
+		pdf.Ln(4)
+		pdf.SetFont(pdfFontFamily, "B", 14)
+		pdf.CellFormat(width, 8, section.Title, "B", 1, "L", false, 0, "")
+		pdf.SetFont(pdfFontFamily, "", 10)
+		pdf.MultiCell(width, 5, note, "", "", false)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Preference keys of the OKR tool integration
+const (
+	prefOKREndpoint = "okrEndpoint"
+	prefOKRToken    = "okrToken"
+)
+
+// OKR is a draft objective with its key results
+type OKR struct {
+	Objective  string   `json:"objective"`
+	KeyResults []string `json:"keyResults"`
+}
+
+// draftOKRs turns Value Proposition items into objectives and spreads the
+// Key Activities items over them as key results
+func draftOKRs(data CanvasData) []OKR {
+	var okrs []OKR
+	for _, item := range sectionLines(data.ValueProposition) {
+		okrs = append(okrs, OKR{Objective: "Deliver " + item})
+	}
+	activities := sectionLines(data.KeyActivities)
+	if len(okrs) == 0 && len(activities) > 0 {
+		okrs = append(okrs, OKR{Objective: "Execute the key activities of the business model"})
+	}
+	for i, activity := range activities {
+		okr := &okrs[i%len(okrs)]
+		okr.KeyResults = append(okr.KeyResults, activity+" (target: TBD)")
+	}
+	return okrs
+}
+
+func okrMarkdown(okrs []OKR) string {
+	var b strings.Builder
+	b.WriteString("# Objectives and Key Results\n")
+	for i, okr := range okrs {
+		fmt.Fprintf(&b, "\n## O%d: %s\n", i+1, okr.Objective)
+		for j, kr := range okr.KeyResults {
+			fmt.Fprintf(&b, "- KR%d.%d: %s\n", i+1, j+1, kr)
+		}
+	}
+	return b.String()
+}
+
+// writeOKRCSV writes one row per key result, objectives without key
+// results get a row of their own
+func writeOKRCSV(w io.Writer, okrs []OKR) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"Objective", "Key Result"})
+	for _, okr := range okrs {
+		if len(okr.KeyResults) == 0 {
+			out.Write([]string{okr.Objective, ""})
+		}
+		for _, kr := range okr.KeyResults {
+			out.Write([]string{okr.Objective, kr})
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// pushOKRs posts the OKRs as JSON to an OKR tool endpoint
+func pushOKRs(endpoint, token string, okrs []OKR) error {
+	body, err := json.Marshal(map[string][]OKR{"okrs": okrs})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("OKR tool responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// showOKRGenerator drafts OKRs from the canvas and exports them as
+// Markdown or CSV, or pushes them to an OKR tool
+func (c *Canvas) showOKRGenerator() {
+	okrs := draftOKRs(c.getCurrentData())
+	if len(okrs) == 0 {
+		dialog.ShowInformation("OKRs", "Fill in the Value Proposition and Key Activities to draft OKRs", c.window)
+		return
+	}
+
+	preview := widget.NewMultiLineEntry()
+	preview.SetText(okrMarkdown(okrs))
+	preview.Wrapping = fyne.TextWrapWord
+	preview.Disable()
+
+	copyMarkdown := widget.NewButton("Copy Markdown", func() {
+		c.window.Clipboard().SetContent(okrMarkdown(okrs))
+	})
+	saveCSV := widget.NewButton("Save CSV...", func() {
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			if err := writeOKRCSV(writer, okrs); err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			dialog.ShowInformation("Success", "OKRs have been exported successfully", c.window)
+		}, c.window)
+		saveDialog.SetFileName("okrs.csv")
+		saveDialog.Show()
+	})
+
+	endpointEntry := widget.NewEntry()
+	endpointEntry.SetPlaceHolder("https://okr.example.com/api/okrs")
+	endpointEntry.SetText(c.prefs.String(prefOKREndpoint))
+	tokenEntry := widget.NewPasswordEntry()
+	tokenEntry.SetText(c.prefs.String(prefOKRToken))
+	push := widget.NewButton("Push to OKR Tool", func() {
+		endpoint := strings.TrimSpace(endpointEntry.Text)
+		if endpoint == "" {
+			dialog.ShowError(fmt.Errorf("OKR tool endpoint is required"), c.window)
+			return
+		}
+		c.prefs.SetString(prefOKREndpoint, endpoint)
+		c.prefs.SetString(prefOKRToken, tokenEntry.Text)
+
+		go func() {
+			if err := pushOKRs(endpoint, tokenEntry.Text, okrs); err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			dialog.ShowInformation("Success", "OKRs have been pushed to the OKR tool", c.window)
+		}()
+	})
+
+	integration := &widget.Form{Items: []*widget.FormItem{
+		widget.NewFormItem("Endpoint", endpointEntry),
+		widget.NewFormItem("API token", tokenEntry),
+	}}
+
+	content := container.NewBorder(nil,
+		container.NewVBox(container.NewHBox(copyMarkdown, saveCSV), widget.NewSeparator(), integration, push),
+		nil, nil, preview)
+	okrDialog := dialog.NewCustom("Draft OKRs", "Close", content, c.window)
+	okrDialog.Resize(fyne.NewSize(600, 550))
+	okrDialog.Show()
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// pdfFontFamily is the family name the export font is registered under
+const pdfFontFamily = "Canvas"
+
+// Preference keys for PDF exports
+const (
+	prefPDFFont    = "pdfFont"
+	prefPDFColored = "pdfColored"
+)
+
+// PDFFont holds the TrueType font data embedded in PDF exports
+type PDFFont struct {
+	Regular []byte
+	Bold    []byte
+}
+
+// pdfOptions collects the settings that shape PDF exports
+type pdfOptions struct {
+	Font      PDFFont
+	Branding  PDFBranding
+	Palette   *pdfPalette // nil for plain black borders
+	Comments  []Comment   // appended as an annex when not empty
+	WordCloud bool        // adds a word cloud page
+	Stats     string      // statistics line in the footer, empty for none
+
+	// PresenterNotes makes an internal variant with a presenter notes annex
+	PresenterNotes bool
+
+	// PageSize is a page size such as A3, or pdfPageAuto to fit the page
+	// to the content. Empty keeps the default page.
+	PageSize string
+	// Environment is the Business Model Environment drawn around a
+	// Business Model Canvas, nil for none
+	Environment *CanvasData
+	// canvasPage is the page chosen for the canvas being exported
+	canvasPage pdfPage
+}
+
+// pdfPalette colors section headers and backgrounds in colored exports
+type pdfPalette struct {
+	Header     [3]int
+	HeaderText [3]int
+	Fill       [3]int
+	Border     [3]int
+	Text       [3]int
+}
+
+// Palettes matching the app themes, toned for print
+var (
+	professionalPalette = pdfPalette{
+		Header:     [3]int{40, 44, 52},
+		HeaderText: [3]int{255, 255, 255},
+		Fill:       [3]int{236, 239, 244},
+		Border:     [3]int{40, 44, 52},
+		Text:       [3]int{33, 33, 33},
+	}
+	lightPalette = pdfPalette{
+		Header:     [3]int{33, 150, 243},
+		HeaderText: [3]int{255, 255, 255},
+		Fill:       [3]int{250, 250, 250},
+		Border:     [3]int{187, 222, 251},
+		Text:       [3]int{33, 33, 33},
+	}
+)
+
+// builtinPDFFont is the Noto Sans font bundled with Fyne, which covers
+// Latin, Greek and Cyrillic scripts. coverText falls back to a system font
+// for others.
+func builtinPDFFont() PDFFont {
+	return PDFFont{
+		Regular: theme.DefaultTextFont().Content(),
+		Bold:    theme.DefaultTextBoldFont().Content(),
+	}
+}
+
+// loadPDFFont reads a TrueType font from a URI, the same file is used for
+// both regular and bold text
+func loadPDFFont(uri fyne.URI) (PDFFont, error) {
+	data, err := readURI(uri)
+	if err != nil {
+		return PDFFont{}, err
+	}
+	return PDFFont{Regular: data, Bold: data}, nil
+}
+
+// pdfOptions returns the export options from the current settings
+func (c *Canvas) pdfOptions() (pdfOptions, error) {
+	opts := pdfOptions{Font: builtinPDFFont(), Branding: c.loadBranding()}
+	if c.prefs.Bool(prefPDFComments) {
+		opts.Comments = c.allComments()
+	}
+	opts.WordCloud = c.prefs.Bool(prefPDFWordCloud)
+	opts.PresenterNotes = c.prefs.Bool(prefPDFPresenterNotes)
+	opts.PageSize = c.prefs.StringWithFallback(prefPDFPageSize, pdfPageAuto)
+	if c.prefs.Bool(prefExportStats) {
+		opts.Stats = c.canvasStats().summary()
+	}
+	if c.prefs.Bool(prefPDFColored) {
+		opts.Palette = &lightPalette
+		if c.currentTheme == "professional" {
+			opts.Palette = &professionalPalette
+		}
+	}
+	if err := opts.Branding.loadLogo(); err != nil {
+		return opts, err
+	}
+
+	if fontURI := c.prefs.String(prefPDFFont); fontURI != "" {
+		uri, err := storage.ParseURI(fontURI)
+		if err != nil {
+			return opts, err
+		}
+		opts.Font, err = loadPDFFont(uri)
+		if err != nil {
+			return opts, err
+		}
+	}
+	opts.Font = opts.Font.coverText(pdfText(c.getCurrentData(), opts.Comments))
+
+	return opts, nil
+}
+
+// newPDF creates a document of the chosen page, A3 landscape by default,
+// with the export font registered and the branding applied
+func newPDF(opts pdfOptions) *gofpdf.Fpdf {
+	page := opts.page()
+	pdf := gofpdf.New(page.Orientation, "mm", page.Size, "")
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "", opts.Font.Regular)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "B", opts.Font.Bold)
+	pdf.SetAutoPageBreak(true, 10)
+	applyBranding(pdf, opts.Branding, opts.Stats)
+	return pdf
+}
+
+// pdfSectionHeaderHeight is the height of the colored title band
+const pdfSectionHeaderHeight = 12.0
+
+func drawColoredSection(pdf *gofpdf.Fpdf, opts pdfOptions, cell pdfCell, title, content string) {
+	palette := opts.Palette
+	x, y, w, h := cell.X, cell.Y, cell.W, cell.H
+	pdf.SetDrawColor(palette.Border[0], palette.Border[1], palette.Border[2])
+	pdf.SetFillColor(palette.Fill[0], palette.Fill[1], palette.Fill[2])
+	pdf.Rect(x, y, w, h, "FD")
+
+	// Header band
+	pdf.SetFillColor(palette.Header[0], palette.Header[1], palette.Header[2])
+	pdf.Rect(x, y, w, pdfSectionHeaderHeight, "F")
+	pdf.SetTextColor(palette.HeaderText[0], palette.HeaderText[1], palette.HeaderText[2])
+	pdf.SetFont(pdfFontFamily, "B", 12)
+	pdf.Text(x+5, y+8, title)
+
+	// Content
+	pdf.SetTextColor(palette.Text[0], palette.Text[1], palette.Text[2])
+	scale := opts.page().Scale
+	pdf.SetFont(pdfFontFamily, "", 10*scale)
+	pdf.SetXY(x+5, y+pdfSectionHeaderHeight+3)
+	pdf.MultiCell(w-10, 5*scale, content, "", "", false)
+
+	// Restore defaults for anything drawn afterwards
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// pdfFontSetting lets the user pick a TrueType font for scripts the built-in
+// font does not cover, such as Japanese
+func (c *Canvas) pdfFontSetting() fyne.CanvasObject {
+	current := widget.NewLabel("")
+	update := func() {
+		fontURI := c.prefs.String(prefPDFFont)
+		if fontURI == "" {
+			current.SetText("Noto Sans (built-in)")
+			return
+		}
+		if uri, err := storage.ParseURI(fontURI); err == nil {
+			current.SetText(uri.Name())
+		}
+	}
+	update()
+
+	choose := widget.NewButton("Choose TTF...", func() {
+		fontDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			reader.Close()
+
+			// Make sure the font can be embedded before keeping it
+			font, err := loadPDFFont(reader.URI())
+			if err == nil {
+				err = newPDF(pdfOptions{Font: font}).Error()
+			}
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+
+			c.prefs.SetString(prefPDFFont, reader.URI().String())
+			update()
+		}, c.window)
+		fontDialog.SetFilter(storage.NewExtensionFileFilter([]string{".ttf"}))
+		fontDialog.Show()
+	})
+	builtin := widget.NewButton("Use built-in", func() {
+		c.prefs.SetString(prefPDFFont, "")
+		update()
+	})
+
+	return container.NewHBox(current, choose, builtin)
+}
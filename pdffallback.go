@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// Text in scripts the export font has no glyphs for, such as Japanese with
+// the built-in Noto Sans, is exported in a TrueType font of the system that
+// has them. A text is written in one font, so the whole export switches to
+// the fallback font, which covers Latin text as well.
+
+// cjkFontPaths are TrueType fonts covering Chinese, Japanese or Korean that
+// systems commonly have, in order of preference. Font collections (.ttc)
+// and OpenType fonts with PostScript outlines cannot be embedded.
+func cjkFontPaths() []string {
+	windows := filepath.Join(os.Getenv("WINDIR"), "Fonts")
+	return []string{
+		// Linux
+		"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+		"/usr/share/fonts/truetype/droid/DroidSansFallback.ttf",
+		"/usr/share/fonts/opentype/ipaexfont-gothic/ipaexg.ttf",
+		"/usr/share/fonts/truetype/fonts-japanese-gothic.ttf",
+		"/usr/share/fonts/truetype/takao-gothic/TakaoPGothic.ttf",
+		"/usr/share/fonts/truetype/nanum/NanumGothic.ttf",
+		"/usr/share/fonts/google-droid-sans-fonts/DroidSansFallbackFull.ttf",
+		// macOS
+		"/System/Library/Fonts/Supplemental/Arial Unicode.ttf",
+		"/Library/Fonts/Arial Unicode.ttf",
+		"/System/Library/Fonts/Supplemental/AppleGothic.ttf",
+		// Windows
+		filepath.Join(windows, "ARIALUNI.TTF"),
+		filepath.Join(windows, "simhei.ttf"),
+		filepath.Join(windows, "malgun.ttf"),
+	}
+}
+
+// missingRunes lists the runes of text a TrueType font has no glyph for
+func missingRunes(font []byte, text string) ([]rune, error) {
+	parsed, err := sfnt.Parse(font)
+	if err != nil {
+		return nil, err
+	}
+	var buf sfnt.Buffer
+	seen := make(map[rune]bool)
+	var missing []rune
+	for _, r := range text {
+		if seen[r] || unicode.IsSpace(r) || unicode.IsControl(r) {
+			continue
+		}
+		seen[r] = true
+		if index, err := parsed.GlyphIndex(&buf, r); err == nil && index == 0 {
+			missing = append(missing, r)
+		}
+	}
+	return missing, nil
+}
+
+// coverText is the font to export text in: the font itself, or the system
+// font with the most glyphs of the text the font lacks
+func (f PDFFont) coverText(text string) PDFFont {
+	missing, err := missingRunes(f.Regular, text)
+	if err != nil || len(missing) == 0 {
+		return f
+	}
+	best, covered := f, 0
+	for _, path := range cjkFontPaths() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		lacking, err := missingRunes(data, string(missing))
+		if err != nil || len(missing)-len(lacking) <= covered {
+			continue
+		}
+		fallback := PDFFont{Regular: data, Bold: data}
+		if newPDF(pdfOptions{Font: fallback}).Error() != nil {
+			continue
+		}
+		best, covered = fallback, len(missing)-len(lacking)
+		if len(lacking) == 0 {
+			break
+		}
+	}
+	return best
+}
+
+// pdfText is the text of canvas data a PDF export may show
+func pdfText(data CanvasData, comments []Comment) string {
+	var b strings.Builder
+	for _, section := range data.sections() {
+		b.WriteString(section.Title)
+		b.WriteString(section.Text)
+	}
+	for _, layer := range canvasLayers {
+		for title, text := range data.layerData(layer.Name) {
+			b.WriteString(title)
+			b.WriteString(text)
+		}
+	}
+	for _, note := range data.PresenterNotes {
+		b.WriteString(note)
+	}
+	for _, comment := range comments {
+		b.WriteString(comment.Author)
+		b.WriteString(comment.Text)
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// prefPDFPageSize is the page size of canvas exports, or pdfPageAuto
+const prefPDFPageSize = "pdfPageSize"
+
+// pdfPageAuto picks the page size, orientation and font scale by how much
+// content the canvas has
+const pdfPageAuto = "auto"
+
+// pdfPage is the page format and font scale of a canvas export
+type pdfPage struct {
+	Size        string
+	Orientation string
+	Scale       float64
+}
+
+// defaultPDFPage is the A3 landscape page used when none is chosen
+var defaultPDFPage = pdfPage{Size: "A3", Orientation: "L", Scale: 1}
+
+// pdfPageCandidates are tried in order when fitting the page to the
+// content, preferring smaller pages and the full font size
+var pdfPageCandidates = []pdfPage{
+	{Size: "A4", Orientation: "L", Scale: 1},
+	{Size: "A4", Orientation: "P", Scale: 1},
+	{Size: "A3", Orientation: "L", Scale: 1},
+	{Size: "A3", Orientation: "P", Scale: 1},
+	{Size: "A3", Orientation: "L", Scale: 0.85},
+	{Size: "A2", Orientation: "L", Scale: 1},
+	{Size: "A2", Orientation: "L", Scale: 0.85},
+	{Size: "A1", Orientation: "L", Scale: 0.85},
+	{Size: "A1", Orientation: "L", Scale: 0.7},
+}
+
+// page returns the page chosen for the canvas, the default when none was
+func (o pdfOptions) page() pdfPage {
+	if o.canvasPage.Size == "" {
+		return defaultPDFPage
+	}
+	return o.canvasPage
+}
+
+// choosePDFPage returns the page for exporting a canvas: the configured
+// size, or when automatic the first candidate page on which the text of
+// every section fits its box
+func choosePDFPage(opts pdfOptions, data CanvasData) pdfPage {
+	switch opts.PageSize {
+	case "":
+		return defaultPDFPage
+	case pdfPageAuto:
+	default:
+		return pdfPage{Size: opts.PageSize, Orientation: "L", Scale: 1}
+	}
+
+	kind := data.canvasType()
+	sections := data.sections()
+	for _, candidate := range pdfPageCandidates {
+		opts.canvasPage = candidate
+		pdf := newPDF(opts)
+		if pdf.Err() {
+			return defaultPDFPage
+		}
+
+		fits := true
+		pdf.SetFont(pdfFontFamily, "", 10*candidate.Scale)
+		for i, cell := range canvasGridCells(pdf, kind.Layout, len(kind.Titles), len(data.CustomSections)) {
+			lines := pdf.SplitText(sections[i].Text, cell.W-10)
+			// The title takes the top 15mm of a section
+			if 15+float64(len(lines))*5*candidate.Scale > cell.H {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			return candidate
+		}
+	}
+	return pdfPageCandidates[len(pdfPageCandidates)-1]
+}
+
+// pdfPageSetting chooses the page size of canvas exports in the settings
+// dialog
+func (c *Canvas) pdfPageSetting() fyne.CanvasObject {
+	options := []string{"Automatic", "A4", "A3", "A2"}
+	pageSelect := widget.NewSelect(options, func(selected string) {
+		if selected == "Automatic" {
+			selected = pdfPageAuto
+		}
+		c.prefs.SetString(prefPDFPageSize, selected)
+	})
+	current := c.prefs.StringWithFallback(prefPDFPageSize, pdfPageAuto)
+	if current == pdfPageAuto {
+		current = "Automatic"
+	}
+	pageSelect.SetSelected(current)
+	return pageSelect
+}
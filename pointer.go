@@ -0,0 +1,133 @@
+package main
+
+import (
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Sizes and timing of the laser pointer and highlights
+const (
+	pointerRadius   = 8
+	highlightRadius = 40
+	highlightFade   = 3 * time.Second
+)
+
+var (
+	pointerColor   = color.NRGBA{R: 0xe5, G: 0x1c, B: 0x23, A: 0xff}
+	highlightColor = color.NRGBA{R: 0xff, G: 0xd6, B: 0x00, A: 0x90}
+)
+
+// pointerPad reports mouse movement and taps over it as positions relative
+// to its size, so a small pad in the presenter window can drive the pointer
+// shown in the audience window
+type pointerPad struct {
+	widget.BaseWidget
+	background *canvas.Rectangle
+	minSize    fyne.Size
+
+	onMove func(fyne.Position)
+	onOut  func()
+	onTap  func(fyne.Position)
+}
+
+func newPointerPad(background color.Color, minSize fyne.Size) *pointerPad {
+	pad := &pointerPad{background: canvas.NewRectangle(background), minSize: minSize}
+	pad.ExtendBaseWidget(pad)
+	return pad
+}
+
+func (p *pointerPad) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(p.background)
+}
+
+func (p *pointerPad) MinSize() fyne.Size {
+	return p.minSize
+}
+
+// relative converts a position on the pad to fractions of its size
+func (p *pointerPad) relative(pos fyne.Position) fyne.Position {
+	size := p.Size()
+	if size.Width == 0 || size.Height == 0 {
+		return fyne.Position{}
+	}
+	return fyne.NewPos(pos.X/size.Width, pos.Y/size.Height)
+}
+
+func (p *pointerPad) MouseIn(event *desktop.MouseEvent) {
+	p.MouseMoved(event)
+}
+
+func (p *pointerPad) MouseMoved(event *desktop.MouseEvent) {
+	if p.onMove != nil {
+		p.onMove(p.relative(event.Position))
+	}
+}
+
+func (p *pointerPad) MouseOut() {
+	if p.onOut != nil {
+		p.onOut()
+	}
+}
+
+func (p *pointerPad) Tapped(event *fyne.PointEvent) {
+	if p.onTap != nil {
+		p.onTap(p.relative(event.Position))
+	}
+}
+
+// pointerOverlay draws the laser pointer and fading highlights over the
+// audience slide
+type pointerOverlay struct {
+	layer *fyne.Container
+	dot   *canvas.Circle
+}
+
+func newPointerOverlay() *pointerOverlay {
+	dot := canvas.NewCircle(pointerColor)
+	dot.Resize(fyne.NewSquareSize(2 * pointerRadius))
+	dot.Hide()
+	return &pointerOverlay{layer: container.NewWithoutLayout(dot), dot: dot}
+}
+
+// absolute converts a relative position to a position on the overlay
+func (o *pointerOverlay) absolute(rel fyne.Position) fyne.Position {
+	size := o.layer.Size()
+	return fyne.NewPos(rel.X*size.Width, rel.Y*size.Height)
+}
+
+func (o *pointerOverlay) point(rel fyne.Position) {
+	o.dot.Move(o.absolute(rel).SubtractXY(pointerRadius, pointerRadius))
+	o.dot.Show()
+}
+
+func (o *pointerOverlay) hide() {
+	o.dot.Hide()
+}
+
+// highlight marks a spot on the slide that fades out after a few seconds
+func (o *pointerOverlay) highlight(rel fyne.Position) {
+	ring := canvas.NewCircle(highlightColor)
+	ring.Resize(fyne.NewSquareSize(2 * highlightRadius))
+	ring.Move(o.absolute(rel).SubtractXY(highlightRadius, highlightRadius))
+	// Keep the pointer dot drawn above the highlights
+	o.layer.Objects = append([]fyne.CanvasObject{ring}, o.layer.Objects...)
+	o.layer.Refresh()
+
+	fade := fyne.NewAnimation(highlightFade, func(done float32) {
+		fill := highlightColor
+		fill.A = uint8(float32(highlightColor.A) * (1 - done))
+		ring.FillColor = fill
+		ring.Refresh()
+		if done == 1 {
+			o.layer.Remove(ring)
+		}
+	})
+	fade.Curve = fyne.AnimationEaseIn
+	fade.Start()
+}
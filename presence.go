@@ -0,0 +1,198 @@
+package main
+
+import (
+	"hash/fnv"
+	"image/color"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Presence timing: typing shows briefly after the last keystroke, users
+// turn idle and then away when they stop sending updates
+const (
+	typingTimeout = 3 * time.Second
+	idleAfter     = time.Minute
+	awayAfter     = 5 * time.Minute
+)
+
+// Presence states of a connected user
+const (
+	presenceActive = "active"
+	presenceIdle   = "idle"
+	presenceAway   = "away"
+)
+
+// PresenceUpdate is a message on the presence channel of a collaboration
+// session telling the others where a user is and whether they are typing
+type PresenceUpdate struct {
+	User     string    `json:"user"`
+	Initials string    `json:"initials,omitempty"`
+	Color    string    `json:"color,omitempty"`
+	Section  string    `json:"section,omitempty"`
+	Typing   bool      `json:"typing,omitempty"`
+	At       time.Time `json:"at"`
+	Left     bool      `json:"left,omitempty"`
+}
+
+// presenceState returns whether a user is active, idle or away
+func presenceState(update PresenceUpdate, now time.Time) string {
+	switch since := now.Sub(update.At); {
+	case since >= awayAfter:
+		return presenceAway
+	case since >= idleAfter:
+		return presenceIdle
+	}
+	return presenceActive
+}
+
+// presenceToolbarItem shows the avatars of connected users on the toolbar
+type presenceToolbarItem struct {
+	avatars *fyne.Container
+}
+
+func (p *presenceToolbarItem) ToolbarObject() fyne.CanvasObject {
+	return p.avatars
+}
+
+// avatarColor picks a stable color for a user from their name
+func avatarColor(user string) color.NRGBA {
+	palette := []color.NRGBA{
+		{R: 0xe5, G: 0x39, B: 0x35, A: 0xff},
+		{R: 0x1e, G: 0x88, B: 0xe5, A: 0xff},
+		{R: 0x43, G: 0xa0, B: 0x47, A: 0xff},
+		{R: 0xfb, G: 0x8c, B: 0x00, A: 0xff},
+		{R: 0x8e, G: 0x24, B: 0xaa, A: 0xff},
+		{R: 0x00, G: 0x89, B: 0x7b, A: 0xff},
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(user))
+	return palette[hash.Sum32()%uint32(len(palette))]
+}
+
+// initials returns up to two initials of a user name
+func initials(user string) string {
+	var letters []rune
+	for _, word := range strings.Fields(user) {
+		letters = append(letters, []rune(strings.ToUpper(word))[0])
+		if len(letters) == 2 {
+			break
+		}
+	}
+	return string(letters)
+}
+
+// avatar draws a user's initials in a circle, faded when idle and grey
+// when away
+func avatar(update PresenceUpdate, state string) fyne.CanvasObject {
+	fill, ok := parseHexColor(update.Color)
+	if !ok {
+		fill = avatarColor(update.User)
+	}
+	switch state {
+	case presenceIdle:
+		fill.A = 0x80
+	case presenceAway:
+		fill = color.NRGBA{R: 0x9e, G: 0x9e, B: 0x9e, A: 0xff}
+	}
+	return identityAvatar(update.User, update.Initials, hexColor(fill), 28)
+}
+
+// typingLabel returns the "is typing" indicator of a section
+func (c *Canvas) typingLabel(section string) *widget.Label {
+	label, ok := c.typingLabels[section]
+	if !ok {
+		label = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+		label.Hide()
+		c.typingLabels[section] = label
+	}
+	return label
+}
+
+// withTypingIndicator adds the typing indicator below a section
+func (c *Canvas) withTypingIndicator(section string, content fyne.CanvasObject) fyne.CanvasObject {
+	return container.NewBorder(nil, c.typingLabel(section), nil, nil, content)
+}
+
+// applyPresence records a presence update from another user
+func (c *Canvas) applyPresence(update PresenceUpdate) {
+	c.collabMu.Lock()
+	if update.Left {
+		delete(c.presence, update.User)
+	} else {
+		c.presence[update.User] = update
+	}
+	c.collabMu.Unlock()
+	c.refreshPresence()
+}
+
+// publishTyping tells the other users of a collaboration session that this
+// user is typing in a section
+func (c *Canvas) publishTyping(section string) {
+	c.noteLocalTyping(section)
+	if c.presenceSink == nil {
+		return
+	}
+	identity := c.identity()
+	c.presenceSink(PresenceUpdate{User: identity.Name, Initials: identity.Initials, Color: identity.Color, Section: section, Typing: true, At: time.Now()})
+}
+
+// refreshPresence updates the avatars and typing indicators
+func (c *Canvas) refreshPresence() {
+	c.collabMu.Lock()
+	defer c.collabMu.Unlock()
+	now := time.Now()
+	users := make([]string, 0, len(c.presence))
+	for user := range c.presence {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	typing := make(map[string][]string)
+	c.presenceBar.RemoveAll()
+	for _, user := range users {
+		update := c.presence[user]
+		c.presenceBar.Add(avatar(update, presenceState(update, now)))
+		if update.Typing && now.Sub(update.At) < typingTimeout {
+			typing[update.Section] = append(typing[update.Section], user)
+		}
+	}
+
+	c.refreshRemoteLocks(typing)
+	for section, label := range c.typingLabels {
+		lock := ""
+		if c.remoteLocks[section] {
+			lock = "Locked: "
+		}
+		switch names := typing[section]; len(names) {
+		case 0:
+			label.Hide()
+		case 1:
+			label.SetText(lock + names[0] + " is typing…")
+			label.Show()
+		default:
+			label.SetText(lock + strings.Join(names, ", ") + " are typing…")
+			label.Show()
+		}
+	}
+}
+
+// presenceRoutine expires typing indicators and moves users to idle and
+// away as time passes without updates
+func (c *Canvas) presenceRoutine() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.collabMu.Lock()
+		present := len(c.presence) > 0
+		c.collabMu.Unlock()
+		if present {
+			c.refreshPresence()
+		}
+		c.flushQueuedEdits(false)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// presentation shows the canvas one section at a time in an audience window
+// while the presenter controls it from a separate window
+type presentation struct {
+	canvas   *Canvas
+	sections []sectionContent
+	index    int
+	started  time.Time
+	stop     chan struct{}
+	ended    bool
+
+	audience  fyne.Window
+	presenter fyne.Window
+	slide     *widget.RichText
+	current   *widget.Label
+	upcoming  *widget.Label
+	timer     *widget.Label
+	comments  *widget.Label
+	notes     *widget.Entry
+	pointer   *pointerOverlay
+	pointing  bool
+}
+
+// startPresentation opens the audience and presenter windows. Fyne cannot
+// place windows on a particular display, so the audience window opens as
+// its own window to be moved to the external display and made full screen
+// from the presenter controls, while the controls stay on the laptop screen.
+func (c *Canvas) startPresentation() {
+	app := fyne.CurrentApp()
+	p := &presentation{
+		canvas:    c,
+		sections:  c.getCurrentData().sections(),
+		started:   time.Now(),
+		stop:      make(chan struct{}),
+		audience:  app.NewWindow("Business Canvas — Audience"),
+		presenter: app.NewWindow("Business Canvas — Presenter"),
+		slide:     widget.NewRichText(),
+		current:   widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		upcoming:  widget.NewLabel(""),
+		timer:     widget.NewLabel(""),
+		comments:  widget.NewLabel(""),
+		notes:     widget.NewMultiLineEntry(),
+		pointer:   newPointerOverlay(),
+	}
+	p.notes.SetPlaceHolder("Presenter notes, only shown here")
+	p.notes.Wrapping = fyne.TextWrapWord
+	p.notes.OnChanged = func(note string) {
+		c.presenterNotes[p.sections[p.index].Title] = note
+	}
+	p.slide.Wrapping = fyne.TextWrapWord
+	p.comments.Wrapping = fyne.TextWrapWord
+
+	// The pointer follows the mouse over the audience window or over the
+	// smaller pad in the presenter window, clicking leaves a highlight
+	audiencePad := newPointerPad(color.Transparent, fyne.Size{})
+	presenterPad := newPointerPad(theme.Color(theme.ColorNameInputBackground), fyne.NewSize(192, 108))
+	for _, pad := range []*pointerPad{audiencePad, presenterPad} {
+		pad.onMove = p.point
+		pad.onOut = p.pointer.hide
+		pad.onTap = p.highlight
+	}
+	pointerCheck := widget.NewCheck("Laser pointer (click to highlight)", func(on bool) {
+		p.pointing = on
+		if !on {
+			p.pointer.hide()
+		}
+	})
+
+	p.audience.SetContent(container.NewStack(c.presentationText(container.NewPadded(p.slide)), audiencePad, p.pointer.layer))
+	p.audience.Canvas().SetOnTypedKey(p.typedKey)
+	p.audience.SetCloseIntercept(p.end)
+	p.audience.Resize(fyne.NewSize(1280, 720))
+
+	fullScreen := widget.NewButtonWithIcon("Audience Full Screen", theme.ViewFullScreenIcon(), func() {
+		p.audience.SetFullScreen(!p.audience.FullScreen())
+	})
+	controls := container.NewHBox(
+		widget.NewButtonWithIcon("Previous", theme.NavigateBackIcon(), p.previous),
+		widget.NewButtonWithIcon("Next", theme.NavigateNextIcon(), p.next),
+		fullScreen,
+		widget.NewButtonWithIcon("End", theme.MediaStopIcon(), p.end),
+	)
+	p.presenter.SetContent(container.NewBorder(
+		container.NewVBox(p.timer, p.current, p.upcoming, pointerCheck, container.NewCenter(presenterPad), widget.NewSeparator()),
+		controls, nil, nil,
+		container.NewVSplit(p.notes, container.NewVScroll(p.comments)),
+	))
+	p.presenter.Canvas().SetOnTypedKey(p.typedKey)
+	p.presenter.SetCloseIntercept(p.end)
+	p.presenter.Resize(fyne.NewSize(500, 650))
+
+	go p.runTimer()
+	p.show()
+	p.audience.Show()
+	p.presenter.Show()
+}
+
+func (p *presentation) runTimer() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.timer.SetText("Elapsed: " + time.Since(p.started).Round(time.Second).String())
+		}
+	}
+}
+
+// show updates both windows for the current section
+func (p *presentation) show() {
+	section := p.sections[p.index]
+
+	var slide strings.Builder
+	slide.WriteString("# " + section.Title + "\n\n")
+	lines := sectionLines(section.Text)
+	if len(lines) == 0 {
+		slide.WriteString("*Not yet defined*\n")
+	}
+	for _, line := range lines {
+		slide.WriteString("- " + line + "\n")
+	}
+	p.slide.ParseMarkdown(slide.String())
+
+	p.current.SetText(fmt.Sprintf("%d/%d: %s", p.index+1, len(p.sections), section.Title))
+	p.upcoming.SetText("Next: end of presentation")
+	if p.index < len(p.sections)-1 {
+		p.upcoming.SetText("Next: " + p.sections[p.index+1].Title)
+	}
+
+	p.notes.SetText(p.canvas.presenterNotes[section.Title])
+
+	var comments []string
+	for _, comment := range p.canvas.allComments() {
+		if comment.Section == section.Title {
+			comments = append(comments, fmt.Sprintf("%s: %s", comment.Author, comment.Text))
+		}
+	}
+	if len(comments) == 0 {
+		p.comments.SetText("No comments on this section")
+	} else {
+		p.comments.SetText("Comments:\n" + strings.Join(comments, "\n"))
+	}
+}
+
+func (p *presentation) point(rel fyne.Position) {
+	if p.pointing {
+		p.pointer.point(rel)
+	}
+}
+
+func (p *presentation) highlight(rel fyne.Position) {
+	if p.pointing {
+		p.pointer.highlight(rel)
+	}
+}
+
+func (p *presentation) next() {
+	if p.index < len(p.sections)-1 {
+		p.index++
+		p.show()
+	}
+}
+
+func (p *presentation) previous() {
+	if p.index > 0 {
+		p.index--
+		p.show()
+	}
+}
+
+func (p *presentation) end() {
+	if p.ended {
+		return
+	}
+	p.ended = true
+	close(p.stop)
+	p.audience.Close()
+	p.presenter.Close()
+}
+
+// typedKey steps through the sections with the arrow keys, space and page
+// keys used by presentation clickers, escape ends the presentation
+func (p *presentation) typedKey(event *fyne.KeyEvent) {
+	switch event.Name {
+	case fyne.KeyRight, fyne.KeyDown, fyne.KeySpace, fyne.KeyPageDown:
+		p.next()
+	case fyne.KeyLeft, fyne.KeyUp, fyne.KeyPageUp:
+		p.previous()
+	case fyne.KeyEscape:
+		p.end()
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// profileVersion is the version of the settings profile file format
+const profileVersion = 1
+
+// Preferences carried in a settings profile by type. Passwords and API
+// tokens stay on the machine they were entered on.
+var (
+	profileStrings = []string{
+		prefPDFFont,
+		prefPDFPageSize,
+		prefBrandingLogo,
+		prefBrandingTitle,
+		prefBrandingAuthor,
+		prefAgenda,
+		prefOKREndpoint,
+		prefMirrorTarget,
+		prefMirrorUser,
+	}
+	profileBools = []string{
+		prefPDFColored,
+		prefPDFComments,
+		prefPDFWordCloud,
+		prefPDFPresenterNotes,
+		prefExportStats,
+		prefRetentionThin,
+		prefAutoFit,
+		prefGitCommit,
+		prefGitPush,
+		prefBrandingDate,
+		prefBrandingFooter,
+	}
+	// profileInts maps whole number settings to their defaults
+	profileInts = map[string]int{
+		prefSnapshotChars: defaultSnapshotChars,
+		prefRetentionMax:  0,
+		prefAutoSaveChars: defaultAutoSaveChars,
+		prefAutoSaveItems: defaultAutoSaveItems,
+	}
+	// profileFloats maps decimal settings to their defaults
+	profileFloats = map[string]float64{
+		prefFontScale:         1,
+		prefLineSpacing:       1,
+		prefPresentationScale: 1,
+	}
+)
+
+// SettingsProfile is a portable copy of the app settings
+type SettingsProfile struct {
+	Version     int                `json:"version"`
+	Theme       string             `json:"theme"`
+	AutoSave    bool               `json:"autoSave"`
+	Strings     map[string]string  `json:"strings"`
+	Bools       map[string]bool    `json:"bools"`
+	StaleDays   map[string]int     `json:"staleDays"`
+	WordLimits  map[string]int     `json:"wordLimits,omitempty"`
+	Ints        map[string]int     `json:"ints,omitempty"`
+	Floats      map[string]float64 `json:"floats,omitempty"`
+	Snippets    []string           `json:"snippets,omitempty"`
+	ScriptRules []ScriptRule       `json:"scriptRules,omitempty"`
+}
+
+// profileSections lists every section title a staleness threshold can be
+// set for
+func (c *Canvas) profileSections() []string {
+	var titles []string
+	seen := make(map[string]bool)
+	add := func(title string) {
+		if !seen[title] {
+			seen[title] = true
+			titles = append(titles, title)
+		}
+	}
+	for _, kind := range canvasTypes {
+		for _, title := range kind.Titles {
+			add(title)
+		}
+	}
+	for _, block := range c.customBlocks {
+		add(block.Title)
+	}
+	return titles
+}
+
+func (c *Canvas) exportProfile() SettingsProfile {
+	profile := SettingsProfile{
+		Version:     profileVersion,
+		Theme:       c.currentTheme,
+		AutoSave:    c.autoSave,
+		Strings:     make(map[string]string),
+		Bools:       make(map[string]bool),
+		StaleDays:   make(map[string]int),
+		WordLimits:  make(map[string]int),
+		Ints:        make(map[string]int),
+		Floats:      make(map[string]float64),
+		Snippets:    c.prefs.StringList(prefSnippets),
+		ScriptRules: c.loadScriptRules(),
+	}
+	for key, fallback := range profileInts {
+		profile.Ints[key] = c.prefs.IntWithFallback(key, fallback)
+	}
+	for key, fallback := range profileFloats {
+		profile.Floats[key] = c.prefs.FloatWithFallback(key, fallback)
+	}
+	for _, key := range profileStrings {
+		profile.Strings[key] = c.prefs.String(key)
+	}
+	for _, key := range profileBools {
+		profile.Bools[key] = c.prefs.Bool(key)
+	}
+	for _, section := range c.profileSections() {
+		if days := c.staleDays(section); days != defaultStaleDays {
+			profile.StaleDays[section] = days
+		}
+		for _, key := range []string{prefWordMinPrefix + section, prefWordMaxPrefix + section} {
+			if words, ok := c.wordLimit(key); ok {
+				profile.WordLimits[key] = words
+			}
+		}
+	}
+	return profile
+}
+
+func (c *Canvas) importProfile(profile SettingsProfile) error {
+	if profile.Version > profileVersion {
+		return fmt.Errorf("settings profile version %d is newer than this app supports", profile.Version)
+	}
+
+	for _, key := range profileStrings {
+		if value, ok := profile.Strings[key]; ok {
+			c.prefs.SetString(key, value)
+		}
+	}
+	for _, key := range profileBools {
+		if value, ok := profile.Bools[key]; ok {
+			c.prefs.SetBool(key, value)
+		}
+	}
+	for _, section := range c.profileSections() {
+		c.prefs.RemoveValue(prefStaleDaysPrefix + section)
+		c.prefs.RemoveValue(prefWordMinPrefix + section)
+		c.prefs.RemoveValue(prefWordMaxPrefix + section)
+	}
+	for section, days := range profile.StaleDays {
+		c.prefs.SetInt(prefStaleDaysPrefix+section, days)
+	}
+	for key, words := range profile.WordLimits {
+		if strings.HasPrefix(key, prefWordMinPrefix) || strings.HasPrefix(key, prefWordMaxPrefix) {
+			c.prefs.SetInt(key, words)
+		}
+	}
+
+	for key := range profileInts {
+		if value, ok := profile.Ints[key]; ok {
+			c.prefs.SetInt(key, value)
+		}
+	}
+	for key := range profileFloats {
+		if value, ok := profile.Floats[key]; ok {
+			c.prefs.SetFloat(key, value)
+		}
+	}
+	if profile.Snippets != nil {
+		c.prefs.SetStringList(prefSnippets, profile.Snippets)
+	}
+	if profile.ScriptRules != nil {
+		c.saveScriptRules(profile.ScriptRules)
+	}
+
+	c.autoSave = profile.AutoSave
+	if profile.Theme == "light" {
+		c.currentTheme = "light"
+		c.setTheme(theme.LightTheme())
+	} else {
+		c.currentTheme = "professional"
+		c.setTheme(theme.DarkTheme())
+	}
+	c.refreshStaleness()
+	c.refreshHealth()
+	return nil
+}
+
+// profileSetting exports and imports settings profiles in the settings
+// dialog
+func (c *Canvas) profileSetting() fyne.CanvasObject {
+	exportButton := widget.NewButton("Export...", func() {
+		profile, err := json.MarshalIndent(c.exportProfile(), "", "    ")
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		c.saveInterchangeFile("business-canvas-profile.json", func(writer fyne.URIWriteCloser) error {
+			_, err := writer.Write(profile)
+			return err
+		})
+	})
+
+	importButton := widget.NewButton("Import...", func() {
+		openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			var profile SettingsProfile
+			if err := json.NewDecoder(reader).Decode(&profile); err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if err := c.importProfile(profile); err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			dialog.ShowInformation("Success", "Settings profile imported, reopen Settings to see the changes", c.window)
+		}, c.window)
+		openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+		openDialog.Show()
+	})
+
+	return container.NewHBox(exportButton, importButton)
+}
@@ -0,0 +1,496 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// A project is a folder of canvas files on this computer, shown as a tree
+// in a sidebar. Canvases are created, renamed, duplicated and deleted from
+// there, and searched all at once.
+
+// prefRecentProjects holds the project folders opened last, most recent
+// first. The first one is opened again on start.
+const prefRecentProjects = "recentProjects"
+
+// maxRecentProjects is how many project folders File > Recent Projects lists
+const maxRecentProjects = 8
+
+// projectMatch is a line of a canvas in the project matching a search
+type projectMatch struct {
+	Path    string
+	Section string
+	Line    string
+}
+
+// isCanvasFile reports whether a file in a project is shown as a canvas
+func isCanvasFile(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".json")
+}
+
+// projectEntries lists the folders and canvas files in a folder, leaving
+// out hidden ones
+func projectEntries(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || (!entry.IsDir() && !isCanvasFile(entry.Name())) {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	return paths
+}
+
+// walkCanvases visits every canvas in the folders under root, skipping
+// hidden ones and JSON files that are not canvases
+func walkCanvases(root string, visit func(path string, data CanvasData)) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") && path != root {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || !isCanvasFile(entry.Name()) {
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return nil
+		}
+		data, err := readCanvasData(file)
+		file.Close()
+		if err != nil {
+			// Other JSON files in the folder are not canvases
+			return nil
+		}
+		visit(path, data)
+		return nil
+	})
+}
+
+// searchProject finds the lines of the canvases under root containing the
+// query, ignoring case
+func searchProject(root, query string) ([]projectMatch, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var matches []projectMatch
+	err := walkCanvases(root, func(path string, data CanvasData) {
+		if strings.Contains(strings.ToLower(filepath.Base(path)), query) {
+			matches = append(matches, projectMatch{Path: path})
+		}
+		for _, section := range data.sections() {
+			for _, line := range sectionLines(section.Text) {
+				if strings.Contains(strings.ToLower(line), query) {
+					matches = append(matches, projectMatch{Path: path, Section: section.Title, Line: line})
+				}
+			}
+		}
+	})
+	return matches, err
+}
+
+// rememberProject puts a project folder first in the recent ones
+func (c *Canvas) rememberProject(path string) {
+	recent := []string{path}
+	for _, previous := range c.prefs.StringList(prefRecentProjects) {
+		if previous != path && len(recent) < maxRecentProjects {
+			recent = append(recent, previous)
+		}
+	}
+	c.prefs.SetStringList(prefRecentProjects, recent)
+}
+
+// reopenProject opens the project folder of the last session, if it is
+// still there
+func (c *Canvas) reopenProject() {
+	recent := c.prefs.StringList(prefRecentProjects)
+	if len(recent) == 0 {
+		return
+	}
+	if info, err := os.Stat(recent[0]); err == nil && info.IsDir() {
+		c.openProject(recent[0])
+	}
+}
+
+// recentProjectsMenu lists the recent project folders for the File menu
+func (c *Canvas) recentProjectsMenu() *fyne.Menu {
+	var items []*fyne.MenuItem
+	for _, path := range c.prefs.StringList(prefRecentProjects) {
+		items = append(items, fyne.NewMenuItem(path, func() { c.openProject(path) }))
+	}
+	if len(items) == 0 {
+		none := fyne.NewMenuItem("No recent projects", nil)
+		none.Disabled = true
+		items = append(items, none)
+	}
+	return fyne.NewMenu("", items...)
+}
+
+// openProjectFolder asks for a folder and opens it as the project
+func (c *Canvas) openProjectFolder() {
+	dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if dir == nil {
+			return
+		}
+		if dir.Scheme() != "file" {
+			dialog.ShowError(errors.New("project folders must be on this computer"), c.window)
+			return
+		}
+		c.openProject(dir.Path())
+	}, c.window)
+}
+
+// openProject shows a folder in the project sidebar
+func (c *Canvas) openProject(path string) {
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		dialog.ShowError(fmt.Errorf("%s is not a folder", path), c.window)
+		return
+	}
+	c.project = path
+	c.projectPick = ""
+	c.rememberProject(path)
+	c.projectPane.Objects = []fyne.CanvasObject{c.createProjectPanel()}
+	c.projectPane.Refresh()
+	c.setupMainMenu()
+}
+
+// closeProject hides the project sidebar
+func (c *Canvas) closeProject() {
+	c.project = ""
+	c.projectTree = nil
+	c.projectPane.Objects = nil
+	c.projectPane.Refresh()
+}
+
+// createProjectPanel builds the sidebar tree of the project with its
+// actions above it
+func (c *Canvas) createProjectPanel() fyne.CanvasObject {
+	c.projectTree = widget.NewTree(
+		func(uid widget.TreeNodeID) []widget.TreeNodeID {
+			if uid == "" {
+				uid = c.project
+			}
+			return projectEntries(uid)
+		},
+		func(uid widget.TreeNodeID) bool {
+			if uid == "" {
+				return true
+			}
+			info, err := os.Stat(uid)
+			return err == nil && info.IsDir()
+		},
+		func(branch bool) fyne.CanvasObject {
+			icon := theme.FileIcon()
+			if branch {
+				icon = theme.FolderIcon()
+			}
+			return container.NewHBox(widget.NewIcon(icon), widget.NewLabel("Canvas"))
+		},
+		func(uid widget.TreeNodeID, branch bool, item fyne.CanvasObject) {
+			name := strings.TrimSuffix(filepath.Base(uid), filepath.Ext(uid))
+			if branch {
+				name = filepath.Base(uid)
+			}
+			item.(*fyne.Container).Objects[1].(*widget.Label).SetText(name)
+		},
+	)
+	c.projectTree.OnSelected = func(uid widget.TreeNodeID) {
+		c.projectPick = uid
+		if info, err := os.Stat(uid); err == nil && !info.IsDir() {
+			c.openProjectFile(uid)
+		}
+	}
+
+	actions := widget.NewToolbar(
+		c.toolbarAction(theme.DocumentCreateIcon(), "New canvas in the project", c.newProjectCanvas),
+		c.toolbarAction(theme.ContentCopyIcon(), "Duplicate the selected canvas", c.duplicateProjectFile),
+		c.toolbarAction(theme.DocumentIcon(), "Rename the selected canvas or folder", c.renameProjectFile),
+		c.toolbarAction(theme.DeleteIcon(), "Delete the selected canvas or folder", c.deleteProjectFile),
+		c.toolbarAction(theme.SearchIcon(), "Search the project", c.showProjectSearch),
+		c.toolbarAction(theme.ViewRefreshIcon(), "Reload the project folder", c.refreshProject),
+	)
+	title := widget.NewLabelWithStyle(filepath.Base(c.project), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	width := canvas.NewRectangle(nil)
+	width.SetMinSize(fyne.NewSize(240, 0))
+	return container.NewStack(width, container.NewBorder(container.NewVBox(title, actions), nil, nil, nil, c.projectTree))
+}
+
+// refreshProject reloads the tree after files changed
+func (c *Canvas) refreshProject() {
+	if c.projectTree != nil {
+		c.projectTree.Refresh()
+	}
+}
+
+// openProjectFile opens a canvas of the project
+func (c *Canvas) openProjectFile(path string) {
+	uri := storage.NewFileURI(path)
+	data, err := readCanvasURI(uri)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %w", filepath.Base(path), err), c.window)
+		return
+	}
+	c.confirmReplace("Opening "+filepath.Base(path), data, func() {
+		c.applyCanvasFile(data)
+		c.watchOpenFile(uri)
+	})
+}
+
+// projectFolderOf is the folder new canvases go in: the selected folder,
+// the folder of the selected canvas or the project folder
+func (c *Canvas) projectFolderOf() string {
+	if c.projectPick == "" {
+		return c.project
+	}
+	if info, err := os.Stat(c.projectPick); err == nil && info.IsDir() {
+		return c.projectPick
+	}
+	return filepath.Dir(c.projectPick)
+}
+
+// projectFileName checks the name of a canvas or folder in the project
+func projectFileName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\:`) {
+		return errors.New("enter a name without slashes")
+	}
+	return nil
+}
+
+// newProjectCanvas creates a blank canvas file in the project and opens it
+func (c *Canvas) newProjectCanvas() {
+	name := widget.NewEntry()
+	name.Validator = projectFileName
+	var options []string
+	for _, kind := range canvasTypes {
+		options = append(options, kind.Name)
+	}
+	kind := widget.NewSelect(options, nil)
+	kind.SetSelected(findCanvasType(c.canvasTypeID).Name)
+
+	dialog.ShowForm("New Canvas in Project", "Create", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Name", name),
+		widget.NewFormItem("Type", kind),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		path := filepath.Join(c.projectFolderOf(), strings.TrimSuffix(strings.TrimSpace(name.Text), ".json")+".json")
+		if _, err := os.Stat(path); err == nil {
+			dialog.ShowError(fmt.Errorf("%s exists already", filepath.Base(path)), c.window)
+			return
+		}
+		data := CanvasData{FormatVersion: canvasFormatVersion}
+		for _, t := range canvasTypes {
+			if t.Name == kind.Selected {
+				data.CanvasType = t.ID
+			}
+		}
+		content, err := json.MarshalIndent(data, "", "    ")
+		if err == nil {
+			err = writeLocalFile(path, content)
+		}
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		c.refreshProject()
+		c.projectTree.OpenBranch(filepath.Dir(path))
+		c.projectTree.Select(path)
+	}, c.window)
+}
+
+// pickedProjectFile is the selected canvas or folder, telling the user
+// when nothing is selected
+func (c *Canvas) pickedProjectFile(action string) (string, bool) {
+	if c.projectPick == "" {
+		dialog.ShowInformation(action, "Select a canvas or folder in the project first", c.window)
+		return "", false
+	}
+	return c.projectPick, true
+}
+
+// projectFileMoved follows a canvas that was renamed or deleted when it is
+// the open one
+func (c *Canvas) projectFileMoved(from, to string) {
+	if c.openFile == nil || c.openFile.Scheme() != "file" {
+		return
+	}
+	open := c.openFile.Path()
+	if open != from && !strings.HasPrefix(open, from+string(filepath.Separator)) {
+		return
+	}
+	if to == "" {
+		c.watchOpenFile(nil)
+		return
+	}
+	c.watchOpenFile(storage.NewFileURI(to + strings.TrimPrefix(open, from)))
+}
+
+// renameProjectFile renames the selected canvas or folder
+func (c *Canvas) renameProjectFile() {
+	path, ok := c.pickedProjectFile("Rename")
+	if !ok {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	name := widget.NewEntry()
+	name.Validator = projectFileName
+	name.SetText(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	if info.IsDir() {
+		name.SetText(filepath.Base(path))
+	}
+	dialog.ShowForm("Rename", "Rename", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Name", name),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		newName := strings.TrimSpace(name.Text)
+		if !info.IsDir() {
+			newName = strings.TrimSuffix(newName, ".json") + ".json"
+		}
+		target := filepath.Join(filepath.Dir(path), newName)
+		if _, err := os.Stat(target); err == nil {
+			dialog.ShowError(fmt.Errorf("%s exists already", newName), c.window)
+			return
+		}
+		if err := os.Rename(path, target); err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		c.projectFileMoved(path, target)
+		c.projectPick = ""
+		c.projectTree.UnselectAll()
+		c.refreshProject()
+	}, c.window)
+}
+
+// duplicateProjectFile copies the selected canvas next to it
+func (c *Canvas) duplicateProjectFile() {
+	path, ok := c.pickedProjectFile("Duplicate")
+	if !ok {
+		return
+	}
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		dialog.ShowInformation("Duplicate", "Only canvases can be duplicated", c.window)
+		return
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	base := strings.TrimSuffix(path, filepath.Ext(path)) + " copy"
+	target := base + ".json"
+	for n := 2; ; n++ {
+		if _, err := os.Stat(target); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		target = fmt.Sprintf("%s %d.json", base, n)
+	}
+	if err := writeLocalFile(target, content); err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	c.refreshProject()
+	c.projectTree.Select(target)
+}
+
+// deleteProjectFile deletes the selected canvas, or folder if it is empty
+func (c *Canvas) deleteProjectFile() {
+	path, ok := c.pickedProjectFile("Delete")
+	if !ok {
+		return
+	}
+	dialog.ShowConfirm("Delete", "Delete "+filepath.Base(path)+"? This cannot be undone.", func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := os.Remove(path); err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		c.projectFileMoved(path, "")
+		c.projectPick = ""
+		c.projectTree.UnselectAll()
+		c.refreshProject()
+	}, c.window)
+}
+
+// showProjectSearch searches every canvas of the project, opening the one
+// a result is in when it is chosen
+func (c *Canvas) showProjectSearch() {
+	if c.project == "" {
+		dialog.ShowInformation("Search Project", "Open a project folder first", c.window)
+		return
+	}
+	var matches []projectMatch
+	status := widget.NewLabel("")
+	results := widget.NewList(
+		func() int { return len(matches) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			match := matches[id]
+			file, _ := filepath.Rel(c.project, match.Path)
+			text := file
+			if match.Section != "" {
+				text += " - " + match.Section + ": " + match.Line
+			}
+			item.(*widget.Label).SetText(text)
+		},
+	)
+	query := widget.NewEntry()
+	query.SetPlaceHolder("Search all canvases in " + filepath.Base(c.project))
+	query.OnSubmitted = func(text string) {
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		found, err := searchProject(c.project, text)
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		matches = found
+		status.SetText(fmt.Sprintf("%d matches", len(matches)))
+		results.UnselectAll()
+		results.Refresh()
+	}
+
+	d := dialog.NewCustom("Search Project", "Close", container.NewBorder(container.NewVBox(query, status), nil, nil, nil, results), c.window)
+	results.OnSelected = func(id widget.ListItemID) {
+		d.Hide()
+		c.openProjectFile(matches[id].Path)
+	}
+	d.Resize(fyne.NewSize(700, 500))
+	d.Show()
+	c.window.Canvas().Focus(query)
+}
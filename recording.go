@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// editMergeWindow is how close consecutive edits to the same section are
+// to be recorded as one, so typing doesn't record every keystroke
+const editMergeWindow = 2 * time.Second
+
+// replaySpeeds are the playback speeds offered when replaying a session
+var replaySpeeds = map[string]float64{"1x": 1, "2x": 2, "5x": 5, "10x": 10, "20x": 20}
+
+// EditOp is a recorded change to a section, offset from the start of the
+// recording
+type EditOp struct {
+	At      time.Duration `json:"at"`
+	Section string        `json:"section"`
+	Text    string        `json:"text"`
+}
+
+// SessionRecording is the sequence of edits made during a workshop
+type SessionRecording struct {
+	Started time.Time  `json:"started"`
+	Initial CanvasData `json:"initial"`
+	Ops     []EditOp   `json:"ops"`
+}
+
+// setSectionText replaces the text of a section by title
+func (d *CanvasData) setSectionText(title, text string) {
+	for i, t := range d.canvasType().Titles {
+		if t == title {
+			*d.standardFields()[i] = text
+			return
+		}
+	}
+	for i := range d.CustomSections {
+		if d.CustomSections[i].Title == title {
+			d.CustomSections[i].Text = text
+			return
+		}
+	}
+}
+
+// state returns the canvas after the first n recorded edits
+func (r *SessionRecording) state(n int) CanvasData {
+	data := r.Initial
+	data.CustomSections = slices.Clone(r.Initial.CustomSections)
+	for _, op := range r.Ops[:n] {
+		data.setSectionText(op.Section, op.Text)
+	}
+	return data
+}
+
+// states returns the canvas before and after every recorded edit, captioned
+// with the time into the session, and the delay until the next edit at the
+// given speed
+func (r *SessionRecording) states(speed float64) ([]CanvasData, []string, []time.Duration) {
+	var states []CanvasData
+	var captions []string
+	var delays []time.Duration
+	for n := 0; n <= len(r.Ops); n++ {
+		states = append(states, r.state(n))
+		var at time.Duration
+		if n > 0 {
+			at = r.Ops[n-1].At
+		}
+		captions = append(captions, at.Round(time.Second).String())
+		if n < len(r.Ops) {
+			var gap time.Duration
+			if n > 0 {
+				gap = r.Ops[n].At - r.Ops[n-1].At
+			}
+			delays = append(delays, frameDelay(time.Duration(float64(gap)/speed)))
+		}
+	}
+	delays = append(delays, lastFrameDelay)
+	return states, captions, delays
+}
+
+// recordEdit adds a section change to the recording in progress
+func (c *Canvas) recordEdit(section, text string) {
+	if c.recording == nil {
+		return
+	}
+	op := EditOp{At: time.Since(c.recording.Started), Section: section, Text: text}
+	ops := c.recording.Ops
+	if n := len(ops); n > 0 && ops[n-1].Section == section && op.At-ops[n-1].At < editMergeWindow {
+		ops[n-1] = op
+		return
+	}
+	c.recording.Ops = append(ops, op)
+}
+
+// showRecording starts and stops session recordings and replays, saves or
+// opens them
+func (c *Canvas) showRecording() {
+	status := widget.NewLabel("")
+	var recordButton *widget.Button
+	update := func() {
+		switch {
+		case c.recording != nil:
+			status.SetText(fmt.Sprintf("Recording since %s", formatClock(c.recording.Started)))
+			recordButton.SetText("Stop Recording")
+			recordButton.SetIcon(theme.MediaStopIcon())
+		case c.lastRecording != nil:
+			status.SetText(fmt.Sprintf("Last recording: %d edits from %s", len(c.lastRecording.Ops), formatStamp(c.lastRecording.Started)))
+			recordButton.SetText("Start Recording")
+			recordButton.SetIcon(theme.MediaRecordIcon())
+		default:
+			status.SetText("No session recorded yet")
+		}
+	}
+
+	recordButton = widget.NewButtonWithIcon("Start Recording", theme.MediaRecordIcon(), func() {
+		if c.recording != nil {
+			c.lastRecording = c.recording
+			c.recording = nil
+		} else {
+			c.recording = &SessionRecording{Started: nowUTC(), Initial: c.getCurrentData()}
+		}
+		update()
+	})
+
+	replayButton := widget.NewButtonWithIcon("Replay", theme.MediaPlayIcon(), func() {
+		if c.lastRecording == nil {
+			dialog.ShowInformation("Replay", "Record or open a session first", c.window)
+			return
+		}
+		c.showReplay(c.lastRecording)
+	})
+
+	saveButton := widget.NewButtonWithIcon("Save...", theme.DocumentSaveIcon(), func() {
+		if c.lastRecording == nil {
+			dialog.ShowInformation("Save Recording", "Record a session first", c.window)
+			return
+		}
+		recording, err := json.MarshalIndent(c.lastRecording, "", "    ")
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		c.saveInterchangeFile("canvas-session.json", func(writer fyne.URIWriteCloser) error {
+			_, err := writer.Write(recording)
+			return err
+		})
+	})
+
+	openButton := widget.NewButtonWithIcon("Open...", theme.FolderOpenIcon(), func() {
+		openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			var recording SessionRecording
+			if err := json.NewDecoder(reader).Decode(&recording); err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			c.lastRecording = &recording
+			update()
+		}, c.window)
+		openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+		openDialog.Show()
+	})
+
+	update()
+	content := container.NewVBox(status, container.NewHBox(recordButton, replayButton, saveButton, openButton))
+	dialog.ShowCustom("Session Recording", "Close", content, c.window)
+}
+
+// showReplay animates how the canvas evolved during a recorded session
+func (c *Canvas) showReplay(recording *SessionRecording) {
+	window := fyne.CurrentApp().NewWindow("Session Replay")
+	view := container.NewStack()
+	position := widget.NewSlider(0, float64(len(recording.Ops)))
+	position.Step = 1
+	elapsed := widget.NewLabel("")
+	speed := widget.NewSelect([]string{"1x", "2x", "5x", "10x", "20x"}, nil)
+	speed.SetSelected("5x")
+
+	step := 0
+	showStep := func(n int) {
+		step = n
+		view.Objects = []fyne.CanvasObject{canvasFrameView(recording.state(n))}
+		view.Refresh()
+		var at time.Duration
+		if n > 0 {
+			at = recording.Ops[n-1].At
+		}
+		elapsed.SetText(fmt.Sprintf("%d/%d edits, %s", n, len(recording.Ops), at.Round(time.Second)))
+	}
+	position.OnChanged = func(value float64) {
+		if int(value) != step {
+			showStep(int(value))
+		}
+	}
+
+	var stop chan struct{}
+	var playButton *widget.Button
+	pause := func() {
+		if stop != nil {
+			close(stop)
+			stop = nil
+		}
+		playButton.SetIcon(theme.MediaPlayIcon())
+	}
+	playButton = widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {
+		if stop != nil {
+			pause()
+			return
+		}
+		if step == len(recording.Ops) {
+			position.SetValue(0)
+		}
+		stop = make(chan struct{})
+		playButton.SetIcon(theme.MediaPauseIcon())
+		go func(stop chan struct{}) {
+			for step < len(recording.Ops) {
+				var gap time.Duration
+				if step > 0 {
+					gap = recording.Ops[step].At - recording.Ops[step-1].At
+				}
+				select {
+				case <-stop:
+					return
+				case <-time.After(frameDelay(time.Duration(float64(gap) / replaySpeeds[speed.Selected]))):
+				}
+				position.SetValue(float64(step + 1))
+			}
+			pause()
+		}(stop)
+	})
+
+	exportButton := widget.NewButtonWithIcon("Export...", theme.DownloadIcon(), func() {
+		states, captions, delays := recording.states(replaySpeeds[speed.Selected])
+		c.exportAnimation("canvas-session.gif", states, captions, delays)
+	})
+
+	controls := container.NewBorder(nil, nil,
+		container.NewHBox(playButton, speed), container.NewHBox(elapsed, exportButton), position)
+	window.SetContent(container.NewBorder(nil, controls, nil, nil, view))
+	window.SetOnClosed(pause)
+	window.Resize(fyne.NewSize(1100, 700))
+	showStep(0)
+	window.Show()
+}
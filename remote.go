@@ -0,0 +1,450 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Preference keys of the remote storage canvases are saved to and opened
+// from. The user and secret are the WebDAV credentials or the S3 access key
+// pair.
+const (
+	prefRemoteType     = "remoteType"
+	prefRemoteEndpoint = "remoteEndpoint"
+	prefRemoteBucket   = "remoteBucket"
+	prefRemoteRegion   = "remoteRegion"
+	prefRemoteUser     = "remoteUser"
+	prefRemoteSecret   = "remoteSecret"
+)
+
+// Kinds of remote storage
+const (
+	remoteNone   = "None"
+	remoteWebDAV = "WebDAV"
+	remoteS3     = "S3"
+	// remoteWorkspace is an organization workspace on the self-hosted
+	// server, the bucket names the organization and the secret is the
+	// member token
+	remoteWorkspace = "Workspace"
+)
+
+// defaultS3Region is used when no region is set, MinIO accepts any region
+const defaultS3Region = "us-east-1"
+
+// remoteStore is a place canvas files are kept by name, such as a WebDAV
+// collection or an S3 bucket
+type remoteStore interface {
+	Put(name string, content []byte) error
+	Get(name string) ([]byte, error)
+	List() ([]string, error)
+}
+
+// remoteClient is shared by the remote stores
+var remoteClient = &http.Client{Timeout: time.Minute}
+
+// doRemote sends a request, failing on error statuses
+func doRemote(req *http.Request) ([]byte, error) {
+	resp, err := remoteClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("server responded with %s", resp.Status)
+	}
+	return body, nil
+}
+
+// webdavStore keeps files in a WebDAV collection, e.g. a Nextcloud folder
+type webdavStore struct {
+	URL      string
+	User     string
+	Password string
+}
+
+func (s webdavStore) request(method, name string, body []byte) (*http.Request, error) {
+	target := strings.TrimSuffix(s.URL, "/") + "/" + url.PathEscape(name)
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if s.User != "" {
+		req.SetBasicAuth(s.User, s.Password)
+	}
+	return req, nil
+}
+
+func (s webdavStore) Put(name string, content []byte) error {
+	req, err := s.request(http.MethodPut, name, content)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	_, err = doRemote(req)
+	return err
+}
+
+func (s webdavStore) Get(name string) ([]byte, error) {
+	req, err := s.request(http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	return doRemote(req)
+}
+
+// List asks for the members of the collection with a PROPFIND
+func (s webdavStore) List() ([]string, error) {
+	req, err := s.request("PROPFIND", "", []byte(`<?xml version="1.0"?><propfind xmlns="DAV:"><prop><resourcetype/></prop></propfind>`))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml")
+	body, err := doRemote(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var status struct {
+		Responses []struct {
+			Href       string    `xml:"href"`
+			Collection *struct{} `xml:"propstat>prop>resourcetype>collection"`
+		} `xml:"response"`
+	}
+	if err := xml.Unmarshal(body, &status); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, response := range status.Responses {
+		if response.Collection != nil {
+			continue
+		}
+		name, err := url.PathUnescape(path.Base(response.Href))
+		if err == nil && name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// s3Store keeps files in an S3 compatible bucket, e.g. on MinIO, addressed
+// path-style and signed with AWS Signature Version 4
+type s3Store struct {
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
+func (s s3Store) request(method, key string, query url.Values, body []byte) (*http.Request, error) {
+	target := strings.TrimSuffix(s.Endpoint, "/") + "/" + url.PathEscape(s.Bucket) + "/"
+	if key != "" {
+		target += url.PathEscape(key)
+	}
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, body, time.Now().UTC())
+	return req, nil
+}
+
+// sign adds an AWS Signature Version 4 authorization to a request
+func (s s3Store) sign(req *http.Request, body []byte, now time.Time) {
+	hash := func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}
+	mac := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+
+	region := s.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	stamp := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payload := hash(body)
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+
+	// The query is signed with spaces as %20, url.Values encodes them as +
+	query := strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20")
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		query,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payload,
+		"x-amz-date:" + stamp,
+		"",
+		signedHeaders,
+		payload,
+	}, "\n")
+	scope := day + "/" + region + "/s3/aws4_request"
+	toSign := strings.Join([]string{"AWS4-HMAC-SHA256", stamp, scope, hash([]byte(canonical))}, "\n")
+
+	key := mac([]byte("AWS4"+s.SecretKey), day)
+	key = mac(key, region)
+	key = mac(key, "s3")
+	key = mac(key, "aws4_request")
+	signature := hex.EncodeToString(mac(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.AccessKey, scope, signedHeaders, signature))
+}
+
+func (s s3Store) Put(name string, content []byte) error {
+	req, err := s.request(http.MethodPut, name, nil, content)
+	if err != nil {
+		return err
+	}
+	_, err = doRemote(req)
+	return err
+}
+
+func (s s3Store) Get(name string) ([]byte, error) {
+	req, err := s.request(http.MethodGet, name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return doRemote(req)
+}
+
+// List lists the objects of the bucket, following continuation tokens
+func (s s3Store) List() ([]string, error) {
+	var names []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := s.request(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := doRemote(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Contents []struct {
+				Key string
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		for _, object := range result.Contents {
+			names = append(names, object.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// configuredStore returns the configured remote storage, nil when there is none
+func (c *Canvas) configuredStore() remoteStore {
+	endpoint := c.prefs.String(prefRemoteEndpoint)
+	user, secret := c.prefs.String(prefRemoteUser), c.prefs.String(prefRemoteSecret)
+	switch c.prefs.String(prefRemoteType) {
+	case remoteWebDAV:
+		return webdavStore{URL: endpoint, User: user, Password: secret}
+	case remoteS3:
+		return s3Store{
+			Endpoint:  endpoint,
+			Bucket:    c.prefs.String(prefRemoteBucket),
+			Region:    c.prefs.String(prefRemoteRegion),
+			AccessKey: user,
+			SecretKey: secret,
+		}
+	case remoteWorkspace:
+		return workspaceStore{URL: endpoint, Org: c.prefs.String(prefRemoteBucket), Token: secret}
+	}
+	return nil
+}
+
+// remoteStoreOrSetup returns the remote storage, opening its settings when
+// none is configured yet
+func (c *Canvas) remoteStoreOrSetup() remoteStore {
+	store := c.configuredStore()
+	if store == nil {
+		c.showRemoteSettings()
+	}
+	return store
+}
+
+// saveToRemote saves the canvas to the remote storage under a name
+func (c *Canvas) saveToRemote() {
+	store := c.remoteStoreOrSetup()
+	if store == nil {
+		return
+	}
+	name := widget.NewEntry()
+	name.SetText("canvas.json")
+	dialog.ShowForm("Save to Remote", "Save", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Name", name),
+	}, func(ok bool) {
+		file := strings.TrimSpace(name.Text)
+		if !ok || file == "" {
+			return
+		}
+		if !strings.HasSuffix(file, ".json") {
+			file += ".json"
+		}
+		content, err := json.MarshalIndent(c.canvasFileData(), "", "    ")
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if err := store.Put(file, content); err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		c.markSaved()
+		dialog.ShowInformation("Success", "Canvas saved to "+file, c.window)
+	}, c.window)
+}
+
+// openFromRemote lists the canvases in the remote storage to open one
+func (c *Canvas) openFromRemote() {
+	store := c.remoteStoreOrSetup()
+	if store == nil {
+		return
+	}
+	names, err := store.List()
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	var canvases []string
+	for _, name := range names {
+		if strings.HasSuffix(name, ".json") {
+			canvases = append(canvases, name)
+		}
+	}
+	if len(canvases) == 0 {
+		dialog.ShowInformation("Open from Remote", "There are no canvases in the remote storage yet", c.window)
+		return
+	}
+
+	choice := widget.NewSelect(canvases, nil)
+	choice.SetSelectedIndex(0)
+	dialog.ShowForm("Open from Remote", "Open", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Canvas", choice),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		content, err := store.Get(choice.Selected)
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		data, err := readCanvasData(bytes.NewReader(content))
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		c.openCanvasData(choice.Selected, data, func() {
+			c.watchOpenFile(nil)
+		})
+	}, c.window)
+}
+
+// showRemoteSettings configures the WebDAV or S3 storage canvases are
+// saved to and opened from
+func (c *Canvas) showRemoteSettings() {
+	endpoint := widget.NewEntry()
+	endpoint.SetText(c.prefs.String(prefRemoteEndpoint))
+	bucket := widget.NewEntry()
+	bucket.SetText(c.prefs.String(prefRemoteBucket))
+	region := widget.NewEntry()
+	region.SetPlaceHolder(defaultS3Region)
+	region.SetText(c.prefs.String(prefRemoteRegion))
+	user := widget.NewEntry()
+	user.SetText(c.prefs.String(prefRemoteUser))
+	secret := widget.NewPasswordEntry()
+	secret.SetText(c.prefs.String(prefRemoteSecret))
+
+	kind := widget.NewRadioGroup([]string{remoteNone, remoteWebDAV, remoteS3, remoteWorkspace}, func(selected string) {
+		switch selected {
+		case remoteS3:
+			endpoint.SetPlaceHolder("https://minio.example.com")
+			bucket.Enable()
+			region.Enable()
+		case remoteWorkspace:
+			endpoint.SetPlaceHolder("https://canvas.example.com")
+			bucket.Enable()
+			region.Disable()
+		default:
+			endpoint.SetPlaceHolder("https://cloud.example.com/remote.php/dav/files/me/Canvases")
+			bucket.Disable()
+			region.Disable()
+		}
+	})
+	kind.Horizontal = true
+	kind.SetSelected(c.prefs.StringWithFallback(prefRemoteType, remoteNone))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Storage", kind),
+		widget.NewFormItem("Endpoint", endpoint),
+		widget.NewFormItem("Bucket / organization", bucket),
+		widget.NewFormItem("Region", region),
+		widget.NewFormItem("User / access key", user),
+		widget.NewFormItem("Password / secret / token", secret),
+	}
+	form := dialog.NewForm("Remote Storage", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		if kind.Selected != remoteNone && !strings.HasPrefix(endpoint.Text, "http://") && !strings.HasPrefix(endpoint.Text, "https://") {
+			dialog.ShowError(errors.New("the endpoint must be an http:// or https:// URL"), c.window)
+			return
+		}
+		c.prefs.SetString(prefRemoteType, kind.Selected)
+		c.prefs.SetString(prefRemoteEndpoint, strings.TrimSpace(endpoint.Text))
+		c.prefs.SetString(prefRemoteBucket, strings.TrimSpace(bucket.Text))
+		c.prefs.SetString(prefRemoteRegion, strings.TrimSpace(region.Text))
+		c.prefs.SetString(prefRemoteUser, user.Text)
+		c.prefs.SetString(prefRemoteSecret, secret.Text)
+	}, c.window)
+	form.Resize(fyne.NewSize(550, 0))
+	form.Show()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// confirmReplace runs an action that replaces the whole canvas with next,
+// first asking with a preview of what changes when there is unsaved work
+// that would be replaced
+func (c *Canvas) confirmReplace(action string, next CanvasData, apply func()) {
+	if !c.hasUnsavedChanges() {
+		apply()
+		return
+	}
+
+	message := widget.NewLabel(action + " replaces the whole canvas, which has unsaved changes. Edit > Undo brings them back.")
+	message.Wrapping = fyne.TextWrapWord
+	var preview fyne.CanvasObject = widget.NewLabel("The content stays the same.")
+	if diffs := diffCanvases(c.getCurrentData(), next); len(diffs) > 0 {
+		preview = container.NewVScroll(diffRichText(diffs))
+	}
+	legend := widget.NewLabel("Red lines are removed and green lines are added")
+
+	var confirm *dialog.CustomDialog
+	save := widget.NewButton("Save First...", func() {
+		confirm.Hide()
+		c.saveCanvasThen(apply)
+	})
+	replace := widget.NewButton("Replace", func() {
+		confirm.Hide()
+		apply()
+	})
+	replace.Importance = widget.DangerImportance
+	confirm = dialog.NewCustomWithoutButtons("Are you sure?", container.NewBorder(container.NewVBox(message, legend), nil, nil, nil, preview), c.window)
+	confirm.SetButtons([]fyne.CanvasObject{
+		widget.NewButton("Cancel", func() { confirm.Hide() }),
+		replace,
+		save,
+	})
+	confirm.Resize(fyne.NewSize(600, 500))
+	confirm.Show()
+}
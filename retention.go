@@ -0,0 +1,149 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// prefRetentionMax is the most versions kept, 0 keeps them all
+	prefRetentionMax = "retentionMax"
+	// prefRetentionThin thins out older versions by the retention tiers
+	prefRetentionThin = "retentionThin"
+)
+
+// retentionTier keeps one version per interval for versions up to an age
+type retentionTier struct {
+	Age      time.Duration
+	Interval time.Duration
+}
+
+// retentionTiers keep every version for an hour, hourly versions for a day,
+// daily versions for a month and weekly versions after that
+var retentionTiers = []retentionTier{
+	{Age: time.Hour, Interval: 0},
+	{Age: 24 * time.Hour, Interval: time.Hour},
+	{Age: 30 * 24 * time.Hour, Interval: 24 * time.Hour},
+	{Age: 0, Interval: 7 * 24 * time.Hour},
+}
+
+// retentionPolicy limits how much version history is kept
+type retentionPolicy struct {
+	MaxVersions int
+	Thin        bool
+}
+
+func (c *Canvas) retentionPolicy() retentionPolicy {
+	return retentionPolicy{
+		MaxVersions: c.prefs.Int(prefRetentionMax),
+		Thin:        c.prefs.Bool(prefRetentionThin),
+	}
+}
+
+// keepVersion reports whether a version is never pruned: named and tagged
+// versions were saved deliberately and comments are stored on versions
+func keepVersion(v Version) bool {
+	return v.Name != "" || len(v.Tags) > 0 || len(v.Comments) > 0
+}
+
+// retentionBucket returns the tier bucket a version of the given age falls
+// in, or "" when every version of that age is kept
+func retentionBucket(v Version, age time.Duration) string {
+	for i, tier := range retentionTiers {
+		if tier.Age != 0 && age >= tier.Age {
+			continue
+		}
+		if tier.Interval == 0 {
+			return ""
+		}
+		return v.branchName() + "/" + strconv.Itoa(i) + "/" + v.Timestamp.Truncate(tier.Interval).String()
+	}
+	return ""
+}
+
+// pruneVersions applies a retention policy to versions in the order they
+// were saved. Thinning keeps the newest version of each tier bucket per
+// branch, then the oldest versions are dropped beyond the maximum. Named and
+// tagged versions, versions with comments and the latest version of each
+// branch are always kept. The hashes of removed chained versions move to
+// the next version kept, so the hash chain still verifies.
+func pruneVersions(versions []Version, now time.Time, policy retentionPolicy) []Version {
+	keep := make([]bool, len(versions))
+	latest := make(map[string]bool)
+	buckets := make(map[string]bool)
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		var bucket string
+		if policy.Thin {
+			bucket = retentionBucket(v, now.Sub(v.Timestamp))
+		}
+		keep[i] = keepVersion(v) || !latest[v.branchName()] || bucket == "" || !buckets[bucket]
+		if bucket != "" {
+			buckets[bucket] = true
+		}
+		latest[v.branchName()] = true
+	}
+
+	if policy.MaxVersions > 0 {
+		kept := 0
+		for _, k := range keep {
+			if k {
+				kept++
+			}
+		}
+		// Drop the oldest versions that may be pruned until within the limit
+		var prunable []int
+		seen := make(map[string]bool)
+		for i := len(versions) - 1; i >= 0; i-- {
+			v := versions[i]
+			if keep[i] && !keepVersion(v) && seen[v.branchName()] {
+				prunable = append(prunable, i)
+			}
+			seen[v.branchName()] = true
+		}
+		sort.Ints(prunable)
+		for _, i := range prunable {
+			if kept <= policy.MaxVersions {
+				break
+			}
+			keep[i] = false
+			kept--
+		}
+	}
+
+	var pruned []Version
+	var removed []prunedVersion
+	for i, v := range versions {
+		if !keep[i] {
+			removed = append(removed, v.Pruned...)
+			if v.Hash != "" {
+				removed = append(removed, prunedVersion{Timestamp: v.Timestamp, PrevHash: v.PrevHash, Hash: v.Hash})
+			}
+			continue
+		}
+		if len(removed) > 0 {
+			v.Pruned = append(removed, v.Pruned...)
+			removed = nil
+		}
+		pruned = append(pruned, v)
+	}
+	return pruned
+}
+
+// retentionSetting edits the retention policy in the settings dialog
+func (c *Canvas) retentionSetting() fyne.CanvasObject {
+	thinCheck := widget.NewCheck("Keep hourly for a day, daily for a month", func(checked bool) {
+		c.prefs.SetBool(prefRetentionThin, checked)
+	})
+	thinCheck.SetChecked(c.prefs.Bool(prefRetentionThin))
+
+	maxEntry := c.intSettingEntry(prefRetentionMax, 0)
+
+	return container.NewVBox(thinCheck,
+		container.NewBorder(nil, nil, nil, widget.NewLabel("versions at most (0 = no limit)"), maxEntry))
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPruneVersionsToMaximum(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	versions := chainedHistory(10, start, time.Minute)
+	versions[2].Name = "Board review"
+
+	pruned := pruneVersions(versions, start.Add(time.Hour), retentionPolicy{MaxVersions: 3})
+	if len(pruned) != 3 {
+		t.Fatalf("kept %d versions, want 3", len(pruned))
+	}
+	if pruned[0].Name != "Board review" {
+		t.Error("the named version was pruned")
+	}
+	if pruned[len(pruned)-1].ID != versions[len(versions)-1].ID {
+		t.Error("the latest version was pruned")
+	}
+	if count := prunedCount(pruned); count != 7 {
+		t.Errorf("recorded %d pruned versions, want 7", count)
+	}
+	if _, problems := verifyVersionChain(pruned); len(problems) > 0 {
+		t.Errorf("the pruned chain does not verify: %v", problems)
+	}
+}
+
+func TestPruneVersionsThinsByAge(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	// Every 10 minutes for the last two days
+	versions := chainedHistory(288, now.Add(-48*time.Hour), 10*time.Minute)
+
+	pruned := pruneVersions(versions, now, retentionPolicy{Thin: true})
+	if len(pruned) >= len(versions) || len(pruned) < 24 {
+		t.Errorf("kept %d of %d versions, want hourly versions for older ones", len(pruned), len(versions))
+	}
+	if _, problems := verifyVersionChain(pruned); len(problems) > 0 {
+		t.Errorf("the thinned chain does not verify: %v", problems)
+	}
+
+	// Pruning again moves the records of pruned versions along
+	again := pruneVersions(pruned, now, retentionPolicy{MaxVersions: 5})
+	if prunedCount(again)+len(again) != len(versions) {
+		t.Errorf("%d kept and %d recorded, want all %d versions accounted for", len(again), prunedCount(again), len(versions))
+	}
+	if _, problems := verifyVersionChain(again); len(problems) > 0 {
+		t.Errorf("the chain pruned twice does not verify: %v", problems)
+	}
+}
+
+func TestVersionRemovedOutsideRetentionIsDetected(t *testing.T) {
+	versions := chainedHistory(3, time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC), time.Minute)
+	removed := append(append([]Version{}, versions[:1]...), versions[2:]...)
+	if _, problems := verifyVersionChain(removed); len(problems) == 0 {
+		t.Error("a version removed outside the retention policy went unnoticed")
+	}
+}
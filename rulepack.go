@@ -0,0 +1,281 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// prefRulePacks stores the installed rule packs in order of precedence
+const prefRulePacks = "rulePacks"
+
+// RulePack is a named, versioned set of validation scripts for a
+// methodology, installed from a file or URL. Packs are kept in order of
+// precedence: when enabled packs define a rule of the same name, the one
+// of the pack listed first applies, and your own scripts come before any
+// pack.
+type RulePack struct {
+	Name        string       `json:"name"`
+	Version     string       `json:"version"`
+	Description string       `json:"description,omitempty"`
+	Rules       []ScriptRule `json:"rules"`
+
+	// Source is the file or URL the pack was installed from, and Enabled
+	// whether its rules are checked. Neither is part of a pack file.
+	Source  string    `json:"source,omitempty"`
+	Enabled bool      `json:"enabled,omitempty"`
+	Updated time.Time `json:"updated,omitempty"`
+}
+
+// packedRule is a validation script with the pack it comes from, empty for
+// the user's own scripts
+type packedRule struct {
+	ScriptRule
+	Pack string
+}
+
+// label names a pack with its version
+func (p RulePack) label() string {
+	if p.Version == "" {
+		return p.Name
+	}
+	return p.Name + " v" + p.Version
+}
+
+// parseRulePack reads a pack file, checking every rule compiles
+func parseRulePack(data []byte) (RulePack, error) {
+	var pack RulePack
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return pack, err
+	}
+	if pack.Name == "" {
+		return pack, errors.New("the rule pack has no name")
+	}
+	for _, rule := range pack.Rules {
+		if _, err := compileScriptRule(rule.Expression); err != nil {
+			return pack, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+	}
+	pack.Source, pack.Enabled, pack.Updated = "", false, time.Time{}
+	return pack, nil
+}
+
+// fetchRulePack downloads a pack from a URL or reads it from a file URI
+func fetchRulePack(source string) (RulePack, error) {
+	var data []byte
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Get(source)
+		if err != nil {
+			return RulePack{}, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return RulePack{}, fmt.Errorf("server responded with %s", resp.Status)
+		}
+		if data, err = io.ReadAll(resp.Body); err != nil {
+			return RulePack{}, err
+		}
+	} else {
+		uri, err := parseLocation(source)
+		if err != nil {
+			return RulePack{}, err
+		}
+		if data, err = readURI(uri); err != nil {
+			return RulePack{}, err
+		}
+	}
+
+	pack, err := parseRulePack(data)
+	if err != nil {
+		return pack, err
+	}
+	pack.Source = source
+	pack.Updated = nowUTC()
+	return pack, nil
+}
+
+func (c *Canvas) loadRulePacks() []RulePack {
+	var packs []RulePack
+	if stored := c.prefs.String(prefRulePacks); stored != "" {
+		json.Unmarshal([]byte(stored), &packs)
+	}
+	return packs
+}
+
+func (c *Canvas) saveRulePacks(packs []RulePack) {
+	stored, err := json.Marshal(packs)
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	c.prefs.SetString(prefRulePacks, string(stored))
+	c.loadValidator()
+	c.refreshHealth()
+}
+
+// installRulePack adds a pack, or replaces the installed pack of the same
+// name keeping its place and whether it is enabled. It returns a note
+// on what changed.
+func installRulePack(packs []RulePack, pack RulePack) ([]RulePack, string) {
+	for i, installed := range packs {
+		if installed.Name == pack.Name {
+			pack.Enabled = installed.Enabled
+			packs[i] = pack
+			if installed.Version == pack.Version {
+				return packs, fmt.Sprintf("%s reinstalled", pack.label())
+			}
+			return packs, fmt.Sprintf("%s updated from v%s to v%s", pack.Name, installed.Version, pack.Version)
+		}
+	}
+	pack.Enabled = true
+	return append(packs, pack), fmt.Sprintf("%s installed with %d rules", pack.label(), len(pack.Rules))
+}
+
+// activeScriptRules lists the user's scripts followed by the rules of the
+// enabled packs in order of precedence, leaving out rules overridden by a
+// rule of the same name earlier in the list
+func activeScriptRules(own []ScriptRule, packs []RulePack) []packedRule {
+	var rules []packedRule
+	seen := make(map[string]bool)
+	add := func(rule ScriptRule, pack string) {
+		if seen[rule.Name] {
+			return
+		}
+		seen[rule.Name] = true
+		rules = append(rules, packedRule{rule, pack})
+	}
+	for _, rule := range own {
+		add(rule, "")
+	}
+	for _, pack := range packs {
+		if !pack.Enabled {
+			continue
+		}
+		for _, rule := range pack.Rules {
+			add(rule, pack.label())
+		}
+	}
+	return rules
+}
+
+// showRulePacks manages the installed rule packs: enabling them, their
+// precedence, updating them from their source and removing them
+func (c *Canvas) showRulePacks() {
+	packs := c.loadRulePacks()
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(packs) },
+		func() fyne.CanvasObject {
+			buttons := container.NewHBox(
+				widget.NewButtonWithIcon("", theme.MoveUpIcon(), nil),
+				widget.NewButtonWithIcon("", theme.MoveDownIcon(), nil),
+				widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), nil),
+				widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+			)
+			return container.NewBorder(nil, nil, nil, buttons, widget.NewCheck("Template", nil))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			check := row.Objects[0].(*widget.Check)
+			check.OnChanged = nil
+			check.SetText(fmt.Sprintf("%s (%d rules)", packs[id].label(), len(packs[id].Rules)))
+			check.SetChecked(packs[id].Enabled)
+			check.OnChanged = func(enabled bool) {
+				packs[id].Enabled = enabled
+				c.saveRulePacks(packs)
+			}
+
+			buttons := row.Objects[1].(*fyne.Container).Objects
+			move := func(to int) {
+				if to < 0 || to >= len(packs) {
+					return
+				}
+				packs[id], packs[to] = packs[to], packs[id]
+				c.saveRulePacks(packs)
+				list.Refresh()
+			}
+			buttons[0].(*widget.Button).OnTapped = func() { move(id - 1) }
+			buttons[1].(*widget.Button).OnTapped = func() { move(id + 1) }
+			buttons[2].(*widget.Button).OnTapped = func() {
+				pack, err := fetchRulePack(packs[id].Source)
+				if err != nil {
+					dialog.ShowError(err, c.window)
+					return
+				}
+				var note string
+				packs, note = installRulePack(packs, pack)
+				c.saveRulePacks(packs)
+				list.Refresh()
+				dialog.ShowInformation("Rule Packs", note, c.window)
+			}
+			buttons[3].(*widget.Button).OnTapped = func() {
+				dialog.ShowConfirm("Remove Rule Pack", "Remove \""+packs[id].label()+"\"?", func(remove bool) {
+					if !remove {
+						return
+					}
+					packs = append(packs[:id], packs[id+1:]...)
+					c.saveRulePacks(packs)
+					list.Refresh()
+				}, c.window)
+			}
+		},
+	)
+
+	install := func(source string) {
+		pack, err := fetchRulePack(source)
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		var note string
+		packs, note = installRulePack(packs, pack)
+		c.saveRulePacks(packs)
+		list.Refresh()
+		dialog.ShowInformation("Rule Packs", note, c.window)
+	}
+	fromFile := widget.NewButtonWithIcon("Install from File...", theme.FolderOpenIcon(), func() {
+		openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			reader.Close()
+			install(reader.URI().String())
+		}, c.window)
+		openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+		openDialog.Show()
+	})
+	fromURL := widget.NewButtonWithIcon("Install from URL...", theme.DownloadIcon(), func() {
+		urlEntry := widget.NewEntry()
+		urlEntry.SetPlaceHolder("https://example.com/lean-startup.json")
+		dialog.ShowForm("Install Rule Pack", "Install", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("URL", urlEntry),
+		}, func(ok bool) {
+			if ok {
+				install(strings.TrimSpace(urlEntry.Text))
+			}
+		}, c.window)
+	})
+
+	help := widget.NewLabel("Packs higher in the list take precedence when rules share a name.\nYour own validation scripts always come first.")
+	content := container.NewBorder(help, container.NewHBox(fromFile, fromURL), nil, nil, list)
+	packsDialog := dialog.NewCustom("Rule Packs", "Close", content, c.window)
+	packsDialog.Resize(fyne.NewSize(600, 400))
+	packsDialog.Show()
+}
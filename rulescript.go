@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// prefScriptRules stores the validation rules written as expressions
+const prefScriptRules = "scriptRules"
+
+// scriptRuleHelp lists what a rule expression can use
+const scriptRuleHelp = `Variables: text (the section), items (its lines), words (its word count),
+sections (text of every section by title).
+The rule passes when the expression is true, e.g. len(items) >= 3 && words <= 150
+or text contains "subscription" or sections["Channels"] != "".`
+
+// ScriptRule is a validation rule whose check is an expr expression over
+// the text of a section, so methodology-specific checks need no rebuild
+type ScriptRule struct {
+	Name       string `json:"name"`
+	Section    string `json:"section"`
+	Expression string `json:"expression"`
+	Message    string `json:"message"`
+}
+
+// scriptRuleEnv is what a rule expression is evaluated against
+type scriptRuleEnv struct {
+	Text     string            `expr:"text"`
+	Items    []string          `expr:"items"`
+	Words    int               `expr:"words"`
+	Sections map[string]string `expr:"sections"`
+}
+
+// compileScriptRule checks a rule expression and prepares it for running
+func compileScriptRule(expression string) (*vm.Program, error) {
+	return expr.Compile(expression, expr.Env(scriptRuleEnv{}), expr.AsBool())
+}
+
+// runScriptRule evaluates a compiled rule against a section of a canvas
+func runScriptRule(program *vm.Program, data CanvasData, section string) (bool, error) {
+	env := scriptRuleEnv{Sections: make(map[string]string)}
+	for _, content := range data.sections() {
+		env.Sections[content.Title] = content.Text
+	}
+	env.Text = env.Sections[section]
+	env.Items = sectionLines(env.Text)
+	env.Words = len(strings.Fields(env.Text))
+
+	passed, err := expr.Run(program, env)
+	if err != nil {
+		return false, err
+	}
+	return passed.(bool), nil
+}
+
+func (c *Canvas) loadScriptRules() []ScriptRule {
+	var rules []ScriptRule
+	if stored := c.prefs.String(prefScriptRules); stored != "" {
+		json.Unmarshal([]byte(stored), &rules)
+	}
+	return rules
+}
+
+func (c *Canvas) saveScriptRules(rules []ScriptRule) {
+	stored, err := json.Marshal(rules)
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	c.prefs.SetString(prefScriptRules, string(stored))
+	c.loadValidator()
+	c.refreshHealth()
+}
+
+// loadValidator sets up the rules of the canvas type, those of the
+// validation file and the validation scripts
+func (c *Canvas) loadValidator() {
+	c.validator = findCanvasType(c.canvasTypeID).NewValidator()
+	limits := c.wordLimitRules(c.validator)
+	if c.validationConfig != nil {
+		c.validationConfig.apply(c.validator, findCanvasType(c.canvasTypeID).ID)
+	}
+	c.validator.rules = append(c.validator.rules, limits...)
+	c.validator.rules = append(c.validator.rules, c.scriptValidationRules()...)
+}
+
+// scriptValidationRules turns the script rules and those of the enabled
+// rule packs into validation rules. Rules for sections the canvas does not
+// have pass, and a rule that fails to run is reported as not passing.
+func (c *Canvas) scriptValidationRules() []ValidationRule {
+	var rules []ValidationRule
+	for _, rule := range activeScriptRules(c.loadScriptRules(), c.canvasRulePacks(c.loadRulePacks())) {
+		program, err := compileScriptRule(rule.Expression)
+		if err != nil {
+			fyne.LogError("Skipping validation script "+rule.Name, err)
+			continue
+		}
+		section := rule.Section
+		rules = append(rules, ValidationRule{
+			Section: section,
+			Message: rule.Message,
+			Pack:    rule.Pack,
+			Check: func(c *Canvas) bool {
+				if c.sectionEntry(section) == nil {
+					return true
+				}
+				passed, err := runScriptRule(program, c.getCurrentData(), section)
+				return err == nil && passed
+			},
+		})
+	}
+	return rules
+}
+
+// showScriptRules lists the validation scripts to add, edit and remove them
+func (c *Canvas) showScriptRules() {
+	rules := c.loadScriptRules()
+
+	var list *widget.List
+	var editRule func(index int)
+	list = widget.NewList(
+		func() int { return len(rules) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.DeleteIcon(), nil), widget.NewLabel("Template"))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s — %s", rules[id].Name, rules[id].Section))
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				dialog.ShowConfirm("Remove Script", "Remove \""+rules[id].Name+"\"?", func(remove bool) {
+					if !remove {
+						return
+					}
+					rules = append(rules[:id], rules[id+1:]...)
+					c.saveScriptRules(rules)
+					list.Refresh()
+				}, c.window)
+			}
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		list.UnselectAll()
+		editRule(id)
+	}
+
+	// editRule edits the rule at index, or adds one when index is -1
+	editRule = func(index int) {
+		rule := ScriptRule{Section: "Value Proposition"}
+		if index >= 0 {
+			rule = rules[index]
+		}
+
+		var titles []string
+		for _, content := range c.getCurrentData().sections() {
+			titles = append(titles, content.Title)
+		}
+		name := widget.NewEntry()
+		name.SetText(rule.Name)
+		section := widget.NewSelectEntry(titles)
+		section.SetText(rule.Section)
+		expression := widget.NewMultiLineEntry()
+		expression.SetText(rule.Expression)
+		expression.SetPlaceHolder("len(items) >= 3")
+		expression.Validator = func(text string) error {
+			_, err := compileScriptRule(text)
+			return err
+		}
+		message := widget.NewEntry()
+		message.SetText(rule.Message)
+		message.SetPlaceHolder("Shown when the expression is false")
+
+		form := dialog.NewForm("Validation Script", "Save", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Name", name),
+			widget.NewFormItem("Section", section),
+			widget.NewFormItem("Expression", expression),
+			widget.NewFormItem("Message", message),
+			widget.NewFormItem("", widget.NewLabel(scriptRuleHelp)),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			rule := ScriptRule{
+				Name:       strings.TrimSpace(name.Text),
+				Section:    strings.TrimSpace(section.Text),
+				Expression: expression.Text,
+				Message:    strings.TrimSpace(message.Text),
+			}
+			if rule.Name == "" {
+				rule.Name = rule.Section
+			}
+			if index >= 0 {
+				rules[index] = rule
+			} else {
+				rules = append(rules, rule)
+			}
+			c.saveScriptRules(rules)
+			list.Refresh()
+		}, c.window)
+		form.Resize(fyne.NewSize(600, 0))
+		form.Show()
+	}
+
+	addButton := widget.NewButtonWithIcon("Add Script", theme.ContentAddIcon(), func() {
+		editRule(-1)
+	})
+
+	content := container.NewBorder(nil, addButton, nil, nil, list)
+	scripts := dialog.NewCustom("Validation Scripts", "Close", content, c.window)
+	scripts.Resize(fyne.NewSize(500, 300))
+	scripts.Show()
+}
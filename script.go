@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// speakingWordsPerMinute is the pace used to time the speaker script
+const speakingWordsPerMinute = 130
+
+// minimumStepDuration is the time given to a section even with little to say
+const minimumStepDuration = 30 * time.Second
+
+// scriptStep is a section of the speaker script in presentation order
+type scriptStep struct {
+	Section       string
+	Notes         string
+	TalkingPoints []string
+	Start         time.Duration
+	Duration      time.Duration
+}
+
+// speakerScript builds the presentation as a sequence of sections with
+// their presenter notes and talking points, timed by speaking pace
+func speakerScript(data CanvasData) []scriptStep {
+	var steps []scriptStep
+	var start time.Duration
+	for _, section := range data.sections() {
+		step := scriptStep{
+			Section:       section.Title,
+			Notes:         strings.TrimSpace(data.PresenterNotes[section.Title]),
+			TalkingPoints: sectionLines(section.Text),
+			Start:         start,
+		}
+
+		words := len(strings.Fields(step.Notes))
+		for _, point := range step.TalkingPoints {
+			words += len(strings.Fields(point))
+		}
+		step.Duration = (time.Duration(words) * time.Minute / speakingWordsPerMinute).Round(time.Second)
+		if step.Duration < minimumStepDuration {
+			step.Duration = minimumStepDuration
+		}
+
+		start += step.Duration
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// formatScriptTime formats a script offset as minutes and seconds
+func formatScriptTime(d time.Duration) string {
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func writeScriptMarkdown(w io.Writer, steps []scriptStep) error {
+	var b strings.Builder
+	b.WriteString("# Speaker Script\n")
+	for i, step := range steps {
+		fmt.Fprintf(&b, "\n## %d. %s (%s, %s)\n", i+1, step.Section, formatScriptTime(step.Start), formatScriptTime(step.Duration))
+		if step.Notes != "" {
+			b.WriteString("\n" + step.Notes + "\n")
+		}
+		if len(step.TalkingPoints) > 0 {
+			b.WriteString("\nTalking points:\n")
+			for _, point := range step.TalkingPoints {
+				b.WriteString("- " + point + "\n")
+			}
+		}
+	}
+	if len(steps) > 0 {
+		last := steps[len(steps)-1]
+		fmt.Fprintf(&b, "\nTotal time: %s\n", formatScriptTime(last.Start+last.Duration))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeScriptPDF writes the speaker script as an A4 portrait document
+func writeScriptPDF(w io.Writer, opts pdfOptions, steps []scriptStep) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "", opts.Font.Regular)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "B", opts.Font.Bold)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddPage()
+
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	width := pageWidth - left - right
+
+	pdf.SetFont(pdfFontFamily, "B", 20)
+	pdf.CellFormat(width, 12, "Speaker Script", "", 1, "L", false, 0, "")
+	for i, step := range steps {
+		pdf.Ln(4)
+		pdf.SetFont(pdfFontFamily, "B", 13)
+		pdf.CellFormat(width-30, 8, fmt.Sprintf("%d. %s", i+1, step.Section), "B", 0, "L", false, 0, "")
+		pdf.SetFont(pdfFontFamily, "", 10)
+		pdf.CellFormat(30, 8, formatScriptTime(step.Start)+" (+"+formatScriptTime(step.Duration)+")", "B", 1, "R", false, 0, "")
+		if step.Notes != "" {
+			pdf.SetFont(pdfFontFamily, "", 11)
+			pdf.MultiCell(width, 5.5, step.Notes, "", "", false)
+		}
+		pdf.SetFont(pdfFontFamily, "", 10)
+		for _, point := range step.TalkingPoints {
+			pdf.MultiCell(width, 5, "• "+point, "", "", false)
+		}
+	}
+	return pdf.Output(w)
+}
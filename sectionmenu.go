@@ -0,0 +1,160 @@
+package main
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// prefSnippets holds the user's text snippets for inserting into sections
+const prefSnippets = "snippets"
+
+// showSectionMenu opens the context menu of a section at the pointer
+func (c *Canvas) showSectionMenu(title string, entry *widget.Entry, at fyne.Position) {
+	var snippets []*fyne.MenuItem
+	for _, snippet := range c.prefs.StringList(prefSnippets) {
+		snippets = append(snippets, fyne.NewMenuItem(snippetLabel(snippet), func() {
+			c.insertSnippet(entry, snippet)
+		}))
+	}
+	if len(snippets) > 0 {
+		snippets = append(snippets, fyne.NewMenuItemSeparator())
+	}
+	snippets = append(snippets, fyne.NewMenuItem("New Snippet...", c.showNewSnippet))
+	insert := fyne.NewMenuItem("Insert Snippet", nil)
+	insert.ChildMenu = fyne.NewMenu("", snippets...)
+
+	lock := fyne.NewMenuItem("Lock", func() {
+		c.setSectionLocked(title, entry, !c.lockedSections[title])
+	})
+	lock.Checked = c.lockedSections[title]
+
+	clear := fyne.NewMenuItem("Clear", func() {
+		c.undoStack = append(c.undoStack, c.getCurrentData())
+		entry.SetText("")
+	})
+	clear.Icon = theme.ContentClearIcon()
+	clear.Disabled = entry.Disabled() || entry.Text == ""
+	insert.Disabled = entry.Disabled()
+
+	var formats []*fyne.MenuItem
+	for _, format := range sectionCopyFormats {
+		formats = append(formats, fyne.NewMenuItem(format, func() {
+			c.copySection(title, entry.Text, format)
+		}))
+	}
+	copyAs := fyne.NewMenuItem("Copy as", nil)
+	copyAs.ChildMenu = fyne.NewMenu("", formats...)
+	copyAs.Icon = theme.ContentCopyIcon()
+
+	external := fyne.NewMenuItem("Edit in External Editor", func() {
+		c.editExternally(title, entry)
+	})
+	external.Icon = theme.DocumentCreateIcon()
+	external.Disabled = entry.Disabled()
+
+	comment := fyne.NewMenuItem("Add Comment...", func() {
+		c.showComments(title)
+	})
+	comment.Icon = theme.MailComposeIcon()
+
+	history := fyne.NewMenuItem("View History", func() {
+		c.showSectionHistory(title)
+	})
+	history.Icon = theme.HistoryIcon()
+
+	link := fyne.NewMenuItem("Link Item to Canvas...", func() {
+		c.linkSectionItem(title, entry)
+	})
+
+	menu := fyne.NewMenu("", clear, copyAs, insert, external, fyne.NewMenuItemSeparator(), comment, history, fyne.NewMenuItemSeparator(), link, c.linkedCanvasesMenu(title), fyne.NewMenuItemSeparator(), lock)
+	widget.ShowPopUpMenuAtPosition(menu, c.window.Canvas(), at)
+}
+
+// snippetLabel is the first line of a snippet, shortened for a menu
+func snippetLabel(snippet string) string {
+	label, _, _ := strings.Cut(strings.TrimSpace(snippet), "\n")
+	if runes := []rune(label); len(runes) > 40 {
+		label = string(runes[:40]) + "…"
+	}
+	return label
+}
+
+// insertSnippet types a snippet into a section at the cursor
+func (c *Canvas) insertSnippet(entry *widget.Entry, snippet string) {
+	lines := strings.Split(entry.Text, "\n")
+	row := min(entry.CursorRow, len(lines)-1)
+	column := min(entry.CursorColumn, len([]rune(lines[row])))
+	offset := column
+	for _, line := range lines[:row] {
+		offset += len([]rune(line)) + 1
+	}
+
+	c.undoStack = append(c.undoStack, c.getCurrentData())
+	text := []rune(entry.Text)
+	entry.SetText(string(text[:offset]) + snippet + string(text[offset:]))
+
+	inserted := strings.Split(snippet, "\n")
+	entry.CursorRow = row + len(inserted) - 1
+	entry.CursorColumn = len([]rune(inserted[len(inserted)-1]))
+	if len(inserted) == 1 {
+		entry.CursorColumn += column
+	}
+	entry.Refresh()
+	c.window.Canvas().Focus(entry)
+}
+
+// showNewSnippet adds a snippet to the list offered by section menus
+func (c *Canvas) showNewSnippet() {
+	snippet := widget.NewMultiLineEntry()
+	snippet.SetPlaceHolder("Text to insert")
+	snippet.SetMinRowsVisible(4)
+	dialog.ShowForm("New Snippet", "Save", "Cancel", []*widget.FormItem{widget.NewFormItem("Snippet", snippet)}, func(ok bool) {
+		if !ok || strings.TrimSpace(snippet.Text) == "" {
+			return
+		}
+		c.prefs.SetStringList(prefSnippets, append(c.prefs.StringList(prefSnippets), snippet.Text))
+		c.setupMainMenu()
+	}, c.window)
+}
+
+// setSectionLocked stops or allows editing a section
+func (c *Canvas) setSectionLocked(title string, entry *widget.Entry, locked bool) {
+	c.lockedSections[title] = locked
+	if locked {
+		entry.Disable()
+	} else {
+		entry.Enable()
+	}
+}
+
+// showSectionHistory lists the saved versions that changed a section,
+// newest first, with what each of them changed
+func (c *Canvas) showSectionHistory(title string) {
+	var segments []widget.RichTextSegment
+	previous := ""
+	for _, version := range c.versions {
+		for _, section := range version.Data.sections() {
+			if section.Title != title || section.Text == previous {
+				continue
+			}
+			change := diffRichText([]sectionDiff{{Title: version.label(), Lines: diffLines(previous, section.Text)}})
+			segments = append(change.Segments, segments...)
+			previous = section.Text
+		}
+	}
+	if len(segments) == 0 {
+		dialog.ShowInformation("History of "+title, "No saved version changed this section", c.window)
+		return
+	}
+
+	text := widget.NewRichText(segments...)
+	text.Wrapping = fyne.TextWrapWord
+	history := dialog.NewCustom("History of "+title, "Close", container.NewVScroll(text), c.window)
+	history.Resize(fyne.NewSize(600, 500))
+	history.Show()
+}
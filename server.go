@@ -0,0 +1,966 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// The self-hosted server relays collaboration sessions and keeps the
+// workspaces of organizations: their members, a shared library of canvas
+// templates and rule packs, and the canvases of the team with a portfolio
+// dashboard. Members authenticate with a bearer token or sign in with the
+// identity provider of their organization, organizations are created with
+// the admin token of the server.
+
+// defaultServerData is the folder the server keeps workspaces in
+const defaultServerData = "business-canvas-server"
+
+// defaultServerAddr is the address the server listens on by default. Every
+// request is authenticated, so it listens on all interfaces.
+const defaultServerAddr = ":8765"
+
+// Roles of workspace members. Owners and admins manage members and the
+// shared libraries, members read the libraries and edit canvases.
+const (
+	roleOwner  = "owner"
+	roleAdmin  = "admin"
+	roleMember = "member"
+)
+
+// workspaceNamePattern limits the names of organizations, members and
+// library entries to what is safe in URLs and file names
+var workspaceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 ._@-]{0,63}$`)
+
+// Organization is a workspace shared by a team
+type Organization struct {
+	ID        string                     `json:"id"`
+	Name      string                     `json:"name"`
+	Members   []Member                   `json:"members"`
+	Templates map[string]json.RawMessage `json:"templates,omitempty"`
+	RulePacks map[string]RulePack        `json:"rulePacks,omitempty"`
+	Canvases  map[string]WorkspaceCanvas `json:"canvases,omitempty"`
+	SSO       *SSOConfig                 `json:"sso,omitempty"`
+}
+
+// Member is a person in an organization. Only hashes of their token and of
+// the sessions of their sign ins are kept. Members who came through single
+// sign-on have no token.
+type Member struct {
+	Name      string               `json:"name"`
+	Role      string               `json:"role"`
+	TokenHash string               `json:"tokenHash"`
+	SSO       bool                 `json:"sso,omitempty"`
+	Sessions  map[string]time.Time `json:"sessions,omitempty"`
+}
+
+// WorkspaceCanvas is a canvas file kept in a workspace, with the versions
+// it replaced
+type WorkspaceCanvas struct {
+	Content   json.RawMessage `json:"content"`
+	Updated   time.Time       `json:"updated"`
+	UpdatedBy string          `json:"updatedBy"`
+	Version   int             `json:"version,omitempty"`
+	Note      string          `json:"note,omitempty"`
+	History   []CanvasVersion `json:"history,omitempty"`
+}
+
+// CanvasVersion is an earlier version of a workspace canvas
+type CanvasVersion struct {
+	Version   int             `json:"version"`
+	Content   json.RawMessage `json:"content,omitempty"`
+	Updated   time.Time       `json:"updated"`
+	UpdatedBy string          `json:"updatedBy"`
+	Note      string          `json:"note,omitempty"`
+}
+
+// maxRequestBody bounds the forms and the JSON of requests other than
+// uploads of canvases and libraries
+const maxRequestBody = 1 << 20
+
+// maxCanvasHistory is how many earlier versions of a canvas are kept
+const maxCanvasHistory = 100
+
+// storeCanvas replaces a canvas of the organization, keeping the version it
+// replaces, and answers the new version number
+func (org *Organization) storeCanvas(name string, content []byte, by, note string) int {
+	if org.Canvases == nil {
+		org.Canvases = make(map[string]WorkspaceCanvas)
+	}
+	previous, ok := org.Canvases[name]
+	next := WorkspaceCanvas{Content: content, Updated: nowUTC(), UpdatedBy: by, Version: 1, Note: note}
+	if ok {
+		next.Version = previous.version() + 1
+		next.History = append(previous.History, CanvasVersion{
+			Version: previous.version(), Content: previous.Content,
+			Updated: previous.Updated, UpdatedBy: previous.UpdatedBy, Note: previous.Note,
+		})
+		if len(next.History) > maxCanvasHistory {
+			next.History = next.History[len(next.History)-maxCanvasHistory:]
+		}
+	}
+	org.Canvases[name] = next
+	return next.Version
+}
+
+// version is the version number of a canvas, stored before versions were
+// counted as the first
+func (c WorkspaceCanvas) version() int {
+	return max(c.Version, 1)
+}
+
+// canManage reports whether a member may manage members and libraries
+func (m Member) canManage() bool {
+	return m.Role == roleOwner || m.Role == roleAdmin
+}
+
+// authenticates reports whether one of the token hashes is the member's
+// token or a session that has not expired
+func (m Member) authenticates(hashes ...string) bool {
+	for _, hash := range hashes {
+		if subtle.ConstantTimeCompare([]byte(m.TokenHash), []byte(hash)) == 1 {
+			return true
+		}
+		if expires, ok := m.Sessions[hash]; ok && time.Now().Before(expires) {
+			return true
+		}
+	}
+	return false
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// workspaceServer serves the workspaces kept in a folder, one JSON file per
+// organization
+type workspaceServer struct {
+	mu         sync.Mutex
+	dir        string
+	adminToken string
+	publicURL  string
+	orgs       map[string]*Organization
+	ssoStates  map[string]ssoState
+	stateKey   []byte
+	rooms      *collabRooms
+	metrics    *serverMetrics
+
+	spOnce sync.Once
+	spKey  *rsa.PrivateKey
+	spCert *x509.Certificate
+	spErr  error
+}
+
+// openWorkspaceServer loads the organizations kept in dir. publicURL is
+// where browsers reach the server, for the callbacks of single sign-on.
+func openWorkspaceServer(dir, adminToken, publicURL string) (*workspaceServer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	stateKey := make([]byte, 32)
+	if _, err := rand.Read(stateKey); err != nil {
+		return nil, err
+	}
+	s := &workspaceServer{
+		dir:        dir,
+		adminToken: adminToken,
+		publicURL:  publicURL,
+		orgs:       make(map[string]*Organization),
+		ssoStates:  make(map[string]ssoState),
+		stateKey:   stateKey,
+		rooms:      newCollabRooms(),
+	}
+	s.metrics = newServerMetrics(collabGauge(s.rooms.peers), metricGauge{
+		Name: "business_canvas_organizations",
+		Help: "Organizations kept by the server.",
+		Value: func() float64 {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return float64(len(s.orgs))
+		},
+	}, metricGauge{
+		Name:  "business_canvas_sso_sessions",
+		Help:  "Sessions of single sign-on that have not expired.",
+		Value: func() float64 { return float64(s.liveSessions()) },
+	})
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var org Organization
+		if err := json.Unmarshal(content, &org); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		s.orgs[org.ID] = &org
+	}
+	return s, nil
+}
+
+// save writes an organization to its file, the caller holds the lock
+func (s *workspaceServer) save(org *Organization) error {
+	content, err := json.MarshalIndent(org, "", "    ")
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	err = writeLocalFile(filepath.Join(s.dir, org.ID+".json"), content)
+	s.metrics.observeSave(time.Since(start))
+	return err
+}
+
+// healthy reports whether the data folder can be reached
+func (s *workspaceServer) healthy() error {
+	_, err := os.Stat(s.dir)
+	return err
+}
+
+// handler routes the workspace API, the dashboards and the collaboration
+// sessions of the canvases
+func (s *workspaceServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/orgs", s.createOrg)
+	mux.HandleFunc("GET /api/backup", s.backupHandler)
+	mux.HandleFunc("POST /api/restore", s.restoreHandler)
+	mux.HandleFunc("GET /api/orgs/{org}/members", s.member(false, s.listMembers))
+	mux.HandleFunc("POST /api/orgs/{org}/members", s.member(true, s.addMember))
+	mux.HandleFunc("DELETE /api/orgs/{org}/members/{name}", s.member(true, s.removeMember))
+
+	mux.HandleFunc("GET /api/orgs/{org}/templates", s.member(false, s.listTemplates))
+	mux.HandleFunc("GET /api/orgs/{org}/templates/{name}", s.member(false, s.getTemplate))
+	mux.HandleFunc("PUT /api/orgs/{org}/templates/{name}", s.member(true, s.putTemplate))
+	mux.HandleFunc("DELETE /api/orgs/{org}/templates/{name}", s.member(true, s.deleteTemplate))
+
+	mux.HandleFunc("GET /api/orgs/{org}/rulepacks", s.member(false, s.listRulePacks))
+	mux.HandleFunc("GET /api/orgs/{org}/rulepacks/{name}", s.member(false, s.getRulePack))
+	mux.HandleFunc("PUT /api/orgs/{org}/rulepacks/{name}", s.member(true, s.putRulePack))
+	mux.HandleFunc("DELETE /api/orgs/{org}/rulepacks/{name}", s.member(true, s.deleteRulePack))
+
+	mux.HandleFunc("GET /api/orgs/{org}/canvases", s.member(false, s.listCanvases))
+	mux.HandleFunc("GET /api/orgs/{org}/canvases/{name}", s.member(false, s.getCanvas))
+	mux.HandleFunc("PUT /api/orgs/{org}/canvases/{name}", s.member(false, s.putCanvas))
+	mux.HandleFunc("PATCH /api/orgs/{org}/canvases/{name}", s.member(false, s.patchCanvasHandler))
+	mux.HandleFunc("GET /api/orgs/{org}/canvases/{name}/versions", s.member(false, s.listCanvasVersions))
+	mux.HandleFunc("GET /api/orgs/{org}/canvases/{name}/versions/{version}", s.member(false, s.getCanvasVersion))
+	mux.HandleFunc("GET /api/orgs/{org}/canvases/{name}/collab", s.collabHandler)
+
+	mux.HandleFunc("GET /api/orgs/{org}/sso", s.member(true, s.getSSO))
+	mux.HandleFunc("PUT /api/orgs/{org}/sso", s.member(true, s.putSSO))
+	mux.HandleFunc("DELETE /api/orgs/{org}/sso", s.member(true, s.deleteSSO))
+	mux.HandleFunc("GET /orgs/{org}/login", s.ssoLogin)
+	mux.HandleFunc("GET /orgs/{org}/sso/oidc", s.oidcCallback)
+	mux.HandleFunc("POST /orgs/{org}/sso/saml", s.samlACS)
+	mux.HandleFunc("GET /orgs/{org}/sso/saml/metadata", s.samlMetadata)
+
+	mux.HandleFunc("GET /orgs/{org}/signin", s.tokenSignInPage)
+	mux.HandleFunc("POST /orgs/{org}/signin", s.tokenSignIn)
+	mux.HandleFunc("GET /orgs/{org}/{$}", s.member(false, s.dashboard))
+	mux.HandleFunc("POST /orgs/{org}/boardpack", s.boardPack)
+	return s.metrics.instrument(mux, s.healthy)
+}
+
+// requestToken is the bearer token of a request. Tokens are never taken
+// from the address, where they would end up in logs and browser history.
+func requestToken(r *http.Request) string {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token
+}
+
+// member authenticates a member of the organization in the path and runs
+// handle with the lock held. Managing handlers need an owner or admin.
+func (s *workspaceServer) member(manage bool, handle func(http.ResponseWriter, *http.Request, *Organization, Member)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if org, m, ok := s.authenticate(w, r, manage); ok {
+			handle(w, r, org, m)
+		}
+	}
+}
+
+// authenticate finds the member of the organization in the path a request
+// comes from, answering the request when there is none. Browsers without a
+// session are sent to single sign-on where it is set up. The caller holds
+// the lock.
+func (s *workspaceServer) authenticate(w http.ResponseWriter, r *http.Request, manage bool) (*Organization, Member, bool) {
+	org := s.orgs[r.PathValue("org")]
+	if org == nil {
+		http.NotFound(w, r)
+		return nil, Member{}, false
+	}
+	hashes := []string{hashToken(requestToken(r))}
+	if cookie, err := r.Cookie(ssoCookie); err == nil {
+		hashes = append(hashes, hashToken(cookie.Value))
+	}
+	for _, m := range org.Members {
+		if !m.authenticates(hashes...) {
+			continue
+		}
+		if manage && !m.canManage() {
+			http.Error(w, "only owners and admins may do this", http.StatusForbidden)
+			return nil, Member{}, false
+		}
+		return org, m, true
+	}
+	if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/orgs/") {
+		page := "signin"
+		if org.SSO != nil {
+			page = "login"
+		}
+		http.Redirect(w, r, s.orgURL(org.ID)+page, http.StatusFound)
+		return nil, Member{}, false
+	}
+	http.Error(w, "a valid member token is required", http.StatusUnauthorized)
+	return nil, Member{}, false
+}
+
+// collabHandler relays the collaboration session of a canvas among the
+// members of its organization, one session per canvas
+func (s *workspaceServer) collabHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	org, _, ok := s.authenticate(w, r, false)
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	room := org.ID + "/" + r.PathValue("name")
+	websocket.Handler(func(conn *websocket.Conn) {
+		s.rooms.serve(room, conn)
+	}).ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// readBody reads a request body of at most 10 MB
+func readBody(r *http.Request) ([]byte, error) {
+	return io.ReadAll(io.LimitReader(r.Body, 10<<20))
+}
+
+// saveOrg stores an organization after a change, answering with an error
+// when that failed
+func (s *workspaceServer) saveOrg(w http.ResponseWriter, org *Organization) bool {
+	if err := s.save(org); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// isAdmin reports whether a request carries the admin token of the server
+func (s *workspaceServer) isAdmin(r *http.Request) bool {
+	return s.adminToken != "" && subtle.ConstantTimeCompare([]byte(requestToken(r)), []byte(s.adminToken)) == 1
+}
+
+// createOrg creates an organization with its owner, answering with the
+// owner's token
+func (s *workspaceServer) createOrg(w http.ResponseWriter, r *http.Request) {
+	if !s.isAdmin(r) {
+		http.Error(w, "the admin token of the server is required", http.StatusUnauthorized)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBody)
+	var req struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Owner string `json:"owner"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !workspaceNamePattern.MatchString(req.ID) || strings.ContainsAny(req.ID, " .") || !workspaceNamePattern.MatchString(req.Owner) {
+		http.Error(w, "id and owner must be names of letters, digits, - and _", http.StatusBadRequest)
+		return
+	}
+	token, err := newShareToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.orgs[req.ID] != nil {
+		http.Error(w, "the organization exists already", http.StatusConflict)
+		return
+	}
+	if req.Name == "" {
+		req.Name = req.ID
+	}
+	org := &Organization{ID: req.ID, Name: req.Name, Members: []Member{{Name: req.Owner, Role: roleOwner, TokenHash: hashToken(token)}}}
+	if !s.saveOrg(w, org) {
+		return
+	}
+	s.orgs[org.ID] = org
+	writeJSON(w, http.StatusCreated, map[string]string{"id": org.ID, "token": token})
+}
+
+func (s *workspaceServer) listMembers(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	type listed struct {
+		Name string `json:"name"`
+		Role string `json:"role"`
+	}
+	members := []listed{}
+	for _, m := range org.Members {
+		members = append(members, listed{m.Name, m.Role})
+	}
+	writeJSON(w, http.StatusOK, members)
+}
+
+// addMember adds a member, or gives an existing member a new role and
+// token, answering with the token
+func (s *workspaceServer) addMember(w http.ResponseWriter, r *http.Request, org *Organization, by Member) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBody)
+	var req struct {
+		Name string `json:"name"`
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Role == "" {
+		req.Role = roleMember
+	}
+	if !workspaceNamePattern.MatchString(req.Name) {
+		http.Error(w, "invalid member name", http.StatusBadRequest)
+		return
+	}
+	if req.Role != roleMember && req.Role != roleAdmin && req.Role != roleOwner {
+		http.Error(w, "role must be owner, admin or member", http.StatusBadRequest)
+		return
+	}
+	if req.Role == roleOwner && by.Role != roleOwner {
+		http.Error(w, "only owners may add owners", http.StatusForbidden)
+		return
+	}
+	token, err := newShareToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	member := Member{Name: req.Name, Role: req.Role, TokenHash: hashToken(token)}
+	replaced := false
+	for i, m := range org.Members {
+		if m.Name == req.Name {
+			org.Members[i], replaced = member, true
+		}
+	}
+	if !replaced {
+		org.Members = append(org.Members, member)
+	}
+	if s.saveOrg(w, org) {
+		writeJSON(w, http.StatusCreated, map[string]string{"name": member.Name, "role": member.Role, "token": token})
+	}
+}
+
+func (s *workspaceServer) removeMember(w http.ResponseWriter, r *http.Request, org *Organization, by Member) {
+	name := r.PathValue("name")
+	owners := 0
+	for _, m := range org.Members {
+		if m.Role == roleOwner {
+			owners++
+		}
+	}
+	for i, m := range org.Members {
+		if m.Name != name {
+			continue
+		}
+		if m.Role == roleOwner && (by.Role != roleOwner || owners == 1) {
+			http.Error(w, "owners are removed by another owner, and the last owner stays", http.StatusForbidden)
+			return
+		}
+		org.Members = append(org.Members[:i], org.Members[i+1:]...)
+		if s.saveOrg(w, org) {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// sortedKeys lists the names in a library
+func sortedKeys[V any](entries map[string]V) []string {
+	names := []string{}
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *workspaceServer) listTemplates(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	writeJSON(w, http.StatusOK, sortedKeys(org.Templates))
+}
+
+func (s *workspaceServer) getTemplate(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	template, ok := org.Templates[r.PathValue("name")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, http.StatusOK, template)
+}
+
+// putTemplate stores a canvas template, which must load in the app
+func (s *workspaceServer) putTemplate(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	name := r.PathValue("name")
+	body, err := readBody(r)
+	if err == nil && !workspaceNamePattern.MatchString(name) {
+		err = errors.New("invalid template name")
+	}
+	if err == nil {
+		_, err = parseCanvasTemplate(name+".json", body)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if org.Templates == nil {
+		org.Templates = make(map[string]json.RawMessage)
+	}
+	org.Templates[name] = body
+	if s.saveOrg(w, org) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *workspaceServer) deleteTemplate(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	delete(org.Templates, r.PathValue("name"))
+	if s.saveOrg(w, org) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *workspaceServer) listRulePacks(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	writeJSON(w, http.StatusOK, sortedKeys(org.RulePacks))
+}
+
+func (s *workspaceServer) getRulePack(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	pack, ok := org.RulePacks[r.PathValue("name")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, http.StatusOK, pack)
+}
+
+// putRulePack stores a rule pack, whose rules must compile
+func (s *workspaceServer) putRulePack(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	name := r.PathValue("name")
+	body, err := readBody(r)
+	if err == nil && !workspaceNamePattern.MatchString(name) {
+		err = errors.New("invalid rule pack name")
+	}
+	var pack RulePack
+	if err == nil {
+		pack, err = parseRulePack(body)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if org.RulePacks == nil {
+		org.RulePacks = make(map[string]RulePack)
+	}
+	pack.Updated = nowUTC()
+	org.RulePacks[name] = pack
+	if s.saveOrg(w, org) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *workspaceServer) deleteRulePack(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	delete(org.RulePacks, r.PathValue("name"))
+	if s.saveOrg(w, org) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *workspaceServer) listCanvases(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	writeJSON(w, http.StatusOK, sortedKeys(org.Canvases))
+}
+
+func (s *workspaceServer) getCanvas(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	canvas, ok := org.Canvases[r.PathValue("name")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(canvas.Content)
+}
+
+// putCanvas stores a canvas file, which must be a canvas
+func (s *workspaceServer) putCanvas(w http.ResponseWriter, r *http.Request, org *Organization, by Member) {
+	name := r.PathValue("name")
+	body, err := readBody(r)
+	if err == nil && !workspaceNamePattern.MatchString(name) {
+		err = errors.New("invalid canvas name")
+	}
+	if err == nil {
+		_, err = readCanvasData(strings.NewReader(string(body)))
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	org.storeCanvas(name, body, by.Name, "")
+	if s.saveOrg(w, org) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// patchCanvasHandler applies a JSON Patch document to a canvas, storing the
+// result as a new version when it is still a valid canvas
+func (s *workspaceServer) patchCanvasHandler(w http.ResponseWriter, r *http.Request, org *Organization, by Member) {
+	name := r.PathValue("name")
+	canvas, ok := org.Canvases[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	patch, err := readBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	patched, err := patchCanvas(canvas.Content, patch)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	note := "JSON patch"
+	if reason := r.Header.Get("X-Change-Note"); reason != "" {
+		note += ": " + reason
+	}
+	version := org.storeCanvas(name, patched, by.Name, note)
+	if s.saveOrg(w, org) {
+		writeJSON(w, http.StatusOK, map[string]int{"version": version})
+	}
+}
+
+// listCanvasVersions lists the versions of a canvas without their content,
+// the current one first
+func (s *workspaceServer) listCanvasVersions(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	canvas, ok := org.Canvases[r.PathValue("name")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	versions := []CanvasVersion{{Version: canvas.version(), Updated: canvas.Updated, UpdatedBy: canvas.UpdatedBy, Note: canvas.Note}}
+	for i := len(canvas.History) - 1; i >= 0; i-- {
+		version := canvas.History[i]
+		version.Content = nil
+		versions = append(versions, version)
+	}
+	writeJSON(w, http.StatusOK, versions)
+}
+
+// getCanvasVersion answers the content of a version of a canvas
+func (s *workspaceServer) getCanvasVersion(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	canvas, ok := org.Canvases[r.PathValue("name")]
+	number, err := strconv.Atoi(r.PathValue("version"))
+	if !ok || err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	content := canvas.Content
+	if number != canvas.version() {
+		content = nil
+		for _, version := range canvas.History {
+			if version.Version == number {
+				content = version.Content
+			}
+		}
+	}
+	if content == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(content)
+}
+
+// portfolioRow is a canvas on the portfolio dashboard
+type portfolioRow struct {
+	Name         string
+	Type         string
+	Completeness int
+	Health       int
+	HealthNotes  string
+	Words        int
+	Updated      time.Time
+	UpdatedBy    string
+}
+
+// portfolioRows summarizes the canvases of an organization, most recently
+// updated first
+func portfolioRows(org *Organization) []portfolioRow {
+	var rows []portfolioRow
+	for name, canvas := range org.Canvases {
+		row := portfolioRow{Name: name, Updated: canvas.Updated, UpdatedBy: canvas.UpdatedBy}
+		if data, err := readCanvasData(strings.NewReader(string(canvas.Content))); err == nil {
+			row.Type = data.canvasType().Name
+			sections := data.sections()
+			filled := 0
+			for _, section := range sections {
+				row.Words += len(strings.Fields(section.Text))
+				if strings.TrimSpace(section.Text) != "" {
+					filled++
+				}
+			}
+			if len(sections) > 0 {
+				row.Completeness = filled * 100 / len(sections)
+			}
+			// Scored like the status bar of the app, with the validation
+			// outcome saved with the canvas and the default thresholds
+			report := canvasHealth(data, func(string) int { return defaultStaleDays }, canvas.Updated, time.Now())
+			row.Health = report.Score()
+			var notes []string
+			for _, factor := range report.Factors {
+				notes = append(notes, fmt.Sprintf("%s %.0f%%", factor.Name, factor.Score*100))
+			}
+			row.HealthNotes = strings.Join(notes, ", ")
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Updated.After(rows[j].Updated)
+	})
+	return rows
+}
+
+// dashboardPage is the portfolio dashboard of an organization
+var dashboardPage = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Org.Name}} - Portfolio</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 6px; text-align: left; }
+.bar { background: #eee; width: 8em; height: 0.8em; }
+.bar div { background: #4a8; height: 100%; }
+</style>
+</head>
+<body>
+<h1>{{.Org.Name}}</h1>
+<h2>Canvases</h2>
+<form method="post" action="boardpack">
+<table>
+<tr><th></th><th>Canvas</th><th>Type</th><th>Completeness</th><th>Health</th><th>Words</th><th>Updated</th></tr>
+{{range .Rows}}<tr><td><input type="checkbox" name="canvas" value="{{.Name}}" checked></td><td>{{.Name}}</td><td>{{.Type}}</td><td><div class="bar"><div style="width: {{.Completeness}}%"></div></div> {{.Completeness}}%</td><td title="{{.HealthNotes}}">{{.Health}}/100</td><td>{{.Words}}</td><td>{{.Updated.Format "2006-01-02 15:04"}} by {{.UpdatedBy}}</td></tr>
+{{else}}<tr><td colspan="7">No canvases yet, save one to the workspace from the app</td></tr>
+{{end}}</table>
+{{if .Rows}}<p><input name="title" value="Board Pack"> <button type="submit">Export board pack (PDF)</button></p>{{end}}
+</form>
+<h2>Libraries</h2>
+<p>Templates: {{range $i, $t := .Templates}}{{if $i}}, {{end}}{{$t}}{{else}}none{{end}}</p>
+<p>Rule packs: {{range $i, $p := .RulePacks}}{{if $i}}, {{end}}{{$p}}{{else}}none{{end}}</p>
+<h2>Members</h2>
+<ul>{{range .Org.Members}}<li>{{.Name}} ({{.Role}})</li>{{end}}</ul>
+</body>
+</html>
+`))
+
+func (s *workspaceServer) dashboard(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	dashboardPage.Execute(w, map[string]any{
+		"Org":       org,
+		"Rows":      portfolioRows(org),
+		"Templates": sortedKeys(org.Templates),
+		"RulePacks": sortedKeys(org.RulePacks),
+	})
+}
+
+// signInPage asks a browser for the token of a member to open the pages of
+// an organization
+var signInPage = template.Must(template.New("signIn").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Org.Name}} - Sign in</title>
+<style>body { font-family: sans-serif; margin: 2em; } .error { color: #b00; }</style>
+</head>
+<body>
+<h1>Sign in to {{.Org.Name}}</h1>
+{{if .Failed}}<p class="error">This token belongs to no member of {{.Org.Name}}.</p>{{end}}
+<form method="post" action="signin">
+<p>Your member token, as entered in Tools &gt; Remote Storage... of the app:</p>
+<p><input type="password" name="token" size="40" autofocus> <button type="submit">Sign in</button></p>
+</form>
+{{if .Org.SSO}}<p><a href="login">Sign in with your company account</a></p>{{end}}
+</body>
+</html>
+`))
+
+func (s *workspaceServer) tokenSignInPage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.showSignIn(w, r, false)
+}
+
+// tokenSignIn signs a browser in with the token of a member, which the
+// browser then keeps in a cookie rather than in the address
+func (s *workspaceServer) tokenSignIn(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBody)
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	token := r.PostForm.Get("token")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org := s.orgs[r.PathValue("org")]
+	if org == nil {
+		http.NotFound(w, r)
+		return
+	}
+	for _, m := range org.Members {
+		if token != "" && m.authenticates(hashToken(token)) {
+			s.setSessionCookie(w, org.ID, token, time.Time{})
+			http.Redirect(w, r, s.orgURL(org.ID), http.StatusSeeOther)
+			return
+		}
+	}
+	s.showSignIn(w, r, true)
+}
+
+// showSignIn answers with the sign in page of the organization in the path.
+// The caller holds the lock.
+func (s *workspaceServer) showSignIn(w http.ResponseWriter, r *http.Request, failed bool) {
+	org := s.orgs[r.PathValue("org")]
+	if org == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if failed {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	signInPage.Execute(w, map[string]any{"Org": org, "Failed": failed})
+}
+
+// boardPack renders the canvases selected on the portfolio dashboard as a
+// board pack with their KPI dashboards and changelogs. The canvases are
+// copied under the lock and rendered after it is released, so other
+// requests go on meanwhile.
+func (s *workspaceServer) boardPack(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBody)
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Canvases are replaced rather than changed in place, so the copies
+	// stay as they were when taken
+	names := r.PostForm["canvas"]
+	selected := make(map[string]WorkspaceCanvas, len(names))
+	s.mu.Lock()
+	org, _, ok := s.authenticate(w, r, false)
+	if ok {
+		for _, name := range names {
+			if canvas, found := org.Canvases[name]; found {
+				selected[name] = canvas
+			}
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	var entries []boardPackEntry
+	for _, name := range names {
+		canvas, ok := selected[name]
+		if !ok {
+			continue
+		}
+		data, err := readCanvasData(strings.NewReader(string(canvas.Content)))
+		if err != nil {
+			continue
+		}
+		var versions []Version
+		for _, version := range canvas.History {
+			versions = append(versions, Version{Timestamp: version.Updated, Author: version.UpdatedBy, Note: version.Note})
+		}
+		versions = append(versions, Version{Timestamp: canvas.Updated, Author: canvas.UpdatedBy, Note: canvas.Note})
+		entries = append(entries, boardPackEntry{
+			Name:      strings.TrimSuffix(name, ".json"),
+			Data:      data,
+			Versions:  versions,
+			Updated:   canvas.Updated,
+			UpdatedBy: canvas.UpdatedBy,
+		})
+	}
+	if len(entries) == 0 {
+		http.Error(w, "select at least one canvas", http.StatusBadRequest)
+		return
+	}
+	title := strings.TrimSpace(r.PostForm.Get("title"))
+	if title == "" {
+		title = "Board Pack"
+	}
+
+	text := title
+	for _, entry := range entries {
+		text += entry.Name + pdfText(entry.Data, nil)
+	}
+	var pack bytes.Buffer
+	if err := writeBoardPack(&pack, pdfOptions{Font: builtinPDFFont().coverText(text)}, title, entries); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="board-pack.pdf"`)
+	w.Write(pack.Bytes())
+}
+
+// runServer serves workspaces and collaboration sessions until it fails
+func runServer(addr, dir, adminToken, publicURL string) error {
+	if publicURL == "" {
+		publicURL = "http://" + addr
+		if strings.HasPrefix(addr, ":") {
+			publicURL = "http://localhost" + addr
+		}
+	}
+	server, err := openWorkspaceServer(dir, adminToken, publicURL)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("serving workspaces from %s on http://%s, relaying the collaboration sessions of canvases on ws://%s/api/orgs/<org>/canvases/<name>/collab\n", dir, addr, addr)
+	if adminToken == "" {
+		fmt.Println("no admin token set, organizations cannot be created")
+	}
+	return http.ListenAndServe(addr, server.handler())
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// A server backup is a zip holding a manifest and every organization as the
+// server keeps it: members, single sign-on, libraries and canvases. The key
+// the server signs SAML requests with goes along, so identity providers keep
+// trusting a server restored elsewhere.
+
+// backupFormat is the version of the backup archive layout
+const backupFormat = 1
+
+// maxBackupSize bounds the archives the restore endpoint accepts
+const maxBackupSize = 1 << 30
+
+// errOrgExists is returned when a restore would replace an organization
+// without being asked to
+var errOrgExists = errors.New("the organization exists already")
+
+// backupManifest describes a backup
+type backupManifest struct {
+	Format        int       `json:"format"`
+	Created       time.Time `json:"created"`
+	Organizations []string  `json:"organizations"`
+}
+
+// backupIDs checks the organizations asked for, all of them when none are,
+// the caller holds the lock
+func (s *workspaceServer) backupIDs(ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return sortedKeys(s.orgs), nil
+	}
+	for _, id := range ids {
+		if s.orgs[id] == nil {
+			return nil, fmt.Errorf("there is no organization %q", id)
+		}
+	}
+	return ids, nil
+}
+
+// backup writes the organizations with the given IDs to a zip, the caller
+// holds the lock
+func (s *workspaceServer) backup(w io.Writer, ids []string) error {
+	archive := zip.NewWriter(w)
+	add := func(name string, content []byte) error {
+		f, err := archive.Create(name)
+		if err == nil {
+			_, err = f.Write(content)
+		}
+		return err
+	}
+	for _, id := range ids {
+		content, err := json.MarshalIndent(s.orgs[id], "", "    ")
+		if err != nil {
+			return err
+		}
+		if err := add("organizations/"+id+".json", content); err != nil {
+			return err
+		}
+	}
+	if key, err := os.ReadFile(filepath.Join(s.dir, samlCredentialsFile)); err == nil {
+		if err := add(samlCredentialsFile, key); err != nil {
+			return err
+		}
+	}
+	manifest, err := json.MarshalIndent(backupManifest{Format: backupFormat, Created: time.Now(), Organizations: ids}, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := add("manifest.json", manifest); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func decodeZipJSON(f *zip.File, v any) error {
+	reader, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return json.NewDecoder(reader).Decode(v)
+}
+
+// restore adds the organizations of a backup, replacing ones with the same
+// ID only when asked, and answers their IDs. The SAML key of the backup is
+// kept unless the server has one already. The caller holds the lock.
+func (s *workspaceServer) restore(content []byte, replace bool) ([]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("not a backup: %w", err)
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	if files["manifest.json"] == nil {
+		return nil, errors.New("not a backup: the manifest is missing")
+	}
+	var manifest backupManifest
+	if err := decodeZipJSON(files["manifest.json"], &manifest); err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+	if manifest.Format > backupFormat {
+		return nil, errors.New("the backup was made by a newer version of the server")
+	}
+
+	// Check the whole backup before changing anything
+	var orgs []*Organization
+	for _, id := range manifest.Organizations {
+		f := files["organizations/"+id+".json"]
+		if f == nil || !workspaceNamePattern.MatchString(id) {
+			return nil, fmt.Errorf("the backup lacks organization %q", id)
+		}
+		var org Organization
+		if err := decodeZipJSON(f, &org); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if org.ID != id {
+			return nil, fmt.Errorf("%s holds organization %q", f.Name, org.ID)
+		}
+		if s.orgs[id] != nil && !replace {
+			return nil, fmt.Errorf("%w: %s", errOrgExists, id)
+		}
+		orgs = append(orgs, &org)
+	}
+
+	for _, org := range orgs {
+		if err := s.save(org); err != nil {
+			return nil, err
+		}
+		s.orgs[org.ID] = org
+	}
+	if f := files[samlCredentialsFile]; f != nil {
+		path := filepath.Join(s.dir, samlCredentialsFile)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			reader, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			key, err := io.ReadAll(reader)
+			reader.Close()
+			if err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(path, key, 0o600); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return manifest.Organizations, nil
+}
+
+// backupHandler answers a backup of the organizations in ?org=, or of all
+func (s *workspaceServer) backupHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.isAdmin(r) {
+		http.Error(w, "the admin token of the server is required", http.StatusUnauthorized)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids, err := s.backupIDs(r.URL.Query()["org"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	var archive bytes.Buffer
+	start := time.Now()
+	if err := s.backup(&archive, ids); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.metrics.observeExport("backup", time.Since(start))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="business-canvas-backup-`+time.Now().Format("2006-01-02")+`.zip"`)
+	w.Write(archive.Bytes())
+}
+
+// restoreHandler restores a backup sent as the body, replacing existing
+// organizations with ?replace=true
+func (s *workspaceServer) restoreHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.isAdmin(r) {
+		http.Error(w, "the admin token of the server is required", http.StatusUnauthorized)
+		return
+	}
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBackupSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids, err := s.restore(content, r.URL.Query().Get("replace") == "true")
+	if errors.Is(err, errOrgExists) {
+		http.Error(w, err.Error()+", restore with ?replace=true to overwrite it", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"restored": ids})
+}
+
+// runBackup writes a backup of the data folder of a server:
+//
+//	business-canvas backup --data ./workspaces --org acme backup.zip
+func runBackup(args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	dir := flags.String("data", defaultServerData, "folder the server keeps workspaces in")
+	var orgs stringList
+	flags.Var(&orgs, "org", "organization to back up (repeatable, all by default)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("give the file to write the backup to")
+	}
+	s, err := openWorkspaceServer(*dir, "", "")
+	if err != nil {
+		return err
+	}
+	ids, err := s.backupIDs(orgs)
+	if err != nil {
+		return err
+	}
+	var archive bytes.Buffer
+	if err := s.backup(&archive, ids); err != nil {
+		return err
+	}
+	if err := writeLocalFile(flags.Arg(0), archive.Bytes()); err != nil {
+		return err
+	}
+	fmt.Printf("backed up %d organizations to %s\n", len(ids), flags.Arg(0))
+	return nil
+}
+
+// runRestore restores a backup into the data folder of a server, which
+// should not be running as it reads the folder only on start:
+//
+//	business-canvas restore --data ./workspaces backup.zip
+func runRestore(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	dir := flags.String("data", defaultServerData, "folder the server keeps workspaces in")
+	replace := flags.Bool("replace", false, "overwrite organizations that exist already")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("give the backup file to restore")
+	}
+	content, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	s, err := openWorkspaceServer(*dir, "", "")
+	if err != nil {
+		return err
+	}
+	ids, err := s.restore(content, *replace)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("restored %d organizations to %s\n", len(ids), *dir)
+	return nil
+}
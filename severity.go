@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Every section with failing rules gets a marker in its header, colored and
+// with the icon of its most severe issue, and the status bar counts the
+// issues of the canvas by severity. Rules without a severity are warnings.
+
+// severityRank orders severities, the most severe first
+func severityRank(severity string) int {
+	switch severity {
+	case severityError:
+		return 0
+	case severityWarning:
+		return 1
+	}
+	return 2
+}
+
+// severityImportance is the color of a severity
+func severityImportance(severity string) widget.Importance {
+	switch severity {
+	case severityError:
+		return widget.DangerImportance
+	case severityWarning:
+		return widget.WarningImportance
+	}
+	return widget.HighImportance
+}
+
+// severityIcon is the icon of a severity
+func severityIcon(severity string) fyne.Resource {
+	switch severity {
+	case severityError:
+		return theme.ErrorIcon()
+	case severityWarning:
+		return theme.WarningIcon()
+	}
+	return theme.InfoIcon()
+}
+
+// sortBySeverity orders results with the most severe first, keeping the
+// order of the rules otherwise
+func sortBySeverity(results []ValidationResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return severityRank(results[i].Severity) < severityRank(results[j].Severity)
+	})
+}
+
+// severityCounts words how many issues there are of each severity
+func severityCounts(results []ValidationResult) string {
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Severity]++
+	}
+	var parts []string
+	if n := counts[severityError]; n > 0 {
+		parts = append(parts, plural(n, "error"))
+	}
+	if n := counts[severityWarning]; n > 0 {
+		parts = append(parts, plural(n, "warning"))
+	}
+	if n := counts[severityInfo]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d info", n))
+	}
+	if len(parts) == 0 {
+		return "No issues"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// plural counts a noun
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// validationMarker is the marker of the issues of a section, hidden while
+// its rules pass
+func (c *Canvas) validationMarker(section string) *hintButton {
+	marker := newHintButton(c.tooltips, "", "", nil, func() {
+		c.showSectionIssues(section)
+	})
+	c.validationMarks[section] = marker
+	c.refreshValidationMarker(section, c.validator.Validate(c))
+	return marker
+}
+
+// refreshValidationMarker shows the issues of a section on its marker
+func (c *Canvas) refreshValidationMarker(section string, results []ValidationResult) {
+	marker := c.validationMarks[section]
+	if marker == nil {
+		return
+	}
+	var issues []ValidationResult
+	for _, result := range results {
+		if result.Section == section {
+			issues = append(issues, result)
+		}
+	}
+	if len(issues) == 0 {
+		marker.Hide()
+		return
+	}
+	sortBySeverity(issues)
+	var tip []string
+	for _, issue := range issues {
+		tip = append(tip, issue.Message)
+	}
+	marker.tip = strings.Join(tip, "\n")
+	marker.Icon = severityIcon(issues[0].Severity)
+	marker.Importance = severityImportance(issues[0].Severity)
+	marker.Text = ""
+	if len(issues) > 1 {
+		marker.Text = fmt.Sprintf("%d", len(issues))
+	}
+	marker.Show()
+	marker.Refresh()
+}
+
+// refreshValidation runs the rules and updates the section markers and the
+// issue counts in the status bar
+func (c *Canvas) refreshValidation() {
+	results := c.validator.Validate(c)
+	c.lastValidation = results
+	for section := range c.validationMarks {
+		c.refreshValidationMarker(section, results)
+	}
+	if c.validationButton == nil {
+		return
+	}
+	sortBySeverity(results)
+	c.validationButton.Text = severityCounts(results)
+	c.validationButton.Icon = theme.ConfirmIcon()
+	c.validationButton.Importance = widget.LowImportance
+	if len(results) > 0 {
+		c.validationButton.Icon = severityIcon(results[0].Severity)
+		c.validationButton.Importance = severityImportance(results[0].Severity)
+	}
+	c.validationButton.Refresh()
+}
+
+// showSectionIssues lists the failing rules of a section
+func (c *Canvas) showSectionIssues(section string) {
+	var message string
+	results := c.validator.Validate(c)
+	sortBySeverity(results)
+	for _, result := range results {
+		if result.Section == section {
+			message += fmt.Sprintf("• %s (%s)\n", result.Message, result.Severity)
+		}
+	}
+	if message == "" {
+		message = "All rules of this section pass"
+	}
+	dialog.ShowInformation(section, message, c.window)
+}
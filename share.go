@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// defaultShareAddr is the address the canvas is shared on by default
+const defaultShareAddr = ":8766"
+
+// shareRefreshSeconds is how often a shared page reloads to follow edits
+const shareRefreshSeconds = 5
+
+// sharePage is the read-only view of a shared canvas
+var sharePage = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html{{with .Lang}} lang="{{.}}"{{end}}>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>{{.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+.canvas { display: grid; grid-template-columns: repeat(auto-fill, minmax(16em, 1fr)); gap: 8px; }
+.section { border: 1px solid #ccc; padding: 8px; }
+.section h2 { font-size: 1em; margin: 0 0 0.5em; }
+pre { white-space: pre-wrap; margin: 0; font-family: inherit; }
+footer { color: #666; margin-top: 2em; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<div class="canvas">
+{{range .Sections}}<div class="section"><h2>{{.Title}}</h2><pre>{{.Text}}</pre></div>
+{{end}}</div>
+<footer><small>Updated {{.Updated.Format "2006-01-02 15:04:05"}}{{with .Stats}} | {{.}}{{end}}</small></footer>
+</body>
+</html>
+`))
+
+// shareServer serves a read-only view of the canvas over HTTP. The app
+// pushes the canvas to it on every edit, so requests never touch widgets.
+type shareServer struct {
+	mu      sync.Mutex
+	data    CanvasData
+	stats   string
+	updated time.Time
+	token   string // required as ?token= when not empty
+	addr    string
+	server  *http.Server
+}
+
+// listen serves the canvas on an address
+func (s *shareServer) listen(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.addr = listener.Addr().String()
+	s.server = &http.Server{Handler: s}
+	go s.server.Serve(listener)
+	return nil
+}
+
+// update replaces the canvas being shared
+func (s *shareServer) update(data CanvasData, stats string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = withoutPresenterNotes(data)
+	s.stats = stats
+	s.updated = time.Now()
+}
+
+func (s *shareServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if s.token != "" && subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(s.token)) != 1 {
+		http.Error(w, "a valid share token is required", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	data, stats, updated := s.data, s.stats, s.updated
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	sharePage.Execute(w, map[string]interface{}{
+		"Name":     data.canvasType().Name,
+		"Sections": data.sections(),
+		"Stats":    stats,
+		"Updated":  updated,
+		"Refresh":  shareRefreshSeconds,
+		"Lang":     data.language(),
+	})
+}
+
+// url returns the address colleagues open, on the LAN address of this
+// machine when listening on every interface
+func (s *shareServer) url() string {
+	host, port, err := net.SplitHostPort(s.addr)
+	if err != nil {
+		return "http://" + s.addr + "/"
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = lanAddress()
+	}
+	url := fmt.Sprintf("http://%s/", net.JoinHostPort(host, port))
+	if s.token != "" {
+		url += "?token=" + s.token
+	}
+	return url
+}
+
+// close stops serving the canvas
+func (s *shareServer) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.server.Shutdown(ctx)
+}
+
+// lanAddress returns the first private IPv4 address of this machine, or
+// localhost when there is none
+func lanAddress() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "localhost"
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.IsPrivate() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	return "localhost"
+}
+
+// newShareToken returns a random token for a shared canvas
+func newShareToken() (string, error) {
+	token := make([]byte, 12)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// refreshShare pushes the canvas to the share server after an edit
+func (c *Canvas) refreshShare() {
+	if c.share == nil {
+		return
+	}
+	c.share.update(c.getCurrentData(), c.canvasStats().summary())
+}
+
+// stopShare stops sharing the canvas
+func (c *Canvas) stopShare() {
+	if c.share == nil {
+		return
+	}
+	c.share.close()
+	c.share = nil
+}
+
+// showShare starts sharing the canvas read-only over HTTP, or shows the
+// address of the current share
+func (c *Canvas) showShare() {
+	var share *dialog.CustomDialog
+
+	if c.share != nil {
+		url := c.share.url()
+		link := widget.NewEntry()
+		link.SetText(url)
+		copyLink := widget.NewButton("Copy", func() {
+			c.window.Clipboard().SetContent(url)
+		})
+		stop := widget.NewButton("Stop Sharing", func() {
+			c.stopShare()
+			share.Hide()
+		})
+		content := container.NewVBox(
+			widget.NewLabel("Colleagues on your network can view the canvas at:"),
+			container.NewBorder(nil, nil, nil, copyLink, link),
+			stop,
+		)
+		share = dialog.NewCustom("Share", "Close", content, c.window)
+		share.Resize(fyne.NewSize(500, 0))
+		share.Show()
+		return
+	}
+
+	addr := widget.NewEntry()
+	addr.SetText(defaultShareAddr)
+	requireToken := widget.NewCheck("Require an access token", nil)
+	requireToken.SetChecked(true)
+	start := widget.NewButton("Start Sharing", func() {
+		server := &shareServer{}
+		if requireToken.Checked {
+			token, err := newShareToken()
+			if err != nil {
+				dialog.ShowError(err, c.window)
+				return
+			}
+			server.token = token
+		}
+		if err := server.listen(addr.Text); err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		c.share = server
+		c.refreshShare()
+		share.Hide()
+		c.showShare()
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel("Share a read-only view of the canvas that follows your edits."),
+		widget.NewForm(widget.NewFormItem("Address", addr)),
+		requireToken,
+		start,
+	)
+	share = dialog.NewCustom("Share", "Close", content, c.window)
+	share.Resize(fyne.NewSize(500, 0))
+	share.Show()
+}
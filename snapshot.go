@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// prefSnapshotChars is how many characters a section has to change by
+	// since the last version before one is saved automatically
+	prefSnapshotChars = "snapshotChars"
+
+	defaultSnapshotChars = 200
+)
+
+// changedChars estimates how many characters differ between two texts as
+// the length of what remains once their common prefix and suffix are
+// removed, which stays cheap enough to run on every keystroke
+func changedChars(a, b string) int {
+	from, to := []rune(a), []rune(b)
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix &&
+		from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+	return max(len(from), len(to)) - prefix - suffix
+}
+
+func (c *Canvas) snapshotChars() int {
+	return c.prefs.IntWithFallback(prefSnapshotChars, defaultSnapshotChars)
+}
+
+// resetSnapshotBase makes the current section texts the baseline that
+// changes are measured against
+func (c *Canvas) resetSnapshotBase() {
+	c.snapshotBase = make(map[string]string)
+	for _, section := range c.getCurrentData().sections() {
+		c.snapshotBase[section.Title] = section.Text
+	}
+}
+
+// snapshotOnChange saves a version when a section has changed by more than
+// the configured number of characters since the last one, so important
+// edits are not lost between auto-save ticks
+func (c *Canvas) snapshotOnChange(section, text string) {
+	threshold := c.snapshotChars()
+	if threshold <= 0 || c.snapshotBase == nil || c.settingData {
+		return
+	}
+	if changedChars(c.snapshotBase[section], text) <= threshold {
+		return
+	}
+	c.saveCurrentVersion()
+	c.versions[len(c.versions)-1].Note = "Automatic snapshot after changes to " + section
+}
+
+// intSettingEntry edits a non-negative whole number preference
+func (c *Canvas) intSettingEntry(key string, fallback int) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetText(strconv.Itoa(c.prefs.IntWithFallback(key, fallback)))
+	entry.Validator = func(text string) error {
+		_, err := strconv.Atoi(strings.TrimSpace(text))
+		return err
+	}
+	entry.OnChanged = func(text string) {
+		if value, err := strconv.Atoi(strings.TrimSpace(text)); err == nil && value >= 0 {
+			c.prefs.SetInt(key, value)
+		}
+	}
+	return entry
+}
+
+// snapshotSetting edits the automatic snapshot threshold in the settings
+// dialog
+func (c *Canvas) snapshotSetting() fyne.CanvasObject {
+	entry := c.intSettingEntry(prefSnapshotChars, defaultSnapshotChars)
+	return container.NewBorder(nil, nil, nil, widget.NewLabel("characters changed (0 = off)"), entry)
+}
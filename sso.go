@@ -0,0 +1,868 @@
+package main
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/crewjam/saml"
+)
+
+// Organizations can let their people sign in with the identity provider of
+// the company over OpenID Connect or SAML. Signing in makes the person a
+// member with the role their groups map to and gives them a session token,
+// which works like a member token until it expires. Every sign in takes the
+// role from the groups again, so access follows the corporate directory.
+
+// Protocols of single sign-on
+const (
+	ssoOIDC = "oidc"
+	ssoSAML = "saml"
+)
+
+// ssoSessionLifetime is how long a sign in lasts, so a person removed from
+// their groups loses access by the next working day
+const ssoSessionLifetime = 12 * time.Hour
+
+// ssoCookie carries the session of a browser on the dashboard
+const ssoCookie = "business-canvas-session"
+
+// ssoStateCookie ties a sign in over OpenID Connect to the browser that
+// started it, so a callback cannot be replayed into another browser
+const ssoStateCookie = "business-canvas-sso-state"
+
+// samlCredentialsFile keeps the key and certificate of the server as a SAML
+// service provider in the data folder
+const samlCredentialsFile = "saml-sp.pem"
+
+// ssoClient talks to identity providers
+var ssoClient = &http.Client{Timeout: 30 * time.Second}
+
+// SSOConfig connects an organization to its identity provider
+type SSOConfig struct {
+	Protocol     string `json:"protocol"`
+	Issuer       string `json:"issuer,omitempty"`
+	ClientID     string `json:"clientId,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	MetadataURL  string `json:"metadataUrl,omitempty"`
+	// NameClaim and GroupsClaim are the claims or attributes holding the
+	// member name and the groups of a person
+	NameClaim   string `json:"nameClaim,omitempty"`
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// GroupRoles maps groups of the identity provider to roles. People in
+	// none of them get DefaultRole, or are turned away when it is empty.
+	GroupRoles  map[string]string `json:"groupRoles"`
+	DefaultRole string            `json:"defaultRole,omitempty"`
+}
+
+// validRole reports whether role is a role of workspace members
+func validRole(role string) bool {
+	return role == roleOwner || role == roleAdmin || role == roleMember
+}
+
+// roleRank orders roles so the strongest one of several groups wins
+func roleRank(role string) int {
+	switch role {
+	case roleOwner:
+		return 3
+	case roleAdmin:
+		return 2
+	case roleMember:
+		return 1
+	}
+	return 0
+}
+
+// check reports what keeps the configuration from working
+func (c SSOConfig) check() error {
+	switch c.Protocol {
+	case ssoOIDC:
+		if c.Issuer == "" || c.ClientID == "" {
+			return errors.New("OpenID Connect needs an issuer and a client ID")
+		}
+	case ssoSAML:
+		if c.MetadataURL == "" {
+			return errors.New("SAML needs the metadata URL of the identity provider")
+		}
+	default:
+		return errors.New(`protocol must be "oidc" or "saml"`)
+	}
+	for group, role := range c.GroupRoles {
+		if !validRole(role) {
+			return fmt.Errorf("group %s: role must be owner, admin or member", group)
+		}
+	}
+	if c.DefaultRole != "" && !validRole(c.DefaultRole) {
+		return errors.New("default role must be owner, admin or member")
+	}
+	return nil
+}
+
+// role is the strongest role the groups of a person map to
+func (c SSOConfig) role(groups []string) string {
+	role := c.DefaultRole
+	for _, group := range groups {
+		if mapped := c.GroupRoles[group]; roleRank(mapped) > roleRank(role) {
+			role = mapped
+		}
+	}
+	return role
+}
+
+func (c SSOConfig) nameClaim() string {
+	if c.NameClaim == "" && c.Protocol == ssoOIDC {
+		return "email"
+	}
+	return c.NameClaim
+}
+
+func (c SSOConfig) groupsClaim() string {
+	if c.GroupsClaim == "" {
+		return "groups"
+	}
+	return c.GroupsClaim
+}
+
+// ssoIdentity is who the identity provider says signed in
+type ssoIdentity struct {
+	Name   string
+	Groups []string
+}
+
+// ssoState is a sign in on its way through the identity provider
+type ssoState struct {
+	Org       string
+	Nonce     string
+	RequestID string
+	Expires   time.Time
+}
+
+// orgURL is the public address of the pages of an organization
+func (s *workspaceServer) orgURL(org string) string {
+	return strings.TrimSuffix(s.publicURL, "/") + "/orgs/" + url.PathEscape(org) + "/"
+}
+
+// ssoConfig is the single sign-on of an organization, if it has one
+func (s *workspaceServer) ssoConfig(org string) (SSOConfig, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if o := s.orgs[org]; o != nil && o.SSO != nil {
+		return *o.SSO, true
+	}
+	return SSOConfig{}, false
+}
+
+func (s *workspaceServer) addSSOState(key string, state ssoState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, pending := range s.ssoStates {
+		if time.Now().After(pending.Expires) {
+			delete(s.ssoStates, k)
+		}
+	}
+	s.ssoStates[key] = state
+}
+
+// takeSSOState ends a sign in started by this server for an organization
+func (s *workspaceServer) takeSSOState(key, org string) (ssoState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.ssoStates[key]
+	delete(s.ssoStates, key)
+	return state, ok && state.Org == org && time.Now().Before(state.Expires)
+}
+
+// liveSessions counts the sessions of single sign-on that have not expired
+func (s *workspaceServer) liveSessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	live := 0
+	for _, org := range s.orgs {
+		for _, m := range org.Members {
+			for _, expires := range m.Sessions {
+				if time.Now().Before(expires) {
+					live++
+				}
+			}
+		}
+	}
+	return live
+}
+
+// signState signs the state of a sign in for the cookie of the browser
+func (s *workspaceServer) signState(key string) string {
+	mac := hmac.New(sha256.New, s.stateKey)
+	mac.Write([]byte(key))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// setStateCookie remembers in the browser the sign in it started. SAML
+// answers with a cross-site POST that does not carry the cookie, so it
+// rests on the request ID the response must answer instead.
+func (s *workspaceServer) setStateCookie(w http.ResponseWriter, org, key string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookie,
+		Value:    s.signState(key),
+		Path:     "/orgs/" + org + "/sso/",
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(s.publicURL, "https:"),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// stateCookieMatches tells whether the browser started the sign in of the
+// state, clearing the cookie
+func (s *workspaceServer) stateCookieMatches(w http.ResponseWriter, r *http.Request, key string) bool {
+	cookie, err := r.Cookie(ssoStateCookie)
+	http.SetCookie(w, &http.Cookie{Name: ssoStateCookie, Path: "/orgs/" + r.PathValue("org") + "/sso/", MaxAge: -1})
+	return err == nil && hmac.Equal([]byte(cookie.Value), []byte(s.signState(key)))
+}
+
+// ssoLogin sends a browser to the identity provider of an organization
+func (s *workspaceServer) ssoLogin(w http.ResponseWriter, r *http.Request) {
+	org := r.PathValue("org")
+	config, ok := s.ssoConfig(org)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	key, err := newShareToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	state := ssoState{Org: org, Expires: time.Now().Add(10 * time.Minute)}
+	var target string
+	switch config.Protocol {
+	case ssoOIDC:
+		target, state.Nonce, err = s.oidcAuthURL(org, config, key)
+	case ssoSAML:
+		target, state.RequestID, err = s.samlAuthURL(org, config, key)
+	}
+	if err != nil {
+		http.Error(w, "identity provider: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	s.addSSOState(key, state)
+	if config.Protocol == ssoOIDC {
+		s.setStateCookie(w, org, key)
+	}
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// oidcProvider is the discovery document of an OpenID Connect issuer
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// fetchJSON sends a request to an identity provider and decodes the answer
+func fetchJSON(req *http.Request, value any) error {
+	resp, err := ssoClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s %s", req.URL.Host, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(value)
+}
+
+func discoverOIDC(issuer string) (oidcProvider, error) {
+	var provider oidcProvider
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err == nil {
+		err = fetchJSON(req, &provider)
+	}
+	if err == nil && (provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "") {
+		err = errors.New("the discovery document lacks endpoints")
+	}
+	return provider, err
+}
+
+// oidcAuthURL is where the authorization code flow starts, with the nonce
+// the ID token has to carry
+func (s *workspaceServer) oidcAuthURL(org string, config SSOConfig, state string) (string, string, error) {
+	provider, err := discoverOIDC(config.Issuer)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := newShareToken()
+	if err != nil {
+		return "", "", err
+	}
+	target, err := url.Parse(provider.AuthorizationEndpoint)
+	if err != nil {
+		return "", "", err
+	}
+	query := target.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", config.ClientID)
+	query.Set("redirect_uri", s.orgURL(org)+"sso/oidc")
+	query.Set("scope", "openid profile email")
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	target.RawQuery = query.Encode()
+	return target.String(), nonce, nil
+}
+
+// oidcCallback completes a sign in over OpenID Connect
+func (s *workspaceServer) oidcCallback(w http.ResponseWriter, r *http.Request) {
+	org := r.PathValue("org")
+	query := r.URL.Query()
+	if failure := query.Get("error"); failure != "" {
+		http.Error(w, "sign in failed: "+failure+" "+query.Get("error_description"), http.StatusForbidden)
+		return
+	}
+	state, ok := s.takeSSOState(query.Get("state"), org)
+	config, configured := s.ssoConfig(org)
+	if !ok || !configured || config.Protocol != ssoOIDC || !s.stateCookieMatches(w, r, query.Get("state")) {
+		http.Error(w, "this sign in expired or was not started here, please try again", http.StatusBadRequest)
+		return
+	}
+	identity, err := s.oidcIdentity(org, config, query.Get("code"), state.Nonce)
+	if err != nil {
+		http.Error(w, "sign in failed: "+err.Error(), http.StatusForbidden)
+		return
+	}
+	s.signIn(w, r, org, identity)
+}
+
+// oidcIdentity redeems an authorization code and reads the person from the
+// verified ID token
+func (s *workspaceServer) oidcIdentity(org string, config SSOConfig, code, nonce string) (ssoIdentity, error) {
+	provider, err := discoverOIDC(config.Issuer)
+	if err != nil {
+		return ssoIdentity{}, err
+	}
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {s.orgURL(org) + "sso/oidc"},
+	}
+	req, err := http.NewRequest(http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return ssoIdentity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(config.ClientSecret))
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := fetchJSON(req, &tokens); err != nil {
+		return ssoIdentity{}, err
+	}
+	claims, err := verifyIDToken(provider, config.ClientID, tokens.IDToken)
+	if err != nil {
+		return ssoIdentity{}, err
+	}
+	if claims["nonce"] != nonce {
+		return ssoIdentity{}, errors.New("the ID token belongs to another sign in")
+	}
+	name, _ := claims[config.nameClaim()].(string)
+	if config.nameClaim() == "email" && !claimTrue(claims["email_verified"]) {
+		return ssoIdentity{}, errors.New("the identity provider has not verified your email address")
+	}
+	return ssoIdentity{Name: name, Groups: claimStrings(claims[config.groupsClaim()])}, nil
+}
+
+// claimTrue reads a boolean claim, which some providers send as a string
+func claimTrue(claim any) bool {
+	return claim == true || claim == "true"
+}
+
+// claimStrings reads a claim that is a list of strings or a single one
+func claimStrings(claim any) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		var values []string
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// verifyIDToken checks the RS256 signature, issuer, audience and expiry of
+// an ID token and answers its claims
+func verifyIDToken(provider oidcProvider, clientID, token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("the ID token is malformed")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeTokenPart(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("ID tokens signed with %s are not supported, use RS256", header.Alg)
+	}
+	key, err := jwksKey(provider.JWKSURI, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("the signature of the ID token is invalid")
+	}
+
+	var claims map[string]any
+	if err := decodeTokenPart(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if claims["iss"] != provider.Issuer {
+		return nil, errors.New("the ID token comes from another issuer")
+	}
+	audience := false
+	for _, aud := range claimStrings(claims["aud"]) {
+		audience = audience || aud == clientID
+	}
+	if !audience {
+		return nil, errors.New("the ID token is meant for another client")
+	}
+	if exp, ok := claims["exp"].(float64); !ok || time.Now().After(time.Unix(int64(exp), 0).Add(time.Minute)) {
+		return nil, errors.New("the ID token has expired")
+	}
+	return claims, nil
+}
+
+func decodeTokenPart(part string, value any) error {
+	content, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, value)
+}
+
+// jwksKey fetches the RSA key with an ID from the key set of an issuer
+func jwksKey(jwksURI, kid string) (*rsa.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	req, err := http.NewRequest(http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := fetchJSON(req, &set); err != nil {
+		return nil, err
+	}
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" || (kid != "" && key.Kid != kid) {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	}
+	return nil, fmt.Errorf("the issuer has no key %q", kid)
+}
+
+// samlCredentials are the key and certificate the server signs SAML
+// requests with, made on first use and kept next to the workspaces
+func (s *workspaceServer) samlCredentials() (*rsa.PrivateKey, *x509.Certificate, error) {
+	s.spOnce.Do(func() {
+		path := filepath.Join(s.dir, samlCredentialsFile)
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			content, err = newSAMLCredentials(path)
+		}
+		if err != nil {
+			s.spErr = err
+			return
+		}
+		for block, rest := pem.Decode(content); block != nil; block, rest = pem.Decode(rest) {
+			switch block.Type {
+			case "RSA PRIVATE KEY":
+				s.spKey, s.spErr = x509.ParsePKCS1PrivateKey(block.Bytes)
+			case "CERTIFICATE":
+				s.spCert, s.spErr = x509.ParseCertificate(block.Bytes)
+			}
+			if s.spErr != nil {
+				return
+			}
+		}
+		if s.spKey == nil || s.spCert == nil {
+			s.spErr = fmt.Errorf("%s lacks the key or the certificate", path)
+		}
+	})
+	return s.spKey, s.spCert, s.spErr
+}
+
+// newSAMLCredentials makes a key with a self-signed certificate and writes
+// them to path, readable only by the server
+func newSAMLCredentials(path string) ([]byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, err
+	}
+	cert := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "business-canvas"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(10, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, cert, cert, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	content := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	content = append(content, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	return content, os.WriteFile(path, content, 0o600)
+}
+
+// serviceProvider is the server as a SAML service provider for an
+// organization, trusting the identity provider of metadata
+func (s *workspaceServer) serviceProvider(org string, metadata *saml.EntityDescriptor) (*saml.ServiceProvider, error) {
+	key, cert, err := s.samlCredentials()
+	if err != nil {
+		return nil, err
+	}
+	metadataURL, err := url.Parse(s.orgURL(org) + "sso/saml/metadata")
+	if err != nil {
+		return nil, err
+	}
+	acsURL, err := url.Parse(s.orgURL(org) + "sso/saml")
+	if err != nil {
+		return nil, err
+	}
+	return &saml.ServiceProvider{
+		Key:         key,
+		Certificate: cert,
+		MetadataURL: *metadataURL,
+		AcsURL:      *acsURL,
+		IDPMetadata: metadata,
+	}, nil
+}
+
+func fetchSAMLMetadata(metadataURL string) (*saml.EntityDescriptor, error) {
+	resp, err := ssoClient.Get(metadataURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Request.URL.Host, resp.Status)
+	}
+	var metadata saml.EntityDescriptor
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("metadata of the identity provider: %w", err)
+	}
+	return &metadata, nil
+}
+
+// samlAuthURL is where a SAML sign in starts, with the ID of the request
+// the response has to answer
+func (s *workspaceServer) samlAuthURL(org string, config SSOConfig, state string) (string, string, error) {
+	metadata, err := fetchSAMLMetadata(config.MetadataURL)
+	if err != nil {
+		return "", "", err
+	}
+	sp, err := s.serviceProvider(org, metadata)
+	if err != nil {
+		return "", "", err
+	}
+	req, err := sp.MakeAuthenticationRequest(sp.GetSSOBindingLocation(saml.HTTPRedirectBinding), saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return "", "", err
+	}
+	target, err := req.Redirect(state, sp)
+	if err != nil {
+		return "", "", err
+	}
+	return target.String(), req.ID, nil
+}
+
+// samlACS completes a sign in over SAML
+func (s *workspaceServer) samlACS(w http.ResponseWriter, r *http.Request) {
+	org := r.PathValue("org")
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	state, ok := s.takeSSOState(r.PostForm.Get("RelayState"), org)
+	config, configured := s.ssoConfig(org)
+	if !ok || !configured || config.Protocol != ssoSAML {
+		http.Error(w, "this sign in expired or was not started here, please try again", http.StatusBadRequest)
+		return
+	}
+	metadata, err := fetchSAMLMetadata(config.MetadataURL)
+	if err != nil {
+		http.Error(w, "identity provider: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	sp, err := s.serviceProvider(org, metadata)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	assertion, err := sp.ParseResponse(r, []string{state.RequestID})
+	if err != nil {
+		var invalid *saml.InvalidResponseError
+		if errors.As(err, &invalid) {
+			err = invalid.PrivateErr
+		}
+		http.Error(w, "the answer of the identity provider was not accepted: "+err.Error(), http.StatusForbidden)
+		return
+	}
+	s.signIn(w, r, org, samlIdentity(assertion, config))
+}
+
+// samlIdentity reads the person from an assertion, named by its NameID
+// unless a name attribute is configured
+func samlIdentity(assertion *saml.Assertion, config SSOConfig) ssoIdentity {
+	var identity ssoIdentity
+	if assertion.Subject != nil && assertion.Subject.NameID != nil {
+		identity.Name = assertion.Subject.NameID.Value
+	}
+	for _, statement := range assertion.AttributeStatements {
+		for _, attribute := range statement.Attributes {
+			named := func(claim string) bool {
+				return attribute.Name == claim || attribute.FriendlyName == claim
+			}
+			for _, value := range attribute.Values {
+				if config.NameClaim != "" && named(config.NameClaim) {
+					identity.Name = value.Value
+				}
+				if named(config.groupsClaim()) {
+					identity.Groups = append(identity.Groups, value.Value)
+				}
+			}
+		}
+	}
+	return identity
+}
+
+func (s *workspaceServer) samlMetadata(w http.ResponseWriter, r *http.Request) {
+	org := r.PathValue("org")
+	if config, ok := s.ssoConfig(org); !ok || config.Protocol != ssoSAML {
+		http.NotFound(w, r)
+		return
+	}
+	sp, err := s.serviceProvider(org, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	content, err := xml.MarshalIndent(sp.Metadata(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	w.Write(content)
+}
+
+// signIn makes a signed in person a member of the organization with the
+// role of their groups and starts their session. A member added with a
+// token is never taken over by a sign in of the same name.
+func (s *workspaceServer) signIn(w http.ResponseWriter, r *http.Request, orgID string, identity ssoIdentity) {
+	if !workspaceNamePattern.MatchString(identity.Name) {
+		http.Error(w, fmt.Sprintf("the identity provider named you %q, which is not a valid member name", identity.Name), http.StatusForbidden)
+		return
+	}
+	token, err := newShareToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org := s.orgs[orgID]
+	if org == nil || org.SSO == nil {
+		http.NotFound(w, r)
+		return
+	}
+	index := -1
+	for i, m := range org.Members {
+		if m.Name == identity.Name {
+			index = i
+		}
+	}
+	if index >= 0 && !org.Members[index].SSO {
+		http.Error(w, identity.Name+" is a member added with a token, an owner has to remove them before they can sign in", http.StatusForbidden)
+		return
+	}
+	role := org.SSO.role(identity.Groups)
+	if role == "" {
+		if index >= 0 && len(org.Members[index].Sessions) > 0 {
+			org.Members[index].Sessions = nil
+			s.save(org)
+		}
+		http.Error(w, identity.Name+" is in no group with access to "+org.Name, http.StatusForbidden)
+		return
+	}
+	if index < 0 {
+		org.Members = append(org.Members, Member{Name: identity.Name, SSO: true})
+		index = len(org.Members) - 1
+	}
+	member := &org.Members[index]
+	member.Role = role
+	expires := time.Now().Add(ssoSessionLifetime)
+	sessions := map[string]time.Time{hashToken(token): expires}
+	for hash, until := range member.Sessions {
+		if time.Now().Before(until) {
+			sessions[hash] = until
+		}
+	}
+	member.Sessions = sessions
+	if !s.saveOrg(w, org) {
+		return
+	}
+
+	s.setSessionCookie(w, org.ID, token, expires)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	signedInPage.Execute(w, map[string]any{
+		"Org":     org,
+		"Member":  *member,
+		"Token":   token,
+		"Expires": expires,
+	})
+}
+
+// setSessionCookie signs a browser in to the pages of an organization with
+// a token, until expires or for the browser session when zero
+func (s *workspaceServer) setSessionCookie(w http.ResponseWriter, org, token string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoCookie,
+		Value:    token,
+		Path:     "/orgs/" + org + "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(s.publicURL, "https:"),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// signedInPage shows the session token a person can paste into the app
+var signedInPage = template.Must(template.New("signedIn").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Org.Name}} - Signed in</title>
+<style>body { font-family: sans-serif; margin: 2em; } code { background: #eee; padding: 4px; }</style>
+</head>
+<body>
+<h1>Signed in to {{.Org.Name}}</h1>
+<p>You are signed in as {{.Member.Name}} ({{.Member.Role}}) until {{.Expires.Format "2006-01-02 15:04"}}.</p>
+<p>To use the workspace in the app, choose Workspace in Tools &gt; Remote Storage... with this token: <code>{{.Token}}</code></p>
+<p><a href="./">Open the portfolio dashboard</a></p>
+</body>
+</html>
+`))
+
+// ssoSettings is the single sign-on of an organization with the addresses
+// to register at the identity provider. The client secret is never sent.
+type ssoSettings struct {
+	SSOConfig
+	SignIn   string `json:"signIn"`
+	Callback string `json:"callback"`
+	Metadata string `json:"metadata,omitempty"`
+}
+
+func (s *workspaceServer) getSSO(w http.ResponseWriter, r *http.Request, org *Organization, _ Member) {
+	if org.SSO == nil {
+		http.NotFound(w, r)
+		return
+	}
+	settings := ssoSettings{SSOConfig: *org.SSO, SignIn: s.orgURL(org.ID) + "login"}
+	settings.ClientSecret = ""
+	settings.Callback = s.orgURL(org.ID) + "sso/" + org.SSO.Protocol
+	if org.SSO.Protocol == ssoSAML {
+		settings.Metadata = s.orgURL(org.ID) + "sso/saml/metadata"
+	}
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// putSSO configures single sign-on, which only owners may do since groups
+// can be mapped to any role. A missing client secret keeps the one set.
+func (s *workspaceServer) putSSO(w http.ResponseWriter, r *http.Request, org *Organization, by Member) {
+	if by.Role != roleOwner {
+		http.Error(w, "only owners may configure single sign-on", http.StatusForbidden)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBody)
+	var config SSOConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := config.check(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if config.ClientSecret == "" && org.SSO != nil {
+		config.ClientSecret = org.SSO.ClientSecret
+	}
+	org.SSO = &config
+	if s.saveOrg(w, org) {
+		s.getSSO(w, r, org, by)
+	}
+}
+
+// deleteSSO turns single sign-on off and ends every session of it
+func (s *workspaceServer) deleteSSO(w http.ResponseWriter, r *http.Request, org *Organization, by Member) {
+	if by.Role != roleOwner {
+		http.Error(w, "only owners may configure single sign-on", http.StatusForbidden)
+		return
+	}
+	org.SSO = nil
+	for i := range org.Members {
+		org.Members[i].Sessions = nil
+	}
+	if s.saveOrg(w, org) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
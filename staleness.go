@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Preference keys for staleness tracking
+const (
+	prefStaleDaysPrefix = "staleDays."
+	prefLastDigest      = "lastDigest"
+)
+
+// defaultStaleDays is how long a section may go unchanged before a nudge
+const defaultStaleDays = 90
+
+// digestInterval is how often the staleness digest is shown on start
+const digestInterval = 7 * 24 * time.Hour
+
+// StaleSection is a section that has not been edited within its threshold
+type StaleSection struct {
+	Section string
+	Edited  time.Time
+	Days    int
+}
+
+// normalizeText collapses whitespace so layout-only edits are not counted
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// markSectionEdited records the edit time when a section's content changes
+// meaningfully, ignoring whitespace-only changes
+func (c *Canvas) markSectionEdited(section, text string, local bool) {
+	normalized := normalizeText(text)
+	if c.sectionBaseline[section] == normalized {
+		return
+	}
+	c.sectionBaseline[section] = normalized
+	c.collabMu.Lock()
+	c.sectionEdited[section] = nowUTC()
+	if local {
+		c.sectionEditors[section] = c.userName()
+	}
+	c.collabMu.Unlock()
+	c.refreshLastEdit()
+}
+
+// resetSectionEdits replaces the edit times and editors, e.g. after
+// loading a file
+func (c *Canvas) resetSectionEdits(edited map[string]time.Time, editors map[string]string) {
+	c.collabMu.Lock()
+	c.sectionEdited = make(map[string]time.Time)
+	for section, t := range edited {
+		c.sectionEdited[section] = t
+	}
+	c.sectionEditors = make(map[string]string)
+	for section, editor := range editors {
+		c.sectionEditors[section] = editor
+	}
+	c.collabMu.Unlock()
+	c.refreshLastEdit()
+	c.sectionBaseline = make(map[string]string)
+	for _, section := range c.getCurrentData().sections() {
+		c.sectionBaseline[section.Title] = normalizeText(section.Text)
+	}
+	c.resetSnapshotBase()
+}
+
+func (c *Canvas) staleDays(section string) int {
+	return c.prefs.IntWithFallback(prefStaleDaysPrefix+section, defaultStaleDays)
+}
+
+// staleSections lists sections past their threshold, stalest first
+func (c *Canvas) staleSections() []StaleSection {
+	return staleSectionsOf(c.getCurrentData(), c.staleDays, time.Now())
+}
+
+// staleSectionsOf lists the sections of canvas data past the threshold of
+// staleDays at now, stalest first
+func staleSectionsOf(data CanvasData, staleDays func(string) int, now time.Time) []StaleSection {
+	var stale []StaleSection
+	for _, section := range data.sections() {
+		edited, ok := data.SectionEdited[section.Title]
+		if !ok || edited.IsZero() {
+			continue
+		}
+		days := int(now.Sub(edited).Hours() / 24)
+		if days >= staleDays(section.Title) {
+			stale = append(stale, StaleSection{Section: section.Title, Edited: edited, Days: days})
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Days > stale[j].Days
+	})
+	return stale
+}
+
+func (s StaleSection) nudge() string {
+	return fmt.Sprintf("%s hasn't changed in %d days — still accurate?", s.Section, s.Days)
+}
+
+func (c *Canvas) refreshStaleness() {
+	if c.stalenessButton == nil {
+		return
+	}
+	stale := c.staleSections()
+	switch len(stale) {
+	case 0:
+		c.stalenessButton.Hide()
+	case 1:
+		c.stalenessButton.SetText(stale[0].nudge())
+		c.stalenessButton.Show()
+	default:
+		c.stalenessButton.SetText(fmt.Sprintf("%s (+%d more)", stale[0].nudge(), len(stale)-1))
+		c.stalenessButton.Show()
+	}
+}
+
+// showStalenessDigest lists every stale section
+func (c *Canvas) showStalenessDigest() {
+	stale := c.staleSections()
+	if len(stale) == 0 {
+		dialog.ShowInformation("Staleness Digest", "All sections have been updated recently", c.window)
+		return
+	}
+
+	digest := container.NewVBox()
+	for _, section := range stale {
+		digest.Add(widget.NewLabel(fmt.Sprintf("• %s (last edited %s)", section.nudge(), formatDate(section.Edited))))
+	}
+	dialog.ShowCustom("Staleness Digest", "Close", digest, c.window)
+}
+
+// showWeeklyDigest shows the digest when a canvas with stale sections
+// opens, at most once a week
+func (c *Canvas) showWeeklyDigest() {
+	last := time.Unix(int64(c.prefs.Int(prefLastDigest)), 0)
+	if time.Since(last) < digestInterval || len(c.staleSections()) == 0 {
+		return
+	}
+	c.showStalenessDigest()
+	c.prefs.SetInt(prefLastDigest, int(time.Now().Unix()))
+}
+
+// showStalenessSettings edits the per-section staleness thresholds
+func (c *Canvas) showStalenessSettings() {
+	var items []*widget.FormItem
+	entries := make(map[string]*widget.Entry)
+	for _, section := range c.getCurrentData().sections() {
+		entry := widget.NewEntry()
+		entry.SetText(strconv.Itoa(c.staleDays(section.Title)))
+		entry.Validator = func(s string) error {
+			if days, err := strconv.Atoi(s); err != nil || days <= 0 {
+				return fmt.Errorf("enter a number of days")
+			}
+			return nil
+		}
+		entries[section.Title] = entry
+		items = append(items, widget.NewFormItem(section.Title, entry))
+	}
+
+	form := dialog.NewForm("Staleness Thresholds (days)", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		for section, entry := range entries {
+			if days, err := strconv.Atoi(entry.Text); err == nil {
+				c.prefs.SetInt(prefStaleDaysPrefix+section, days)
+			}
+		}
+		c.refreshStaleness()
+		c.refreshHealth()
+	}, c.window)
+	form.Resize(fyne.NewSize(400, 0))
+	form.Show()
+}
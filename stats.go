@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// prefExportStats adds a statistics footer to PDF and HTML exports
+const prefExportStats = "exportStats"
+
+// canvasStats summarises the state of the canvas for export footers
+type canvasStats struct {
+	Completeness int // percentage of filled sections
+	Words        int
+	Version      int // number of saved versions
+	Issues       int // failed validation rules
+}
+
+// summary renders the statistics as a single footer line
+func (s canvasStats) summary() string {
+	parts := []string{
+		fmt.Sprintf("Completeness %d%%", s.Completeness),
+		fmt.Sprintf("%d words", s.Words),
+		fmt.Sprintf("Version %d", s.Version),
+	}
+	switch s.Issues {
+	case 0:
+		parts = append(parts, "Valid")
+	case 1:
+		parts = append(parts, "1 validation issue")
+	default:
+		parts = append(parts, fmt.Sprintf("%d validation issues", s.Issues))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// canvasStats computes the statistics of the canvas on screen
+func (c *Canvas) canvasStats() canvasStats {
+	kind := findCanvasType(c.canvasTypeID)
+	entries := c.standardEntries()[:len(kind.Titles)]
+	for _, block := range c.customBlocks {
+		entries = append(entries, block.entry)
+	}
+
+	var stats canvasStats
+	filled := 0
+	for _, entry := range entries {
+		if len(entry.Text) > 0 {
+			filled++
+		}
+		stats.Words += len(strings.Fields(entry.Text))
+	}
+	if len(entries) > 0 {
+		stats.Completeness = filled * 100 / len(entries)
+	}
+	stats.Version = len(c.versions)
+	stats.Issues = len(c.validator.Validate(c))
+	return stats
+}
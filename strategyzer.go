@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// strategyzerBlockTypes are the Strategyzer block identifiers of the nine
+// standard blocks in reading order
+var strategyzerBlockTypes = []string{
+	"keyPartners",
+	"keyActivities",
+	"keyResources",
+	"valuePropositions",
+	"customerRelationships",
+	"channels",
+	"customerSegments",
+	"costStructure",
+	"revenueStreams",
+}
+
+// strategyzerCanvas is a Business Model Canvas in the Strategyzer layout:
+// blocks of sticky notes
+type strategyzerCanvas struct {
+	Name   string             `json:"name"`
+	Type   string             `json:"type"`
+	Blocks []strategyzerBlock `json:"blocks"`
+}
+
+type strategyzerBlock struct {
+	Type     string              `json:"type"`
+	Stickies []strategyzerSticky `json:"stickies"`
+}
+
+type strategyzerSticky struct {
+	Text string `json:"text"`
+}
+
+// normalizeBlockName reduces a block name to lower-case letters and digits
+// without a plural "s", so "Value Propositions" matches "valueProposition"
+func normalizeBlockName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+	return strings.TrimSuffix(name, "s")
+}
+
+// matchStandardBlock returns the reading order index of the standard block
+// named by a Strategyzer identifier or a block title, -1 when unknown
+func matchStandardBlock(kind *canvasType, name string) int {
+	name = normalizeBlockName(name)
+	for i, title := range kind.Titles {
+		if name == normalizeBlockName(title) {
+			return i
+		}
+	}
+	for i, blockType := range strategyzerBlockTypes {
+		if name == normalizeBlockName(blockType) {
+			return i
+		}
+	}
+	return -1
+}
+
+// toStrategyzer converts the standard blocks to sticky notes, one per line
+func toStrategyzer(name string, data CanvasData) strategyzerCanvas {
+	canvas := strategyzerCanvas{Name: name, Type: "businessModelCanvas"}
+	for i, field := range data.standardFields() {
+		block := strategyzerBlock{Type: strategyzerBlockTypes[i], Stickies: []strategyzerSticky{}}
+		for _, line := range sectionLines(*field) {
+			block.Stickies = append(block.Stickies, strategyzerSticky{Text: line})
+		}
+		canvas.Blocks = append(canvas.Blocks, block)
+	}
+	return canvas
+}
+
+// addSticky appends a sticky note to the standard block it belongs to
+func addSticky(data *CanvasData, block int, text string) {
+	field := data.standardFields()[block]
+	if *field != "" {
+		*field += "\n"
+	}
+	*field += text
+}
+
+// fromStrategyzer converts sticky notes back to section text, one line each
+func fromStrategyzer(canvas strategyzerCanvas) (CanvasData, error) {
+	data := CanvasData{CanvasType: canvasTypeBusiness}
+	kind := data.canvasType()
+	matched := false
+	for _, block := range canvas.Blocks {
+		index := matchStandardBlock(kind, block.Type)
+		if index < 0 {
+			continue
+		}
+		matched = true
+		for _, sticky := range block.Stickies {
+			if text := strings.TrimSpace(sticky.Text); text != "" {
+				addSticky(&data, index, text)
+			}
+		}
+	}
+	if !matched {
+		return data, errors.New("no Business Model Canvas blocks found")
+	}
+	return data, nil
+}
+
+func readStrategyzerJSON(reader io.Reader) (CanvasData, error) {
+	var canvas strategyzerCanvas
+	if err := json.NewDecoder(reader).Decode(&canvas); err != nil {
+		return CanvasData{}, err
+	}
+	return fromStrategyzer(canvas)
+}
+
+// strategyzerRows lays out a canvas as rows of block and sticky note, the
+// spreadsheet form of the Strategyzer layout
+func strategyzerRows(canvas strategyzerCanvas) [][]string {
+	rows := [][]string{{"Block", "Sticky"}}
+	for _, block := range canvas.Blocks {
+		for _, sticky := range block.Stickies {
+			rows = append(rows, []string{block.Type, sticky.Text})
+		}
+	}
+	return rows
+}
+
+// readStrategyzerXLSX reads block and sticky note rows, skipping a header
+func readStrategyzerXLSX(reader io.Reader) (CanvasData, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return CanvasData{}, err
+	}
+	rows, err := readXLSXRows(content)
+	if err != nil {
+		return CanvasData{}, err
+	}
+
+	canvas := strategyzerCanvas{}
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		canvas.Blocks = append(canvas.Blocks, strategyzerBlock{
+			Type:     row[0],
+			Stickies: []strategyzerSticky{{Text: row[1]}},
+		})
+	}
+	return fromStrategyzer(canvas)
+}
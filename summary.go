@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+// summaryItemLimit keeps each part of the executive summary short enough
+// for the whole summary to fit on one page
+const summaryItemLimit = 5
+
+// summaryPart is a heading of the executive summary and the standard
+// blocks, by reading order index, it is compressed from
+type summaryPart struct {
+	Heading string
+	Blocks  []int
+}
+
+var summaryParts = []summaryPart{
+	{"Problem", []int{3}},
+	{"Solution", []int{1, 2, 0}},
+	{"Market", []int{6, 5, 4}},
+	{"Revenue", []int{8, 7}},
+}
+
+// executiveSummary compresses the canvas into a plain-text one-pager with
+// problem, solution, market and revenue
+func executiveSummary(data CanvasData, now time.Time) string {
+	kind := data.canvasType()
+	sections := data.sections()[:len(kind.Titles)]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "EXECUTIVE SUMMARY — %s\n%s\n", strings.ToUpper(kind.Name), now.Format("January 2, 2006"))
+	for _, part := range summaryParts {
+		var items []string
+		var sources []string
+		for _, block := range part.Blocks {
+			if block >= len(sections) {
+				continue
+			}
+			lines := sectionLines(sections[block].Text)
+			if len(lines) > 0 {
+				sources = append(sources, sections[block].Title)
+			}
+			items = append(items, lines...)
+		}
+		if len(items) > summaryItemLimit {
+			items = items[:summaryItemLimit]
+		}
+
+		fmt.Fprintf(&b, "\n%s\n%s\n", strings.ToUpper(part.Heading), strings.Repeat("-", len(part.Heading)))
+		if len(items) == 0 {
+			b.WriteString("(not yet defined)\n")
+			continue
+		}
+		for _, item := range items {
+			b.WriteString("* " + item + "\n")
+		}
+		fmt.Fprintf(&b, "  (from %s)\n", strings.Join(sources, ", "))
+	}
+	return b.String()
+}
+
+func (c *Canvas) exportSummary() {
+	data := c.getCurrentData()
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		if _, err := io.WriteString(writer, executiveSummary(data, time.Now())); err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+
+		dialog.ShowInformation("Success", "Executive summary has been exported successfully", c.window)
+	}, c.window)
+	saveDialog.SetFileName("executive-summary.txt")
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".txt"}))
+	saveDialog.Show()
+}
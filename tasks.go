@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+// Task is a to-do item for a canvas section
+type Task struct {
+	Section string
+	Text    string
+}
+
+// canvasTasks turns validation findings and the guiding questions of empty
+// sections into tasks, in section order
+func (c *Canvas) canvasTasks() []Task {
+	data := c.getCurrentData()
+	findings := make(map[string][]string)
+	for _, result := range c.validator.Validate(c) {
+		findings[result.Section] = append(findings[result.Section], result.Message)
+	}
+
+	kind := data.canvasType()
+	prompts := make(map[string]string)
+	for i, title := range kind.Titles {
+		prompts[title] = kind.Prompts[i]
+	}
+	for _, custom := range data.CustomSections {
+		prompts[custom.Title] = custom.Prompt
+	}
+
+	var tasks []Task
+	for _, section := range data.sections() {
+		for _, message := range findings[section.Title] {
+			tasks = append(tasks, Task{Section: section.Title, Text: message})
+		}
+		if strings.TrimSpace(section.Text) == "" && prompts[section.Title] != "" {
+			tasks = append(tasks, Task{Section: section.Title, Text: "Answer: " + prompts[section.Title]})
+		}
+	}
+	return tasks
+}
+
+func writeTasksMarkdown(w io.Writer, tasks []Task) error {
+	var b strings.Builder
+	b.WriteString("# Canvas Tasks\n\n")
+	for _, task := range tasks {
+		fmt.Fprintf(&b, "- [ ] **%s**: %s\n", task.Section, task.Text)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTasksCSV(w io.Writer, tasks []Task) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"Section", "Task", "Done"})
+	for _, task := range tasks {
+		out.Write([]string{task.Section, task.Text, "no"})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// exportTaskList saves the canvas tasks as a Markdown checklist, or as CSV
+// when the file name ends in .csv
+func (c *Canvas) exportTaskList() {
+	tasks := c.canvasTasks()
+	if len(tasks) == 0 {
+		dialog.ShowInformation("Task List", "There are no open issues", c.window)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		if strings.ToLower(writer.URI().Extension()) == ".csv" {
+			err = writeTasksCSV(writer, tasks)
+		} else {
+			err = writeTasksMarkdown(writer, tasks)
+		}
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+
+		dialog.ShowInformation("Success", fmt.Sprintf("%d tasks have been exported successfully", len(tasks)), c.window)
+	}, c.window)
+	saveDialog.SetFileName("canvas-tasks.md")
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".md", ".csv"}))
+	saveDialog.Show()
+}
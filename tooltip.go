@@ -0,0 +1,207 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Tooltips show after the pointer rests on a widget for tooltipDelay,
+// offset from the pointer by tooltipOffset
+const tooltipDelay = 600 * time.Millisecond
+
+var tooltipOffset = fyne.NewPos(12, 18)
+
+// tooltipLayer draws tooltips above the window content. It sits in the
+// content itself rather than in an overlay, so it never takes the clicks
+// meant for the widgets below it.
+type tooltipLayer struct {
+	layer      *fyne.Container
+	bubble     *fyne.Container
+	background *canvas.Rectangle
+	label      *widget.Label
+	timer      *time.Timer
+	owner      fyne.CanvasObject
+	at         fyne.Position
+}
+
+func newTooltipLayer() *tooltipLayer {
+	t := &tooltipLayer{label: widget.NewLabel(""), background: canvas.NewRectangle(nil)}
+	t.background.StrokeWidth = 1
+	t.bubble = container.NewStack(t.background, t.label)
+	t.bubble.Hide()
+	t.layer = container.NewWithoutLayout(t.bubble)
+	return t
+}
+
+// hover schedules the tooltip of owner near the pointer
+func (t *tooltipLayer) hover(owner fyne.CanvasObject, text string, at fyne.Position) {
+	if text == "" {
+		return
+	}
+	t.at = at
+	if t.owner == owner {
+		return
+	}
+	t.hide()
+	t.owner = owner
+	t.timer = time.AfterFunc(tooltipDelay, func() {
+		if t.owner != owner {
+			return
+		}
+		t.show(text)
+	})
+}
+
+// show places the tooltip next to the pointer, kept inside the window
+func (t *tooltipLayer) show(text string) {
+	// The colors follow the current theme, which can change at any time
+	t.background.FillColor = theme.Color(theme.ColorNameOverlayBackground)
+	t.background.StrokeColor = theme.Color(theme.ColorNameShadow)
+	t.background.CornerRadius = theme.InputRadiusSize()
+	t.label.SetText(text)
+	size := t.bubble.MinSize()
+	bounds := t.layer.Size()
+	pos := t.at.Add(tooltipOffset)
+	if pos.X+size.Width > bounds.Width {
+		pos.X = max(bounds.Width-size.Width, 0)
+	}
+	if pos.Y+size.Height > bounds.Height {
+		pos.Y = max(t.at.Y-size.Height-theme.Padding(), 0)
+	}
+	t.bubble.Resize(size)
+	t.bubble.Move(pos)
+	t.bubble.Show()
+	t.layer.Refresh()
+}
+
+// hide removes the tooltip and cancels a pending one
+func (t *tooltipLayer) hide() {
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	t.owner = nil
+	if t.bubble.Visible() {
+		t.bubble.Hide()
+		t.layer.Refresh()
+	}
+}
+
+// hintArea shows a tooltip for content that does not handle the pointer
+// itself, such as labels. Tapping it hides the tooltip and calls onTapped.
+type hintArea struct {
+	widget.BaseWidget
+	content  fyne.CanvasObject
+	tips     *tooltipLayer
+	tip      string
+	onTapped func()
+
+	onSecondaryTapped func(*fyne.PointEvent)
+
+	// detail, when set, adds a line that changes over time to the tip
+	detail func() string
+}
+
+func newHintArea(tips *tooltipLayer, tip string, content fyne.CanvasObject, onTapped func()) *hintArea {
+	h := &hintArea{content: content, tips: tips, tip: tip, onTapped: onTapped}
+	h.ExtendBaseWidget(h)
+	return h
+}
+
+func (h *hintArea) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(h.content)
+}
+
+func (h *hintArea) tipText() string {
+	if h.detail == nil {
+		return h.tip
+	}
+	if detail := h.detail(); detail != "" {
+		return h.tip + "\n" + detail
+	}
+	return h.tip
+}
+
+func (h *hintArea) MouseIn(e *desktop.MouseEvent) {
+	h.tips.hover(h, h.tipText(), e.AbsolutePosition)
+}
+
+func (h *hintArea) MouseMoved(e *desktop.MouseEvent) {
+	h.tips.hover(h, h.tipText(), e.AbsolutePosition)
+}
+
+func (h *hintArea) MouseOut() {
+	h.tips.hide()
+}
+
+func (h *hintArea) Tapped(*fyne.PointEvent) {
+	h.tips.hide()
+	if h.onTapped != nil {
+		h.onTapped()
+	}
+}
+
+func (h *hintArea) TappedSecondary(e *fyne.PointEvent) {
+	h.tips.hide()
+	if h.onSecondaryTapped != nil {
+		h.onSecondaryTapped(e)
+	}
+}
+
+// hintButton is a button with a tooltip, which goes away once pressed
+type hintButton struct {
+	widget.Button
+	tips *tooltipLayer
+	tip  string
+}
+
+func newHintButton(tips *tooltipLayer, tip, label string, icon fyne.Resource, tapped func()) *hintButton {
+	b := &hintButton{tips: tips, tip: tip}
+	b.Text = label
+	b.Icon = icon
+	b.OnTapped = tapped
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+func (b *hintButton) MouseIn(e *desktop.MouseEvent) {
+	b.Button.MouseIn(e)
+	b.tips.hover(b, b.tip, e.AbsolutePosition)
+}
+
+func (b *hintButton) MouseMoved(e *desktop.MouseEvent) {
+	b.Button.MouseMoved(e)
+	b.tips.hover(b, b.tip, e.AbsolutePosition)
+}
+
+func (b *hintButton) MouseOut() {
+	b.Button.MouseOut()
+	b.tips.hide()
+}
+
+func (b *hintButton) Tapped(e *fyne.PointEvent) {
+	b.tips.hide()
+	b.Button.Tapped(e)
+}
+
+// hintToolbarAction is a toolbar action with a tooltip
+type hintToolbarAction struct {
+	button *hintButton
+}
+
+func (a *hintToolbarAction) ToolbarObject() fyne.CanvasObject {
+	return a.button
+}
+
+// toolbarAction creates a toolbar action showing tip on hover
+func (c *Canvas) toolbarAction(icon fyne.Resource, tip string, activated func()) widget.ToolbarItem {
+	button := newHintButton(c.tooltips, tip, "", icon, activated)
+	button.Importance = widget.LowImportance
+	return &hintToolbarAction{button: button}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"gopkg.in/yaml.v3"
+)
+
+// Organizations codify their review standards in a validation file, YAML
+// or JSON, in the folder of the app. Its rules replace the built-in ones of
+// the canvas types they cover, or are added to them:
+//
+//	replaceBuiltIn: true
+//	rules:
+//	  - section: Value Proposition
+//	    canvasType: business
+//	    predicate: minWords
+//	    threshold: 25
+//	    message: Explain the value proposition in at least 25 words
+//	    severity: error
+//	  - section: Channels
+//	    predicate: contains
+//	    text: online
+//	    message: Say which channels are online
+//	    severity: info
+
+// validationFileNames are the names the validation file is looked up by
+var validationFileNames = []string{"validation.yaml", "validation.yml", "validation.json"}
+
+// Severities of validation rules, from most to least severe
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityInfo    = "info"
+)
+
+// Predicates a configured rule checks the text of its section with
+const (
+	predicateNotEmpty  = "notEmpty"
+	predicateMinLength = "minLength"
+	predicateMaxLength = "maxLength"
+	predicateMinWords  = "minWords"
+	predicateMaxWords  = "maxWords"
+	predicateMinItems  = "minItems"
+	predicateMaxItems  = "maxItems"
+	predicateContains  = "contains"
+	predicateMatches   = "matches"
+)
+
+// ValidationConfig is a validation file
+type ValidationConfig struct {
+	// ReplaceBuiltIn drops the built-in rules of the canvas types the file
+	// has rules for
+	ReplaceBuiltIn bool             `json:"replaceBuiltIn" yaml:"replaceBuiltIn"`
+	Rules          []ConfiguredRule `json:"rules" yaml:"rules"`
+
+	path     string
+	patterns map[int]*regexp.Regexp // compiled patterns of matches rules
+}
+
+// ConfiguredRule is a rule of a validation file. Rules without a canvas
+// type apply to every canvas with the section.
+type ConfiguredRule struct {
+	Section    string `json:"section" yaml:"section"`
+	CanvasType string `json:"canvasType,omitempty" yaml:"canvasType"`
+	Predicate  string `json:"predicate" yaml:"predicate"`
+	Threshold  int    `json:"threshold,omitempty" yaml:"threshold"`
+	Text       string `json:"text,omitempty" yaml:"text"`
+	Message    string `json:"message" yaml:"message"`
+	Severity   string `json:"severity,omitempty" yaml:"severity"`
+}
+
+// validationFolder is where the validation file is looked for
+func validationFolder() string {
+	return fyne.CurrentApp().Storage().RootURI().Path()
+}
+
+// loadValidationConfig reads the validation file in dir, nil when there is
+// none
+func loadValidationConfig(dir string) (*ValidationConfig, error) {
+	for _, name := range validationFileNames {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		config, err := parseValidationConfig(name, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		config.path = path
+		return config, nil
+	}
+	return nil, nil
+}
+
+// parseValidationConfig reads a validation file, YAML unless it ends in
+// .json, and checks its rules
+func parseValidationConfig(name string, data []byte) (*ValidationConfig, error) {
+	var config ValidationConfig
+	var err error
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		err = json.Unmarshal(data, &config)
+	} else {
+		err = yaml.Unmarshal(data, &config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	config.patterns = make(map[int]*regexp.Regexp)
+	for i := range config.Rules {
+		rule := &config.Rules[i]
+		if strings.TrimSpace(rule.Section) == "" {
+			return nil, fmt.Errorf("rule %d needs a section", i+1)
+		}
+		if rule.Severity == "" {
+			rule.Severity = severityWarning
+		}
+		switch rule.Severity {
+		case severityError, severityWarning, severityInfo:
+		default:
+			return nil, fmt.Errorf("rule %d: unknown severity %q, use error, warning or info", i+1, rule.Severity)
+		}
+		switch rule.Predicate {
+		case predicateNotEmpty:
+		case predicateMinLength, predicateMaxLength, predicateMinWords, predicateMaxWords, predicateMinItems, predicateMaxItems:
+			if rule.Threshold < 0 {
+				return nil, fmt.Errorf("rule %d: the threshold cannot be negative", i+1)
+			}
+		case predicateContains:
+			if rule.Text == "" {
+				return nil, fmt.Errorf("rule %d: contains needs the text to look for", i+1)
+			}
+		case predicateMatches:
+			pattern, err := regexp.Compile(rule.Text)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			config.patterns[i] = pattern
+		default:
+			return nil, fmt.Errorf("rule %d: unknown predicate %q", i+1, rule.Predicate)
+		}
+		if rule.Message == "" {
+			rule.Message = rule.describe()
+		}
+	}
+	return &config, nil
+}
+
+// describe words what a rule asks for, its message when it has none
+func (r ConfiguredRule) describe() string {
+	switch r.Predicate {
+	case predicateNotEmpty:
+		return "Fill in this section"
+	case predicateMinLength:
+		return fmt.Sprintf("Write at least %d characters", r.Threshold)
+	case predicateMaxLength:
+		return fmt.Sprintf("Keep it to %d characters", r.Threshold)
+	case predicateMinWords:
+		return fmt.Sprintf("Write at least %d words", r.Threshold)
+	case predicateMaxWords:
+		return fmt.Sprintf("Keep it to %d words", r.Threshold)
+	case predicateMinItems:
+		return fmt.Sprintf("List at least %d items, one per line", r.Threshold)
+	case predicateMaxItems:
+		return fmt.Sprintf("List at most %d items", r.Threshold)
+	case predicateContains:
+		return fmt.Sprintf("Mention %q", r.Text)
+	}
+	return fmt.Sprintf("Match %s", r.Text)
+}
+
+// passes checks the text of a section against a rule
+func (r ConfiguredRule) passes(text string, pattern *regexp.Regexp) bool {
+	switch r.Predicate {
+	case predicateNotEmpty:
+		return strings.TrimSpace(text) != ""
+	case predicateMinLength:
+		return len(text) >= r.Threshold
+	case predicateMaxLength:
+		return len(text) <= r.Threshold
+	case predicateMinWords:
+		return len(strings.Fields(text)) >= r.Threshold
+	case predicateMaxWords:
+		return len(strings.Fields(text)) <= r.Threshold
+	case predicateMinItems:
+		return len(sectionLines(text)) >= r.Threshold
+	case predicateMaxItems:
+		return len(sectionLines(text)) <= r.Threshold
+	case predicateContains:
+		return strings.Contains(strings.ToLower(text), strings.ToLower(r.Text))
+	case predicateMatches:
+		return pattern.MatchString(text)
+	}
+	return true
+}
+
+// covers reports whether the file has rules for a canvas type
+func (v *ValidationConfig) covers(canvasType string) bool {
+	for _, rule := range v.Rules {
+		if rule.CanvasType == "" || rule.CanvasType == canvasType {
+			return true
+		}
+	}
+	return false
+}
+
+// apply sets up the rules of the file for a canvas type on a validator.
+// Rules for sections the canvas does not have pass.
+func (v *ValidationConfig) apply(validator *BusinessValidator, canvasType string) {
+	if v.ReplaceBuiltIn && v.covers(canvasType) {
+		validator.rules = nil
+	}
+	for i, rule := range v.Rules {
+		if rule.CanvasType != "" && rule.CanvasType != canvasType {
+			continue
+		}
+		rule, pattern := rule, v.patterns[i]
+		validator.rules = append(validator.rules, ValidationRule{
+			Section:  rule.Section,
+			Message:  rule.Message,
+			Severity: rule.Severity,
+			Check: func(c *Canvas) bool {
+				entry := c.sectionEntry(rule.Section)
+				return entry == nil || rule.passes(entry.Text, pattern)
+			},
+		})
+	}
+}
+
+// showValidationConfig shows the rules of the validation file, where to put
+// it, and reloads it
+func (c *Canvas) showValidationConfig() {
+	list := container.NewVBox()
+	var refresh func()
+	refresh = func() {
+		list.RemoveAll()
+		if c.validationErr != nil {
+			errorLabel := widget.NewLabel(c.validationErr.Error())
+			errorLabel.Importance = widget.DangerImportance
+			errorLabel.Wrapping = fyne.TextWrapWord
+			list.Add(errorLabel)
+			return
+		}
+		config := c.validationConfig
+		if config == nil {
+			list.Add(widget.NewLabel("No validation file, the built-in rules apply"))
+			return
+		}
+		mode := "added to the built-in rules"
+		if config.ReplaceBuiltIn {
+			mode = "replacing the built-in rules"
+		}
+		list.Add(widget.NewLabel(fmt.Sprintf("%d rules from %s, %s:", len(config.Rules), filepath.Base(config.path), mode)))
+		for _, rule := range config.Rules {
+			kind := "all canvases"
+			if rule.CanvasType != "" {
+				kind = findCanvasType(rule.CanvasType).Name
+			}
+			label := widget.NewLabel(fmt.Sprintf("• %s (%s, %s): %s", rule.Section, kind, rule.Severity, rule.Message))
+			label.Wrapping = fyne.TextWrapWord
+			list.Add(label)
+		}
+	}
+	refresh()
+
+	folder := validationFolder()
+	help := widget.NewLabel("Put validation.yaml or validation.json in " + folder + ". Predicates: notEmpty, minLength, maxLength, minWords, maxWords, minItems, maxItems, contains and matches.")
+	help.Wrapping = fyne.TextWrapWord
+	open := widget.NewButton("Open Folder", func() {
+		if err := os.MkdirAll(folder, 0o755); err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if folderURL, err := url.Parse(storage.NewFileURI(folder).String()); err == nil {
+			fyne.CurrentApp().OpenURL(folderURL)
+		}
+	})
+	reload := widget.NewButton("Reload", func() {
+		c.validationConfig, c.validationErr = loadValidationConfig(folder)
+		c.loadValidator()
+		c.updateProgress()
+		refresh()
+	})
+
+	d := dialog.NewCustom("Validation Rules", "Close", container.NewBorder(help, container.NewHBox(open, reload), nil, nil, container.NewVScroll(list)), c.window)
+	d.Resize(fyne.NewSize(600, 450))
+	d.Show()
+}
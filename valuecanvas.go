@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// SegmentLink names the Business Model Canvas file and the customer segment
+// in it that a Value Proposition Canvas is for
+type SegmentLink struct {
+	Canvas  string `json:"canvas,omitempty"`
+	Segment string `json:"segment"`
+}
+
+func sameSegmentLink(a, b *SegmentLink) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// customerSegments lists the segments of a Business Model Canvas, one per
+// line of its Customer Segments block
+func customerSegments(data CanvasData) []string {
+	if data.canvasType().ID != canvasTypeBusiness {
+		return nil
+	}
+	return sectionLines(data.CustomerSegments)
+}
+
+// createSegmentBar shows which customer segment a Value Proposition Canvas
+// is for, with buttons to change the link and to go to the main canvas
+func (c *Canvas) createSegmentBar() fyne.CanvasObject {
+	label := widget.NewLabel("Not linked to a customer segment")
+	if link := c.segmentLink; link != nil {
+		label.SetText("For customer segment: " + link.Segment)
+		if link.Canvas != "" {
+			if uri, err := storage.ParseURI(link.Canvas); err == nil {
+				label.SetText(fmt.Sprintf("For customer segment: %s (%s)", link.Segment, uri.Name()))
+			}
+		}
+	}
+	linkButton := widget.NewButton("Link to Segment...", c.linkSegment)
+	openButton := widget.NewButton("Open Business Model Canvas", c.openSegmentCanvas)
+	if c.segmentLink == nil || c.segmentLink.Canvas == "" {
+		openButton.Disable()
+	}
+	return container.NewBorder(nil, nil, nil, container.NewHBox(linkButton, openButton), label)
+}
+
+// chooseSegment lets the user pick one of the customer segments of a canvas
+func (c *Canvas) chooseSegment(data CanvasData, chosen func(segment string)) {
+	segments := customerSegments(data)
+	if len(segments) == 0 {
+		dialog.ShowError(errors.New("the Business Model Canvas has no customer segments, list one per line in Customer Segments"), c.window)
+		return
+	}
+	segmentSelect := widget.NewSelect(segments, nil)
+	segmentSelect.SetSelectedIndex(0)
+	dialog.ShowForm("Customer Segment", "Link", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Segment", segmentSelect),
+	}, func(ok bool) {
+		if ok {
+			chosen(segmentSelect.Selected)
+		}
+	}, c.window)
+}
+
+// linkSegment links the open Value Proposition Canvas to a customer segment
+// of a Business Model Canvas file
+func (c *Canvas) linkSegment() {
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		data, err := readCanvasData(reader)
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		file := reader.URI().String()
+		c.chooseSegment(data, func(segment string) {
+			c.undoStack = append(c.undoStack, c.getCurrentData())
+			c.segmentLink = &SegmentLink{Canvas: file, Segment: segment}
+			c.refreshLayout()
+		})
+	}, c.window)
+}
+
+// openSegmentCanvas opens the Business Model Canvas a Value Proposition
+// Canvas is linked to
+func (c *Canvas) openSegmentCanvas() {
+	if c.segmentLink == nil || c.segmentLink.Canvas == "" {
+		return
+	}
+	uri, err := parseLocation(c.segmentLink.Canvas)
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	data, err := readCanvasURI(uri)
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	c.openCanvasData(uri.Name(), data, func() { c.watchOpenFile(uri) })
+}
+
+// newValueCanvas starts a Value Proposition Canvas for a customer segment of
+// the open Business Model Canvas, seeded with its value proposition
+func (c *Canvas) newValueCanvas() {
+	data := c.getCurrentData()
+	if data.canvasType().ID != canvasTypeBusiness {
+		dialog.ShowInformation("Value Proposition Canvas", "Open a Business Model Canvas to design a value proposition for one of its customer segments", c.window)
+		return
+	}
+	file := ""
+	if c.openFile != nil {
+		file = c.openFile.String()
+	}
+	c.chooseSegment(data, func(segment string) {
+		next := CanvasData{
+			FormatVersion:    canvasFormatVersion,
+			CanvasType:       canvasTypeValue,
+			ValueProposition: data.ValueProposition,
+			SegmentLink:      &SegmentLink{Canvas: file, Segment: segment},
+		}
+		c.confirmReplace("A new Value Proposition Canvas", next, func() { c.startCanvas(next) })
+	})
+}
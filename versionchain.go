@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// With the hash chain on, every saved version records the hash of the one
+// saved before it and its own hash over its content, so changing, removing
+// or reordering recorded versions shows when the history is verified.
+// Names, tags, notes and comments are annotations and stay editable.
+
+// prefHashChain turns on hash chaining of saved versions
+const prefHashChain = "hashChain"
+
+// versionDigest is the part of a version its hash covers
+type versionDigest struct {
+	ID        string     `json:"id"`
+	Timestamp string     `json:"timestamp"`
+	Author    string     `json:"author"`
+	Branch    string     `json:"branch"`
+	Data      CanvasData `json:"data"`
+	PrevHash  string     `json:"prevHash"`
+}
+
+// versionHash is the SHA-256 of the content of a version and the hash it
+// follows
+func versionHash(version Version) string {
+	digest, _ := json.Marshal(versionDigest{
+		ID:        version.ID,
+		Timestamp: version.Timestamp.UTC().Format(time.RFC3339Nano),
+		Author:    version.Author,
+		Branch:    version.Branch,
+		Data:      version.Data,
+		PrevHash:  version.PrevHash,
+	})
+	sum := sha256.Sum256(digest)
+	return hex.EncodeToString(sum[:])
+}
+
+// prunedVersion is what the retention policy keeps of a chained version it
+// removes, so the chain still verifies across the gap
+type prunedVersion struct {
+	Timestamp time.Time
+	PrevHash  string
+	Hash      string
+}
+
+// chainVersion links a version to the latest saved one and hashes it
+func chainVersion(version *Version, versions []Version) {
+	version.PrevHash = ""
+	if len(versions) > 0 {
+		version.PrevHash = versions[len(versions)-1].Hash
+	}
+	version.Hash = versionHash(*version)
+}
+
+// chainedVersions are the versions with a hash, kept in canvas files
+func chainedVersions(versions []Version) []Version {
+	var chained []Version
+	for _, version := range versions {
+		if version.Hash != "" {
+			chained = append(chained, version)
+		}
+	}
+	return chained
+}
+
+// verifyVersionChain checks the hashes of versions in the order they were
+// saved, answering how many are chained and what is wrong
+func verifyVersionChain(versions []Version) (chained int, problems []string) {
+	for i, version := range versions {
+		name := formatStampSeconds(version.Timestamp)
+		if version.Hash == "" {
+			if i > 0 && versions[i-1].Hash != "" {
+				problems = append(problems, name+": has no hash although the versions before it do")
+			}
+			continue
+		}
+		chained++
+		if versionHash(version) != version.Hash {
+			problems = append(problems, name+": changed after it was saved")
+		}
+		if version.PrevHash == "" {
+			if i > 0 && versions[i-1].Hash != "" {
+				problems = append(problems, name+": starts a new chain after chained versions")
+			}
+			continue
+		}
+		if i == 0 {
+			// The history starts after versions that are no longer kept
+			continue
+		}
+		prev := versions[i-1].Hash
+		for _, pruned := range version.Pruned {
+			if pruned.PrevHash != prev {
+				break
+			}
+			prev = pruned.Hash
+		}
+		if prev != version.PrevHash {
+			problems = append(problems, name+": does not follow the version before it, so versions were removed without the retention policy recording it, or reordered")
+		}
+	}
+	return chained, problems
+}
+
+// prunedCount is the number of chained versions the retention policy
+// removed between versions
+func prunedCount(versions []Version) int {
+	count := 0
+	for _, version := range versions {
+		count += len(version.Pruned)
+	}
+	return count
+}
+
+// chainReport describes the outcome of verifying versions
+func chainReport(versions []Version) (string, bool) {
+	chained, problems := verifyVersionChain(versions)
+	if chained == 0 {
+		return fmt.Sprintf("None of the %d versions are chained. Turn on the hash chain in Settings to record new versions with hashes.", len(versions)), len(problems) == 0
+	}
+	head := ""
+	for _, version := range versions {
+		if version.Hash != "" {
+			head = version.Hash
+		}
+	}
+	if len(problems) == 0 {
+		report := fmt.Sprintf("%d of %d versions are chained and intact.", chained, len(versions))
+		if pruned := prunedCount(versions); pruned > 0 {
+			report += fmt.Sprintf(" %s removed by the retention policy, the chain runs through their recorded hashes.", plural(pruned, "version"))
+		}
+		return report + fmt.Sprintf("\nLatest hash: %s\nRecord it elsewhere to prove the history up to now later.", head), true
+	}
+	return fmt.Sprintf("%d of %d versions are chained, with problems:\n• %s", chained, len(versions), strings.Join(problems, "\n• ")), false
+}
+
+// verifyHistory checks the hash chain of the versions of the canvas
+func (c *Canvas) verifyHistory() {
+	if len(c.versions) == 0 {
+		dialog.ShowInformation("Verify History", "No previous versions found", c.window)
+		return
+	}
+	report, _ := chainReport(c.versions)
+	label := widget.NewLabel(report)
+	label.Wrapping = fyne.TextWrapWord
+	dialog.ShowCustom("Verify History", "Close", label, c.window)
+}
+
+// hashChainSetting turns hash chaining of new versions on and off
+func (c *Canvas) hashChainSetting() *widget.Check {
+	check := widget.NewCheck("Chain version hashes to make tampering detectable", func(checked bool) {
+		c.prefs.SetBool(prefHashChain, checked)
+	})
+	check.SetChecked(c.prefs.Bool(prefHashChain))
+	return check
+}
+
+// readVersionHistory reads the versions of an exported history: a folder of
+// version files from File > Export Version History, a JSON array of versions such
+// as the changelog of a data room or the version chain of a canvas file,
+// oldest first
+func readVersionHistory(path string) ([]Version, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var versions []Version
+	if !info.IsDir() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(content, &versions); err != nil {
+			data, canvasErr := readCanvasData(bytes.NewReader(content))
+			if canvasErr != nil {
+				return nil, fmt.Errorf("%s: not a list of versions or a canvas: %w", path, err)
+			}
+			versions = data.VersionChain
+		}
+	} else {
+		files, err := filepath.Glob(filepath.Join(path, "canvas-*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			var version Version
+			if err := json.Unmarshal(content, &version); err != nil {
+				return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+			}
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no versions found in %s", path)
+	}
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i].Timestamp.Before(versions[j].Timestamp) })
+	return versions, nil
+}
+
+// runVerify verifies the hash chain of an exported version history:
+//
+//	business-canvas verify ./history
+func runVerify(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("give the folder of an exported history or a JSON file of versions")
+	}
+	versions, err := readVersionHistory(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(versions) > 0 {
+		applyDateDisplay(versions[len(versions)-1].Data.DateDisplay)
+	}
+	report, ok := chainReport(versions)
+	fmt.Println(report)
+	if !ok {
+		return errors.New("the history failed verification")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// chainedHistory saves n chained versions, step apart from start
+func chainedHistory(n int, start time.Time, step time.Duration) []Version {
+	var versions []Version
+	for i := 0; i < n; i++ {
+		version := Version{
+			ID:        fmt.Sprint(i),
+			Timestamp: start.Add(time.Duration(i) * step),
+			Author:    "Ana",
+			Data:      CanvasData{FormatVersion: canvasFormatVersion, ValueProposition: fmt.Sprintf("version %d", i)},
+		}
+		chainVersion(&version, versions)
+		versions = append(versions, version)
+	}
+	return versions
+}
+
+func TestVersionChainVerifies(t *testing.T) {
+	versions := chainedHistory(4, time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC), time.Minute)
+	chained, problems := verifyVersionChain(versions)
+	if chained != 4 || len(problems) > 0 {
+		t.Errorf("got %d chained with problems %v, want 4 intact", chained, problems)
+	}
+}
+
+func TestVersionChainDetectsTampering(t *testing.T) {
+	tamper := map[string]func([]Version) []Version{
+		"changed content": func(versions []Version) []Version {
+			versions[1].Data.ValueProposition = "rewritten"
+			return versions
+		},
+		"changed author": func(versions []Version) []Version {
+			versions[2].Author = "Mallory"
+			return versions
+		},
+		"removed version": func(versions []Version) []Version {
+			return append(versions[:1], versions[2:]...)
+		},
+		"reordered versions": func(versions []Version) []Version {
+			versions[1], versions[2] = versions[2], versions[1]
+			return versions
+		},
+		"rehashed version": func(versions []Version) []Version {
+			versions[1].Data.ValueProposition = "rewritten"
+			versions[1].Hash = versionHash(versions[1])
+			return versions
+		},
+		"unchained version": func(versions []Version) []Version {
+			versions[3].Hash = ""
+			versions[3].PrevHash = ""
+			return append(versions, chainedHistory(1, versions[3].Timestamp.Add(time.Minute), time.Minute)...)
+		},
+	}
+	for name, change := range tamper {
+		versions := change(chainedHistory(4, time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC), time.Minute))
+		if _, problems := verifyVersionChain(versions); len(problems) == 0 {
+			t.Errorf("%s: the chain still verifies", name)
+		}
+	}
+}
+
+func TestVersionChainSurvivesCanvasFile(t *testing.T) {
+	versions := chainedHistory(3, time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC), time.Hour)
+	versions = pruneVersions(versions, versions[2].Timestamp, retentionPolicy{MaxVersions: 2})
+	content, err := json.Marshal(CanvasData{FormatVersion: canvasFormatVersion, VersionChain: versions})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := readCanvasData(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.VersionChain) != 2 || prunedCount(data.VersionChain) != 1 {
+		t.Fatalf("read %d versions with %d pruned, want 2 with 1 pruned", len(data.VersionChain), prunedCount(data.VersionChain))
+	}
+	if _, problems := verifyVersionChain(data.VersionChain); len(problems) > 0 {
+		t.Errorf("the chain read from the file does not verify: %v", problems)
+	}
+
+	data.VersionChain[1].Data.Channels = "changed in the file"
+	if _, problems := verifyVersionChain(data.VersionChain); len(problems) == 0 {
+		t.Error("a version changed in the file still verifies")
+	}
+}
+
+func TestChainedVersions(t *testing.T) {
+	versions := chainedHistory(2, time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC), time.Minute)
+	versions = append([]Version{{ID: "unchained"}}, versions...)
+	if chained := chainedVersions(versions); len(chained) != 2 || chained[0].ID != "0" {
+		t.Errorf("chainedVersions kept %d versions, want the 2 chained ones", len(chained))
+	}
+}
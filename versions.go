@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// label describes a version in the history list
+func (v Version) label() string {
+	label := formatStampSeconds(v.Timestamp)
+	if v.Name != "" {
+		label += " — " + v.Name
+	}
+	if len(v.Tags) > 0 {
+		label += " [" + strings.Join(v.Tags, ", ") + "]"
+	}
+	if v.Author != "" {
+		label += " by " + v.Author
+	}
+	if v.Branch != "" {
+		label += " on " + v.Branch
+	}
+	if len(v.Attendance) > 0 {
+		label += fmt.Sprintf(" (%d attendees)", len(v.Attendance))
+	}
+	if note, _, _ := strings.Cut(strings.TrimSpace(v.Note), "\n"); note != "" {
+		label += ": " + note
+	}
+	return label
+}
+
+// matches reports whether the version name, a tag, the change note or the
+// timestamp contains the search text, ignoring case
+func (v Version) matches(search string) bool {
+	search = strings.ToLower(strings.TrimSpace(search))
+	if search == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(v.label()), search) ||
+		strings.Contains(strings.ToLower(v.Note), search)
+}
+
+// parseTags splits comma separated tags, dropping empty and repeated tags
+func parseTags(text string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(text, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// showSaveVersion saves a version of the current canvas with a name, tags
+// and a note on what changed entered by the user, calling saved afterwards
+func (c *Canvas) showSaveVersion(saved func()) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("e.g. Post-workshop v2")
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetPlaceHolder("Comma separated, e.g. workshop, investor")
+	noteEntry := widget.NewMultiLineEntry()
+	noteEntry.SetPlaceHolder("Optional, e.g. Narrowed segments after customer interviews")
+	noteEntry.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Tags", tagsEntry),
+		widget.NewFormItem("What changed", noteEntry),
+	}
+	form := dialog.NewForm("Save Version", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		c.saveCurrentVersion()
+		version := &c.versions[len(c.versions)-1]
+		version.Name = strings.TrimSpace(nameEntry.Text)
+		version.Tags = parseTags(tagsEntry.Text)
+		version.Note = strings.TrimSpace(noteEntry.Text)
+		if saved != nil {
+			saved()
+		}
+	}, c.window)
+	form.Resize(fyne.NewSize(450, 0))
+	form.Show()
+}
+
+// showSideBySide shows two versions rendered as read-only canvases next to
+// each other, to review how the model evolved between milestones
+func (c *Canvas) showSideBySide() {
+	if len(c.versions) == 0 {
+		dialog.ShowInformation("Side by Side", "No previous versions found", c.window)
+		return
+	}
+
+	// The current canvas can be compared against any version
+	const current = "Current canvas"
+	states := map[string]CanvasData{current: c.getCurrentData()}
+	options := []string{current}
+	for i := len(c.versions) - 1; i >= 0; i-- {
+		label := c.versions[i].label()
+		states[label] = c.versions[i].Data
+		options = append(options, label)
+	}
+
+	pane := func(selected string) fyne.CanvasObject {
+		view := container.NewStack()
+		choose := widget.NewSelect(options, func(label string) {
+			view.Objects = []fyne.CanvasObject{canvasFrameView(states[label])}
+			view.Refresh()
+		})
+		choose.SetSelected(selected)
+		return container.NewBorder(choose, nil, nil, nil, view)
+	}
+	left := pane(options[1])
+	right := pane(current)
+
+	window := fyne.CurrentApp().NewWindow("Side by Side")
+	window.SetContent(container.NewHSplit(left, right))
+	window.Resize(fyne.NewSize(1600, 800))
+	window.Show()
+}
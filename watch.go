@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"time"
+)
+
+// fileStamp identifies a version of a file on disk by its modification
+// time and size
+type fileStamp struct {
+	ModTime time.Time
+	Size    int64
+}
+
+func statFile(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{ModTime: info.ModTime(), Size: info.Size()}, nil
+}
+
+// watchFile polls a file and calls changed whenever it is modified until
+// stop is closed. Polling also notices files replaced by editors, sync
+// clients and Git checkouts, and missing files are picked up once they
+// appear again.
+func watchFile(path string, interval time.Duration, stop <-chan struct{}, changed func()) {
+	last, _ := statFile(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			stamp, err := statFile(path)
+			if err != nil || stamp == last {
+				continue
+			}
+			last = stamp
+			changed()
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// prefPDFWordCloud adds a word cloud page to PDF exports
+const prefPDFWordCloud = "pdfWordCloud"
+
+// wordCloudSize is the number of words shown in a word cloud
+const wordCloudSize = 40
+
+// stopwords are common English words left out of the word cloud
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "all": true, "any": true, "can": true, "her": true, "was": true,
+	"one": true, "our": true, "out": true, "his": true, "has": true, "had": true,
+	"how": true, "its": true, "who": true, "did": true, "yes": true, "she": true,
+	"him": true, "they": true, "them": true, "their": true, "this": true,
+	"that": true, "these": true, "those": true, "with": true, "from": true,
+	"into": true, "onto": true, "than": true, "then": true, "there": true,
+	"here": true, "have": true, "been": true, "being": true, "were": true,
+	"will": true, "would": true, "should": true, "could": true, "what": true,
+	"which": true, "when": true, "where": true, "while": true, "your": true,
+	"about": true, "also": true, "each": true, "more": true, "most": true,
+	"other": true, "some": true, "such": true, "only": true, "very": true,
+	"just": true, "over": true, "under": true, "through": true, "via": true,
+	"per": true, "does": true, "doing": true, "done": true, "because": true,
+	"both": true, "between": true, "after": true, "before": true, "again": true,
+	"own": true, "same": true, "too": true, "may": true, "might": true,
+	"must": true, "shall": true, "etc": true, "use": true, "using": true,
+}
+
+// cloudWord is a word of the cloud with its number of occurrences
+type cloudWord struct {
+	Word  string
+	Count int
+}
+
+// wordCloud counts the words of all canvas sections, stopwords removed,
+// returning the most frequent words first
+func wordCloud(data CanvasData) []cloudWord {
+	counts := make(map[string]int)
+	for _, section := range data.sections() {
+		words := strings.FieldsFunc(strings.ToLower(section.Text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+		})
+		for _, word := range words {
+			word = strings.Trim(word, "-")
+			if len([]rune(word)) < 3 || stopwords[word] {
+				continue
+			}
+			counts[word]++
+		}
+	}
+
+	var words []cloudWord
+	for word, count := range counts {
+		words = append(words, cloudWord{word, count})
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Count != words[j].Count {
+			return words[i].Count > words[j].Count
+		}
+		return words[i].Word < words[j].Word
+	})
+	if len(words) > wordCloudSize {
+		words = words[:wordCloudSize]
+	}
+	return words
+}
+
+// wordScale maps a word count to a value between 0 and 1 within the cloud
+func wordScale(words []cloudWord, count int) float64 {
+	most, least := words[0].Count, words[len(words)-1].Count
+	if most == least {
+		return 0.5
+	}
+	return float64(count-least) / float64(most-least)
+}
+
+// flowLayout places objects left to right at their minimum size, wrapping
+// onto new rows
+type flowLayout struct{}
+
+func (flowLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	padding := theme.Padding()
+	x, y, rowHeight := float32(0), float32(0), float32(0)
+	for _, object := range objects {
+		min := object.MinSize()
+		if x > 0 && x+min.Width > size.Width {
+			x, y, rowHeight = 0, y+rowHeight+padding, 0
+		}
+		object.Move(fyne.NewPos(x, y))
+		object.Resize(min)
+		x += min.Width + padding
+		if min.Height > rowHeight {
+			rowHeight = min.Height
+		}
+	}
+}
+
+func (flowLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	var min fyne.Size
+	for _, object := range objects {
+		min = min.Max(object.MinSize())
+	}
+	return min
+}
+
+// showWordCloud opens a window with a word cloud that updates as the
+// canvas is edited
+func (c *Canvas) showWordCloud() {
+	if c.wordCloud != nil {
+		return
+	}
+
+	window := fyne.CurrentApp().NewWindow("Word Cloud")
+	c.wordCloud = container.New(flowLayout{})
+	c.refreshWordCloud()
+	window.SetContent(container.NewVScroll(container.NewPadded(c.wordCloud)))
+	window.SetOnClosed(func() {
+		c.wordCloud = nil
+	})
+	window.Resize(fyne.NewSize(600, 400))
+	window.Show()
+}
+
+// refreshWordCloud redraws an open word cloud window
+func (c *Canvas) refreshWordCloud() {
+	if c.wordCloud == nil {
+		return
+	}
+
+	words := wordCloud(c.getCurrentData())
+	c.wordCloud.Objects = nil
+	if len(words) == 0 {
+		c.wordCloud.Add(widget.NewLabel("Add content to the canvas to see its dominant themes"))
+	}
+	for _, word := range words {
+		scale := wordScale(words, word.Count)
+		color := theme.Color(theme.ColorNameForeground)
+		if scale > 0.66 {
+			color = theme.Color(theme.ColorNamePrimary)
+		}
+		text := canvas.NewText(word.Word, color)
+		text.TextSize = float32(12 + 28*scale)
+		text.TextStyle.Bold = scale > 0.33
+		c.wordCloud.Add(text)
+	}
+	c.wordCloud.Refresh()
+}
+
+// drawWordCloudPage adds a page with the word cloud of the canvas
+func drawWordCloudPage(pdf *gofpdf.Fpdf, data CanvasData) {
+	words := wordCloud(data)
+	if len(words) == 0 {
+		return
+	}
+
+	pdf.AddPage()
+	left, top, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	width := pageWidth - left - right
+
+	pdf.SetFont(pdfFontFamily, "B", 20)
+	pdf.SetXY(left, top)
+	pdf.CellFormat(width, 12, "Word Cloud", "", 1, "L", false, 0, "")
+
+	// Flow the words left to right, wrapping at the right margin
+	x, y := left, top+20
+	rowHeight := 0.0
+	for _, word := range words {
+		scale := wordScale(words, word.Count)
+		size := 14 + 34*scale
+		style := ""
+		if scale > 0.33 {
+			style = "B"
+		}
+		pdf.SetFont(pdfFontFamily, style, size)
+		_, lineHeight := pdf.GetFontSize()
+		wordWidth := pdf.GetStringWidth(word.Word) + 6
+		if x > left && x+wordWidth > left+width {
+			x, y, rowHeight = left, y+rowHeight+4, 0
+		}
+		if lineHeight > rowHeight {
+			rowHeight = lineHeight
+		}
+		pdf.SetXY(x, y)
+		pdf.CellFormat(wordWidth, lineHeight, word.Word, "", 0, "L", false, 0, "")
+		x += wordWidth
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Length rules count words rather than characters, so neither letters that
+// take several bytes nor stray whitespace decide whether a section passes.
+// Settings override the minimum of the built-in rules and add a minimum or
+// a maximum for any section.
+
+// Preference key prefixes of the word limits of each section
+const (
+	prefWordMinPrefix = "wordMin."
+	prefWordMaxPrefix = "wordMax."
+)
+
+// wordCount counts the words of a text
+func wordCount(text string) int {
+	return len(strings.Fields(text))
+}
+
+// wordRule is a built-in rule asking for at least minWords words in the
+// text of a section, unless Settings set another minimum
+func wordRule(section string, minWords int, message string, text func(*Canvas) string) ValidationRule {
+	return ValidationRule{
+		Section:  section,
+		Message:  message,
+		minWords: minWords,
+		Check: func(c *Canvas) bool {
+			return wordCount(text(c)) >= c.prefs.IntWithFallback(prefWordMinPrefix+section, minWords)
+		},
+	}
+}
+
+// defaultWordMinimums are the minimums of the built-in word rules of a
+// validator by section
+func defaultWordMinimums(validator *BusinessValidator) map[string]int {
+	minimums := make(map[string]int)
+	for _, rule := range validator.rules {
+		if rule.minWords > 0 {
+			minimums[rule.Section] = rule.minWords
+		}
+	}
+	return minimums
+}
+
+// wordLimitRules are the rules of the word limits set in Settings that the
+// built-in rules of the validator do not check
+func (c *Canvas) wordLimitRules(builtIn *BusinessValidator) []ValidationRule {
+	defaults := defaultWordMinimums(builtIn)
+	var rules []ValidationRule
+	for _, section := range c.getCurrentData().sections() {
+		title := section.Title
+		if minWords := c.prefs.Int(prefWordMinPrefix + title); minWords > 0 && defaults[title] == 0 {
+			rules = append(rules, ValidationRule{
+				Section: title,
+				Message: fmt.Sprintf("Write at least %d words", minWords),
+				Check: func(c *Canvas) bool {
+					entry := c.sectionEntry(title)
+					return entry == nil || wordCount(entry.Text) >= minWords
+				},
+			})
+		}
+		if maxWords := c.prefs.Int(prefWordMaxPrefix + title); maxWords > 0 {
+			rules = append(rules, ValidationRule{
+				Section: title,
+				Message: fmt.Sprintf("Keep it to %d words", maxWords),
+				Check: func(c *Canvas) bool {
+					entry := c.sectionEntry(title)
+					return entry == nil || wordCount(entry.Text) <= maxWords
+				},
+			})
+		}
+	}
+	return rules
+}
+
+// showWordLimits edits the minimum and maximum word count of each section
+func (c *Canvas) showWordLimits() {
+	defaults := defaultWordMinimums(findCanvasType(c.canvasTypeID).NewValidator())
+	wordEntry := func(key, placeholder string) *widget.Entry {
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder(placeholder)
+		if value, ok := c.wordLimit(key); ok {
+			entry.SetText(strconv.Itoa(value))
+		}
+		entry.Validator = func(text string) error {
+			if text = strings.TrimSpace(text); text == "" {
+				return nil
+			}
+			if words, err := strconv.Atoi(text); err != nil || words < 0 {
+				return fmt.Errorf("enter a number of words")
+			}
+			return nil
+		}
+		return entry
+	}
+
+	var items []*widget.FormItem
+	entries := make(map[string]*widget.Entry)
+	for _, section := range c.getCurrentData().sections() {
+		minPlaceholder := "no minimum"
+		if words := defaults[section.Title]; words > 0 {
+			minPlaceholder = fmt.Sprintf("at least %d (default)", words)
+		}
+		minEntry := wordEntry(prefWordMinPrefix+section.Title, minPlaceholder)
+		maxEntry := wordEntry(prefWordMaxPrefix+section.Title, "no maximum")
+		entries[prefWordMinPrefix+section.Title] = minEntry
+		entries[prefWordMaxPrefix+section.Title] = maxEntry
+		items = append(items, widget.NewFormItem(section.Title, container.NewGridWithColumns(2, minEntry, maxEntry)))
+	}
+
+	form := dialog.NewForm("Word Limits (minimum, maximum)", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		for key, entry := range entries {
+			text := strings.TrimSpace(entry.Text)
+			if text == "" {
+				c.prefs.RemoveValue(key)
+				continue
+			}
+			if words, err := strconv.Atoi(text); err == nil {
+				c.prefs.SetInt(key, words)
+			}
+		}
+		c.loadValidator()
+		c.updateProgress()
+	}, c.window)
+	form.Resize(fyne.NewSize(500, 0))
+	form.Show()
+}
+
+// wordLimit is a word limit set in Settings
+func (c *Canvas) wordLimit(key string) (int, bool) {
+	value := c.prefs.IntWithFallback(key, -1)
+	return value, value >= 0
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// workspaceStore keeps canvases in an organization workspace on the
+// self-hosted server
+type workspaceStore struct {
+	URL   string
+	Org   string
+	Token string
+}
+
+// request builds a request for a path below the organization
+func (s workspaceStore) request(method, path string, body []byte) (*http.Request, error) {
+	target := strings.TrimSuffix(s.URL, "/") + "/api/orgs/" + url.PathEscape(s.Org) + path
+	req, err := http.NewRequest(method, target, strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+	return req, nil
+}
+
+// getJSON reads a JSON answer from a path below the organization
+func (s workspaceStore) getJSON(path string, value any) error {
+	req, err := s.request(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	body, err := doRemote(req)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, value)
+}
+
+func (s workspaceStore) Put(name string, content []byte) error {
+	req, err := s.request(http.MethodPut, "/canvases/"+url.PathEscape(name), content)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	_, err = doRemote(req)
+	return err
+}
+
+// Patch applies a JSON Patch document to a canvas, answering the version
+// the server stored it as
+func (s workspaceStore) Patch(name string, patch []byte) (int, error) {
+	req, err := s.request(http.MethodPatch, "/canvases/"+url.PathEscape(name), patch)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json-patch+json")
+	resp, err := remoteClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	// The server explains why a patch was refused
+	if resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("server responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	var answer struct {
+		Version int `json:"version"`
+	}
+	err = json.Unmarshal(body, &answer)
+	return answer.Version, err
+}
+
+func (s workspaceStore) Get(name string) ([]byte, error) {
+	req, err := s.request(http.MethodGet, "/canvases/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	return doRemote(req)
+}
+
+func (s workspaceStore) List() ([]string, error) {
+	var names []string
+	err := s.getJSON("/canvases", &names)
+	return names, err
+}
+
+// collabURL is the address of the collaboration session of a canvas
+func (s workspaceStore) collabURL(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("enter the name of the canvas")
+	}
+	base, ok := strings.CutPrefix(strings.TrimSuffix(s.URL, "/"), "http")
+	if !ok {
+		return "", fmt.Errorf("the workspace server %q is not an http:// or https:// address", s.URL)
+	}
+	return "ws" + base + "/api/orgs/" + url.PathEscape(s.Org) + "/canvases/" + url.PathEscape(name) + "/collab", nil
+}
+
+// dashboardURL is the portfolio dashboard of the organization, which asks
+// the browser to sign in with the token or the identity provider
+func (s workspaceStore) dashboardURL() string {
+	return strings.TrimSuffix(s.URL, "/") + "/orgs/" + url.PathEscape(s.Org) + "/"
+}
+
+// workspace returns the organization workspace canvases are kept in, nil
+// when the remote storage is something else
+func (c *Canvas) workspace() *workspaceStore {
+	store, ok := c.configuredStore().(workspaceStore)
+	if !ok {
+		dialog.ShowInformation("Workspace", "Choose Workspace in Tools > Remote Storage... and enter the server, organization and your member token", c.window)
+		return nil
+	}
+	return &store
+}
+
+// syncWorkspaceLibraries adds the shared templates of the workspace as
+// canvas types and installs its shared rule packs. It runs on the UI
+// thread, which every reader of the canvas types is on.
+func (c *Canvas) syncWorkspaceLibraries() {
+	store := c.workspace()
+	if store == nil {
+		return
+	}
+	var templateNames, packNames []string
+	if err := store.getJSON("/templates", &templateNames); err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	if err := store.getJSON("/rulepacks", &packNames); err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+
+	var problems []error
+	for _, name := range templateNames {
+		var raw json.RawMessage
+		err := store.getJSON("/templates/"+url.PathEscape(name), &raw)
+		var template CanvasTemplate
+		if err == nil {
+			template, err = parseCanvasTemplate(name+".json", raw)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Errorf("template %s: %w", name, err))
+			continue
+		}
+		addCanvasType(template.canvasType())
+	}
+
+	packs := c.loadRulePacks()
+	for _, name := range packNames {
+		var pack RulePack
+		if err := store.getJSON("/rulepacks/"+url.PathEscape(name), &pack); err != nil {
+			problems = append(problems, fmt.Errorf("rule pack %s: %w", name, err))
+			continue
+		}
+		pack.Source = "workspace " + store.Org
+		packs, _ = installRulePack(packs, pack)
+	}
+	c.saveRulePacks(packs)
+
+	if err := errors.Join(problems...); err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	dialog.ShowInformation("Success", fmt.Sprintf("%d templates and %d rule packs synced from the workspace", len(templateNames), len(packNames)), c.window)
+}
+
+// addCanvasType adds a canvas type, replacing the type of the same ID. Only
+// the UI thread may call it.
+func addCanvasType(kind *canvasType) {
+	for i, existing := range canvasTypes {
+		if existing.ID == kind.ID {
+			canvasTypes[i] = kind
+			return
+		}
+	}
+	canvasTypes = append(canvasTypes, kind)
+}
+
+// openWorkspaceDashboard shows the portfolio dashboard of the workspace in
+// the browser
+func (c *Canvas) openWorkspaceDashboard() {
+	store := c.workspace()
+	if store == nil {
+		return
+	}
+	dashboard, err := url.Parse(store.dashboardURL())
+	if err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	fyne.CurrentApp().OpenURL(dashboard)
+}
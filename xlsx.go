@@ -0,0 +1,375 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// xlsxSheet is a worksheet as rows of plain text cells
+type xlsxSheet struct {
+	Name string
+	Rows [][]string
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+%s</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+// xlsxStyles defines a default style and a wrapped, top-aligned style used
+// for every cell so multi-line section content stays readable
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0" applyAlignment="1"><alignment wrapText="1" vertical="top"/></xf></cellXfs>
+</styleSheet>`
+
+func (c *Canvas) exportToXLSX() {
+	data := c.getCurrentData()
+	versions := c.versions
+
+	dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		err = writeXLSX(writer, canvasWorkbook(data, versions))
+		if err != nil {
+			dialog.ShowError(err, c.window)
+			return
+		}
+
+		dialog.ShowInformation("Success", "Workbook has been exported successfully", c.window)
+	}, c.window)
+}
+
+// canvasWorkbook builds a grid sheet mirroring the canvas layout, one
+// detail sheet per section and a sheet of version metadata
+func canvasWorkbook(data CanvasData, versions []Version) []xlsxSheet {
+	grid := xlsxSheet{Name: "Canvas", Rows: xlsxLayoutRows(data.canvasType().Layout, data.sections())}
+	if len(data.CustomSections) > 0 {
+		var titles, texts []string
+		for _, custom := range data.CustomSections {
+			titles = append(titles, custom.Title)
+			texts = append(texts, custom.Text)
+		}
+		grid.Rows = append(grid.Rows, titles, texts)
+	}
+	sheets := []xlsxSheet{grid}
+
+	for _, section := range data.sections() {
+		sheet := xlsxSheet{Name: section.Title, Rows: [][]string{{section.Title}}}
+		for _, line := range strings.Split(section.Text, "\n") {
+			if strings.TrimSpace(line) != "" {
+				sheet.Rows = append(sheet.Rows, []string{line})
+			}
+		}
+		sheets = append(sheets, sheet)
+	}
+
+	meta := xlsxSheet{
+		Name: "Versions",
+		Rows: [][]string{
+			{"Exported", time.Now().Format("2006-01-02 15:04:05")},
+			{"Versions", fmt.Sprintf("%d", len(versions))},
+			{},
+			{"ID", "Saved", "Comments"},
+		},
+	}
+	for _, version := range versions {
+		meta.Rows = append(meta.Rows, []string{
+			version.ID,
+			version.Timestamp.Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%d", len(version.Comments)),
+		})
+	}
+	sheets = append(sheets, meta)
+
+	return sheets
+}
+
+// xlsxLayoutRows places each section title above its text, with blocks
+// starting on the same layout row sharing a pair of sheet rows and columns
+// sized by the narrowest block
+func xlsxLayoutRows(layout canvasLayout, sections []sectionContent) [][]string {
+	minSpan := layout.Columns
+	var starts []int
+	for _, block := range layout.Blocks {
+		if block.ColSpan < minSpan {
+			minSpan = block.ColSpan
+		}
+		if !slices.Contains(starts, block.Row) {
+			starts = append(starts, block.Row)
+		}
+	}
+	slices.Sort(starts)
+
+	rows := make([][]string, 2*len(starts))
+	for i, block := range layout.Blocks {
+		if i >= len(sections) {
+			break
+		}
+		r := 2 * slices.Index(starts, block.Row)
+		col := block.Col / minSpan
+		for len(rows[r]) <= col {
+			rows[r] = append(rows[r], "")
+			rows[r+1] = append(rows[r+1], "")
+		}
+		rows[r][col] = sections[i].Title
+		rows[r+1][col] = sections[i].Text
+	}
+	return rows
+}
+
+// writeXLSX writes a minimal Office Open XML workbook using inline strings
+func writeXLSX(w io.Writer, sheets []xlsxSheet) error {
+	archive := zip.NewWriter(w)
+
+	var overrides, workbookSheets, workbookRels strings.Builder
+	used := make(map[string]bool)
+	for i, sheet := range sheets {
+		sheet.Name = xlsxSheetName(sheet.Name, used)
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", i+1)
+		fmt.Fprintf(&workbookSheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(sheet.Name), i+1, i+1)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`+"\n", i+1, i+1)
+	}
+	stylesID := len(sheets) + 1
+	fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`+"\n", stylesID)
+
+	files := map[string]string{
+		"[Content_Types].xml": fmt.Sprintf(xlsxContentTypes, overrides.String()),
+		"_rels/.rels":         xlsxRootRels,
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` + workbookSheets.String() + `</sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+` + workbookRels.String() + `</Relationships>`,
+		"xl/styles.xml": xlsxStyles,
+	}
+	for i, sheet := range sheets {
+		files[fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)] = xlsxSheetXML(sheet)
+	}
+
+	// [Content_Types].xml must come first for some readers
+	order := []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"}
+	for i := range sheets {
+		order = append(order, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+	}
+	for _, name := range order {
+		f, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, files[name]); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func xlsxSheetXML(sheet xlsxSheet) string {
+	columns := 5
+	for _, row := range sheet.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<cols><col min="1" max="%d" width="40" customWidth="1"/></cols>
+<sheetData>`, columns)
+	for r, row := range sheet.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for col, value := range row {
+			if value == "" {
+				continue
+			}
+			fmt.Fprintf(&b, `<c r="%s%d" s="1" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+				xlsxColumn(col), r+1, xmlEscape(value))
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// xlsxCell is a worksheet cell holding a shared, inline or plain value
+type xlsxCell struct {
+	Ref        string   `xml:"r,attr"`
+	Type       string   `xml:"t,attr"`
+	Value      string   `xml:"v"`
+	Inline     string   `xml:"is>t"`
+	InlineRuns []string `xml:"is>r>t"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []xlsxCell `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string   `xml:"t"`
+		Runs []string `xml:"r>t"`
+	} `xml:"si"`
+}
+
+// readXLSXRows reads the cell text of the first worksheet of a workbook,
+// row by row
+func readXLSXRows(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	var sheet *zip.File
+	for _, f := range archive.File {
+		switch f.Name {
+		case "xl/sharedStrings.xml":
+			var sst xlsxSharedStrings
+			if err := decodeZipXML(f, &sst); err != nil {
+				return nil, err
+			}
+			for _, item := range sst.Items {
+				shared = append(shared, item.Text+strings.Join(item.Runs, ""))
+			}
+		case "xl/worksheets/sheet1.xml":
+			sheet = f
+		}
+	}
+	if sheet == nil {
+		return nil, errors.New("workbook has no worksheet")
+	}
+
+	var ws xlsxWorksheet
+	if err := decodeZipXML(sheet, &ws); err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	for _, row := range ws.Rows {
+		var values []string
+		for i, cell := range row.Cells {
+			col := xlsxColumnIndex(cell.Ref)
+			if col < 0 {
+				col = i
+			}
+			for len(values) <= col {
+				values = append(values, "")
+			}
+			switch cell.Type {
+			case "s":
+				var index int
+				if _, err := fmt.Sscan(cell.Value, &index); err == nil && index < len(shared) {
+					values[col] = shared[index]
+				}
+			case "inlineStr":
+				values[col] = cell.Inline + strings.Join(cell.InlineRuns, "")
+			default:
+				values[col] = cell.Value
+			}
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+func decodeZipXML(f *zip.File, v interface{}) error {
+	reader, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return xml.NewDecoder(reader).Decode(v)
+}
+
+// xlsxSheetName makes a title a valid, unique worksheet name: at most 31
+// characters and none of the characters Excel reserves
+func xlsxSheetName(title string, used map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, title)
+	if name == "" {
+		name = "Sheet"
+	}
+
+	candidate := name
+	for i := 2; ; i++ {
+		if len([]rune(candidate)) > 31 {
+			candidate = string([]rune(candidate)[:31])
+		}
+		if !used[strings.ToLower(candidate)] {
+			break
+		}
+		suffix := fmt.Sprintf(" (%d)", i)
+		runes := []rune(name)
+		if len(runes)+len(suffix) > 31 {
+			runes = runes[:31-len(suffix)]
+		}
+		candidate = string(runes) + suffix
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// xlsxColumn converts a zero-based column index to its letter name
+func xlsxColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxColumnIndex converts the letters of a cell reference such as "B3" to
+// a zero-based column index, -1 when there are none
+func xlsxColumnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A') + 1
+	}
+	return index - 1
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}